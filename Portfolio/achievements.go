@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/ssh"
+)
+
+type achievement struct {
+	id          string
+	icon        string
+	name        string
+	description string
+}
+
+// achievements are listed by the achievements command in this order.
+var achievements = []achievement{
+	{"first-command", "👣", "First steps", "Run your first command"},
+	{"plumber", "🔧", "Plumber", "Pipe one command into another"},
+	{"egg-hunter", "🥚", "Egg hunter", "Find a hidden easter egg"},
+	{"hiring-manager", "📄", "Hiring manager", "Read the whole resume"},
+	{"snake-charmer", "🐍", "Snake charmer", "Score 10 points at snake"},
+	{"know-it-all", "🧠", "Know-it-all", "Get every question in a quiz right"},
+	{"speed-typist", "⌨️", "Speed typist", "Type at 60 WPM or faster"},
+	{"completionist", "🏆", "Completionist", "Run every command"},
+}
+
+// progress is what a visitor has unlocked, kept across sessions for SSH
+// keys. It's shared by pointer so every copy of the model sees the same.
+type progress struct {
+	Unlocked map[string]time.Time `json:"unlocked"`
+	Used     map[string]bool      `json:"used"` // commands run, for the completionist
+
+	path    string   // where it's saved, empty to keep it for the session only
+	pending []string // unlocked since they were last announced
+}
+
+// achievementsDir returns the directory progress files are stored in.
+func achievementsDir() string {
+	return filepath.Join(dataDir(), "achievements")
+}
+
+// localProgressPath is the progress file used when running without the server.
+func localProgressPath() string {
+	return filepath.Join(achievementsDir(), "local")
+}
+
+// sessionProgressPath keys the progress file on the visitor's public key.
+// Keyboard-interactive logins can share an IP, so they only keep it for the
+// session.
+func sessionProgressPath(s ssh.Session) string {
+	if key := keyID(s); key != "" {
+		return filepath.Join(achievementsDir(), key)
+	}
+	return ""
+}
+
+// loadProgress reads a progress file, starting afresh if there isn't one.
+func loadProgress(path string) *progress {
+	p := &progress{path: path}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err == nil {
+			err = json.Unmarshal(data, p)
+		}
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			log.Error("Could not load achievements", "path", path, "error", err)
+		}
+	}
+	if p.Unlocked == nil {
+		p.Unlocked = map[string]time.Time{}
+	}
+	if p.Used == nil {
+		p.Used = map[string]bool{}
+	}
+	return p
+}
+
+func (p *progress) save() {
+	if p.path == "" {
+		return
+	}
+	data, err := json.Marshal(p)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(p.path), 0o700)
+	}
+	if err == nil {
+		err = os.WriteFile(p.path, data, 0o600)
+	}
+	if err != nil {
+		log.Error("Could not save achievements", "error", err)
+	}
+}
+
+// unlock records an achievement, queueing it to be announced if it's new.
+func (p *progress) unlock(id string) {
+	if _, ok := p.Unlocked[id]; ok {
+		return
+	}
+	p.Unlocked[id] = time.Now()
+	p.pending = append(p.pending, id)
+	p.save()
+}
+
+// completionistCommands are the commands to run for the completionist:
+// everything in help, except exit, which would end the session.
+func completionistCommands() []string {
+	var names []string
+	for _, page := range manPages {
+		if !page.hidden && page.category != "plugins" && page.name != "exit" {
+			names = append(names, page.name)
+		}
+	}
+	return names
+}
+
+// commandRun notes that a visitor ran a command, for the achievements
+// that depend on it.
+func (p *progress) commandRun(name string, piped bool) {
+	p.unlock("first-command")
+	if piped {
+		p.unlock("plumber")
+	}
+	if p.Used[name] {
+		return
+	}
+	p.Used[name] = true
+	p.save()
+	for _, command := range completionistCommands() {
+		if !p.Used[command] {
+			return
+		}
+	}
+	p.unlock("completionist")
+}
+
+// gameOver unlocks the achievements for a good enough score.
+func (p *progress) gameOver(name string, score int) {
+	switch {
+	case name == "snake" && score >= 10:
+		p.unlock("snake-charmer")
+	case name == "quiz" && score == quizLength:
+		p.unlock("know-it-all")
+	case name == "typetest" && score >= 60:
+		p.unlock("speed-typist")
+	}
+}
+
+// announcements returns a line for each achievement unlocked since the last
+// call, or "" if there are none.
+func (p *progress) announcements(t *theme) string {
+	var lines []string
+	for _, id := range p.pending {
+		for _, a := range achievements {
+			if a.id == id {
+				lines = append(lines, t.warning.Bold(true).Render("🏆 Achievement unlocked: "+a.icon+" "+a.name)+t.muted.Render(" ("+a.description+")"))
+			}
+		}
+	}
+	p.pending = nil
+	return strings.Join(lines, "\n")
+}
+
+// announceAchievements adds any newly unlocked achievements to the scrollback.
+func (m *model) announceAchievements() {
+	if text := m.progress.announcements(m.theme); text != "" {
+		m.print(text)
+	}
+}
+
+func achievementsCommand(m *model, in commandInput) (string, tea.Cmd) {
+	th := m.theme
+	p := m.progress
+	var b strings.Builder
+	b.WriteString(th.header.Render(fmt.Sprintf("Achievements: %d of %d unlocked", len(p.Unlocked), len(achievements))) + "\n\n")
+	for _, a := range achievements {
+		description := a.description
+		if a.id == "completionist" {
+			used, all := 0, completionistCommands()
+			for _, name := range all {
+				if p.Used[name] {
+					used++
+				}
+			}
+			description += fmt.Sprintf(" (%d of %d so far)", used, len(all))
+		}
+		if at, ok := p.Unlocked[a.id]; ok {
+			badge := th.style().Bold(true).Foreground(th.colors.onAccent).Background(th.colors.accent).Padding(0, 1).Render(a.icon + " " + a.name)
+			b.WriteString(badge + " " + description + th.subtle.Render(" · "+at.Format("2 Jan 2006")) + "\n")
+		} else {
+			badge := th.muted.Padding(0, 1).Render("🔒 " + a.name)
+			b.WriteString(badge + " " + th.muted.Render(description) + "\n")
+		}
+	}
+	if p.path == "" {
+		b.WriteString("\n" + th.muted.Render("Connect with an SSH key to keep your achievements between visits."))
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// defaultAliases are available to every visitor. The config file can add to
+// or replace them, and visitors can add their own for the session.
+var defaultAliases = map[string]string{
+	"ll":    "ls",
+	"dir":   "ls",
+	"about": "cat About/bio.txt",
+}
+
+// aliasMaxDepth stops aliases that expand to each other from looping forever.
+const aliasMaxDepth = 10
+
+// sessionAliases returns the aliases a new session starts with.
+func sessionAliases() map[string]string {
+	aliases := map[string]string{}
+	for name, value := range defaultAliases {
+		aliases[name] = value
+	}
+	for name, value := range currentConfig().aliases {
+		aliases[name] = value
+	}
+	return aliases
+}
+
+// parseAlias splits a definition like ll="ls" into its name and value.
+func parseAlias(def string) (string, string, error) {
+	name, value, ok := strings.Cut(def, "=")
+	if !ok {
+		return "", "", messageError{id: "alias syntax"}
+	}
+	name = strings.TrimSpace(name)
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		value = value[1 : len(value)-1]
+	}
+
+	switch {
+	case name == "" || strings.ContainsAny(name, " \t|>\"'!\\"):
+		return "", "", messageError{id: "alias name", args: []any{name}}
+	case strings.TrimSpace(value) == "":
+		return "", "", messageError{id: "alias no command", args: []any{name}}
+	}
+	p, err := parsePipeline(value)
+	switch {
+	case err != nil:
+		return "", "", err
+	case p.redirected:
+		return "", "", messageError{id: "alias redirect"}
+	}
+	return name, value, nil
+}
+
+// expandAlias replaces the command name at the start of stage with its
+// alias, repeating while the result starts with another alias.
+func (m model) expandAlias(stage string) string {
+	seen := map[string]bool{}
+	for i := 0; i < aliasMaxDepth; i++ {
+		name, args := splitCommand(stage)
+		value, ok := m.aliases[name]
+		// Built-in commands always win, even over aliases from the config file
+		if _, builtin := commands[name]; builtin || !ok || seen[name] {
+			break
+		}
+		seen[name] = true
+		stage = strings.TrimSpace(value + " " + args)
+	}
+	return stage
+}
+
+// aliasNames returns the names of every alias, sorted.
+func (m model) aliasNames() []string {
+	var names []string
+	for name := range m.aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func aliasCommand(m *model, in commandInput) (string, tea.Cmd) {
+	if in.args == "" {
+		if len(m.aliases) == 0 {
+			return m.tr("alias none"), nil
+		}
+		var b strings.Builder
+		for _, name := range m.aliasNames() {
+			fmt.Fprintf(&b, "alias %s=%q\n", name, m.aliases[name])
+		}
+		return strings.TrimSuffix(b.String(), "\n"), nil
+	}
+
+	if !strings.Contains(in.args, "=") {
+		value, ok := m.aliases[in.args]
+		if !ok {
+			return m.tr("alias not found", in.args), nil
+		}
+		return fmt.Sprintf("alias %s=%q", in.args, value), nil
+	}
+
+	name, value, err := parseAlias(in.args)
+	if err != nil {
+		return "alias: " + m.errorText(err), nil
+	}
+	// Hidden commands are still commands, so check them all, not just the
+	// ones completion offers
+	if slices.Contains(builtinNames, name) || slices.Contains(m.commandautocomplete, name) {
+		return m.tr("alias builtin", name), nil
+	}
+	m.aliases[name] = value
+	return "", nil
+}
+
+func unaliasCommand(m *model, in commandInput) (string, tea.Cmd) {
+	if in.args == "" {
+		return m.tr("unalias usage"), nil
+	}
+	if _, ok := m.aliases[in.args]; !ok {
+		return m.tr("unalias not found", in.args), nil
+	}
+	delete(m.aliases, in.args)
+	return "", nil
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/ssh"
+	_ "modernc.org/sqlite"
+)
+
+// Analytics are only recorded in server mode. The database lives next to the
+// history files, outside the portfolio tree visitors can browse.
+
+const analyticsSchema = `
+CREATE TABLE IF NOT EXISTS sessions (
+	id           INTEGER PRIMARY KEY AUTOINCREMENT,
+	connected_at INTEGER NOT NULL,
+	duration_ms  INTEGER,
+	client_ip    TEXT NOT NULL,
+	term_width   INTEGER,
+	term_height  INTEGER
+);
+CREATE TABLE IF NOT EXISTS commands (
+	session_id INTEGER NOT NULL REFERENCES sessions(id),
+	name       TEXT NOT NULL,
+	run_at     INTEGER NOT NULL
+);`
+
+// analytics stores per-session visitor metrics in SQLite.
+type analytics struct {
+	db *sql.DB
+}
+
+// visitorStats is the analytics store for the running server, nil when the
+// portfolio runs locally or the database couldn't be opened.
+var visitorStats *analytics
+
+func analyticsPath() string {
+	if p := os.Getenv("PORTFOLIO_STATS_DB"); p != "" {
+		return p
+	}
+	return filepath.Join(dataDir(), "analytics.db")
+}
+
+func openAnalytics(path string) (*analytics, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite only allows one writer, so don't let concurrent sessions fight over it
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(analyticsSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &analytics{db: db}, nil
+}
+
+// startSession records a new visitor and returns the session's row id.
+func (a *analytics) startSession(ip string, width, height int) int64 {
+	res, err := a.db.Exec(
+		"INSERT INTO sessions (connected_at, client_ip, term_width, term_height) VALUES (?, ?, ?, ?)",
+		time.Now().Unix(), ip, width, height,
+	)
+	if err != nil {
+		log.Error("Could not record session", "error", err)
+		return 0
+	}
+	id, _ := res.LastInsertId()
+	return id
+}
+
+// endSession stores how long the visitor stayed.
+func (a *analytics) endSession(id int64, connected time.Time) {
+	if _, err := a.db.Exec("UPDATE sessions SET duration_ms = ? WHERE id = ?", time.Since(connected).Milliseconds(), id); err != nil {
+		log.Error("Could not record session end", "error", err)
+	}
+}
+
+// recordCommand stores the name of a command a visitor ran. Arguments are
+// left out on purpose since they may contain anything.
+func (a *analytics) recordCommand(sessionID int64, name string) {
+	if _, err := a.db.Exec("INSERT INTO commands (session_id, name, run_at) VALUES (?, ?, ?)", sessionID, name, time.Now().Unix()); err != nil {
+		log.Error("Could not record command", "error", err)
+	}
+}
+
+// report renders the operator overview shown by the stats command.
+func (a *analytics) report(t *theme) (string, error) {
+	var b strings.Builder
+
+	var visitors, sessions, commands int
+	var avgDuration sql.NullFloat64
+	row := a.db.QueryRow("SELECT COUNT(DISTINCT client_ip), COUNT(*), AVG(duration_ms) FROM sessions")
+	if err := row.Scan(&visitors, &sessions, &avgDuration); err != nil {
+		return "", err
+	}
+	if err := a.db.QueryRow("SELECT COUNT(*) FROM commands").Scan(&commands); err != nil {
+		return "", err
+	}
+	b.WriteString(t.accent.Render("Visitors") + "\n")
+	fmt.Fprintf(&b, "  Unique visitors:   %d\n", visitors)
+	fmt.Fprintf(&b, "  Sessions:          %d\n", sessions)
+	fmt.Fprintf(&b, "  Commands run:      %d\n", commands)
+	fmt.Fprintf(&b, "  Average duration:  %s\n\n", (time.Duration(avgDuration.Float64) * time.Millisecond).Round(time.Second))
+
+	b.WriteString(t.accent.Render("Most used commands") + "\n")
+	rows, err := a.db.Query("SELECT name, COUNT(*) AS n FROM commands GROUP BY name ORDER BY n DESC LIMIT 10")
+	if err != nil {
+		return "", err
+	}
+	for rows.Next() {
+		var name string
+		var n int
+		if err := rows.Scan(&name, &n); err != nil {
+			rows.Close()
+			return "", err
+		}
+		fmt.Fprintf(&b, "  %-10s %d\n", name, n)
+	}
+	rows.Close()
+
+	b.WriteString("\n" + t.accent.Render("Peak hours (UTC)") + "\n")
+	var perHour [24]int
+	peak := 0
+	rows, err = a.db.Query("SELECT CAST(strftime('%H', connected_at, 'unixepoch') AS INTEGER) AS hour, COUNT(*) FROM sessions GROUP BY hour")
+	if err != nil {
+		return "", err
+	}
+	for rows.Next() {
+		var hour, n int
+		if err := rows.Scan(&hour, &n); err != nil {
+			rows.Close()
+			return "", err
+		}
+		perHour[hour] = n
+		peak = max(peak, n)
+	}
+	rows.Close()
+	for hour, n := range perHour {
+		bar := ""
+		if peak > 0 {
+			bar = strings.Repeat("█", n*30/peak)
+		}
+		fmt.Fprintf(&b, "  %02d:00 %s %d\n", hour, t.success.Render(bar), n)
+	}
+	return b.String(), nil
+}
+
+// loadOperatorKeys reads the authorized_keys style file listing SSH keys
+// allowed to run stats.
+func loadOperatorKeys() []ssh.PublicKey {
+	path := os.Getenv("PORTFOLIO_OPERATOR_KEYS")
+	if path == "" {
+		path = ".ssh/operator_keys"
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var keys []ssh.PublicKey
+	for len(data) > 0 {
+		key, _, _, rest, err := ssh.ParseAuthorizedKey(data)
+		if err != nil {
+			break
+		}
+		keys = append(keys, key)
+		data = rest
+	}
+	return keys
+}
+
+// isOperator reports whether the session authenticated with an operator key.
+func isOperator(s ssh.Session) bool {
+	key := s.PublicKey()
+	if key == nil {
+		return false
+	}
+	for _, operatorKey := range loadOperatorKeys() {
+		if ssh.KeysEqual(key, operatorKey) {
+			return true
+		}
+	}
+	return false
+}
+
+func statsCommand(m *model, in commandInput) (string, tea.Cmd) {
+	if visitorStats == nil {
+		return m.tr("stats disabled"), nil
+	}
+	if m.operator {
+		report, err := visitorStats.report(m.theme)
+		if err != nil {
+			return m.tr("stats error", err), nil
+		}
+		return report, nil
+	}
+	password := os.Getenv("PORTFOLIO_STATS_PASSWORD")
+	if password == "" {
+		return m.tr("stats denied"), nil
+	}
+	return "", m.startApp(newPasswordPrompt(password, m.theme, m.tr))
+}
+
+// passwordPromptModel asks for the operator password without echoing it or
+// saving it to the command history.
+type passwordPromptModel struct {
+	input    textinput.Model
+	password string
+	theme    *theme
+	tr       func(id string, args ...any) string
+}
+
+func newPasswordPrompt(password string, t *theme, tr func(string, ...any) string) *passwordPromptModel {
+	ti := textinput.New()
+	ti.Prompt = tr("stats password")
+	ti.EchoMode = textinput.EchoPassword
+	ti.Focus()
+	return &passwordPromptModel{input: ti, password: password, theme: t, tr: tr}
+}
+
+func (p *passwordPromptModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (p *passwordPromptModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc", "ctrl+c":
+			return p, exitApp("", 0, p.tr("stats cancelled"))
+		case "enter":
+			if subtle.ConstantTimeCompare([]byte(p.input.Value()), []byte(p.password)) != 1 {
+				return p, exitApp("", 0, p.tr("stats bad password"))
+			}
+			report, err := visitorStats.report(p.theme)
+			if err != nil {
+				report = p.tr("stats error", err)
+			}
+			return p, exitApp("", 0, report)
+		}
+	}
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	return p, cmd
+}
+
+func (p *passwordPromptModel) View() string {
+	return p.input.View() + "\n\n" + p.tr("stats esc")
+}
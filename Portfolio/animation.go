@@ -0,0 +1,168 @@
+package main
+
+import (
+	"strings"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// animationTick is the frame interval for animations with no reason to pick
+// their own.
+const animationTick = 40 * time.Millisecond
+
+// An animation plays full screen, one frame at a time, until it's over or
+// the visitor presses a key. The animator below does the ticking, sizing and
+// drawing, so an animation only has to move its pieces and say where they are.
+type animation interface {
+	// resize is called before the first frame and whenever the terminal
+	// changes size, with the size of the canvas to draw on.
+	resize(width, height int)
+	// step advances the animation by one frame, returning false once it
+	// has finished. Endless animations always return true.
+	step() bool
+	// draw puts the current frame on the canvas.
+	draw(c *canvas)
+}
+
+// animationTickMsg advances an animation. Like snake, the id keeps ticks
+// from an animation that was cut short from affecting the next one.
+type animationTickMsg struct{ id int64 }
+
+var animations atomic.Int64
+
+// cell is one character on a canvas. An empty cell has no rune.
+type cell struct {
+	r     rune
+	color lipgloss.Color
+}
+
+// canvas is a grid of single-width characters that's drawn as one frame.
+type canvas struct {
+	width, height int
+	cells         []cell
+}
+
+func newCanvas(width, height int) *canvas {
+	return &canvas{width: width, height: height, cells: make([]cell, width*height)}
+}
+
+// set puts r at x, y. Anything off the canvas is ignored, so animations
+// don't have to clip what they draw.
+func (c *canvas) set(x, y int, r rune, color lipgloss.Color) {
+	if x < 0 || y < 0 || x >= c.width || y >= c.height {
+		return
+	}
+	c.cells[y*c.width+x] = cell{r: r, color: color}
+}
+
+// text writes s across the canvas starting at x, y, clipped like set.
+func (c *canvas) text(x, y int, s string, color lipgloss.Color) {
+	for i, r := range []rune(s) {
+		c.set(x+i, y, r, color)
+	}
+}
+
+func (c *canvas) clear() {
+	clear(c.cells)
+}
+
+// render draws the canvas, styling runs of same-coloured cells together so a
+// frame isn't mostly escape codes.
+func (c *canvas) render(t *theme) string {
+	var b, run strings.Builder
+	var runColor lipgloss.Color
+	flush := func() {
+		if run.Len() == 0 {
+			return
+		}
+		if runColor == "" {
+			b.WriteString(run.String())
+		} else {
+			b.WriteString(t.style().Foreground(runColor).Render(run.String()))
+		}
+		run.Reset()
+	}
+	for y := 0; y < c.height; y++ {
+		if y > 0 {
+			flush()
+			b.WriteByte('\n')
+		}
+		for x := 0; x < c.width; x++ {
+			cl := c.cells[y*c.width+x]
+			if cl.r == 0 {
+				cl = cell{r: ' '}
+			}
+			if cl.color != runColor {
+				flush()
+				runColor = cl.color
+			}
+			run.WriteRune(cl.r)
+		}
+	}
+	flush()
+	return b.String()
+}
+
+// animator is the app that plays an animation.
+type animator struct {
+	id       int64
+	theme    *theme
+	anim     animation
+	interval time.Duration
+	canvas   *canvas
+}
+
+// playAnimation takes over the screen with anim, drawing a frame every interval.
+func (m *model) playAnimation(anim animation, interval time.Duration) tea.Cmd {
+	a := &animator{id: animations.Add(1), theme: m.theme, anim: anim, interval: interval}
+	width, height := m.width, m.height
+	if width == 0 || height == 0 {
+		// The terminal hasn't told us its size yet
+		width, height = 80, 24
+	}
+	a.resize(width, height)
+	return m.startApp(a)
+}
+
+// resize fits the canvas to the terminal, leaving the bottom line for help.
+func (a *animator) resize(width, height int) {
+	width, height = max(1, width), max(1, height-1)
+	a.canvas = newCanvas(width, height)
+	a.anim.resize(width, height)
+}
+
+func (a *animator) tick() tea.Cmd {
+	id := a.id
+	return tea.Tick(a.interval, func(time.Time) tea.Msg { return animationTickMsg{id: id} })
+}
+
+func (a *animator) Init() tea.Cmd {
+	return a.tick()
+}
+
+func (a *animator) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		a.resize(msg.Width, msg.Height)
+	case tea.KeyMsg:
+		return a, exitApp("", 0, "")
+	case animationTickMsg:
+		if msg.id != a.id {
+			return a, nil
+		}
+		if !a.anim.step() {
+			return a, exitApp("", 0, "")
+		}
+		return a, a.tick()
+	}
+	return a, nil
+}
+
+func (a *animator) View() string {
+	a.canvas.clear()
+	a.anim.draw(a.canvas)
+	return a.canvas.render(a.theme) + "\n" + a.theme.muted.Render("Press any key to return to the shell")
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// An app is a full-screen sub-model (like a game) that takes over Update and
+// View until it sends appExitMsg. It's the generalised version of fileViewMode.
+
+// appExitMsg is sent by an app when the visitor leaves it. A non-empty
+// result is added to the scrollback, and a named app's score is compared
+// against the session's best.
+type appExitMsg struct {
+	name   string
+	score  int
+	result string
+}
+
+// scoreUnits names what each app's score measures.
+var scoreUnits = map[string]string{
+	"snake":    "points",
+	"typetest": "WPM",
+	"quiz":     "right",
+}
+
+// exitApp returns a command that closes the running app.
+func exitApp(name string, score int, result string) tea.Cmd {
+	return func() tea.Msg {
+		return appExitMsg{name: name, score: score, result: result}
+	}
+}
+
+// startApp hands the screen over to app, returning its Init command.
+func (m *model) startApp(app tea.Model) tea.Cmd {
+	m.app = app
+	return app.Init()
+}
+
+// updateApp routes a message to the running app and handles it exiting.
+func (m model) updateApp(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case appExitMsg:
+		m.app = nil
+		result := msg.result
+		if msg.name != "" {
+			m.progress.gameOver(msg.name, msg.score)
+			best := max(msg.score, m.highScores[msg.name])
+			m.highScores[msg.name] = best
+			if result == "" {
+				result = fmt.Sprintf("%s score: %d", msg.name, msg.score)
+			}
+			result += fmt.Sprintf(" (best this session: %d %s)", best, scoreUnits[msg.name])
+		}
+		if result != "" {
+			m.print(result)
+		}
+		m.announceAchievements()
+		m.refreshViewport()
+		m.followOutput()
+		return m, nil
+	case composeMsg:
+		cmd := m.runComposed(msg)
+		m.refreshViewport()
+		m.followOutput()
+		return m, cmd
+	case tea.WindowSizeMsg:
+		// Keep the shell's viewport in sync so it's the right size on return
+		m.resizeViewport(msg)
+	}
+	var cmd tea.Cmd
+	m.app, cmd = m.app.Update(msg)
+	return m, cmd
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// tokenKind tells words apart from the operators that join commands.
+type tokenKind int
+
+const (
+	tokenWord     tokenKind = iota
+	tokenPipe               // |
+	tokenRedirect           // > or >>
+)
+
+// token is one word or operator of a command line. start and end are its
+// byte offsets in the line, quotes and escapes included.
+type token struct {
+	kind       tokenKind
+	value      string // the word with its quotes and escapes removed, or the operator
+	start, end int
+}
+
+// syntaxError is a command line that can't be parsed. Its value is the id
+// of the message explaining why, so it can be shown in the visitor's language.
+type syntaxError string
+
+func (e syntaxError) Error() string {
+	return messages[defaultLanguage][string(e)]
+}
+
+const (
+	errUnterminatedQuote syntaxError = "unterminated quote"
+	errMissingRedirect   syntaxError = "missing redirect"
+	errRedirectTarget    syntaxError = "redirect target"
+	errEmptyStage        syntaxError = "empty stage"
+)
+
+// lex splits a command line into words and operators the way a shell does:
+// whitespace separates words, single quotes keep everything inside them as
+// it is, double quotes do too except for \" and \\, and a backslash outside
+// quotes escapes the character after it. If a quote is left open the tokens
+// are returned along with errUnterminatedQuote, so completion can still
+// finish the word being typed.
+func lex(line string) ([]token, error) {
+	var (
+		tokens  []token
+		word    strings.Builder
+		inWord  bool
+		start   int
+		quote   rune
+		escaped bool
+	)
+	begin := func(i int) {
+		if !inWord {
+			inWord, start = true, i
+		}
+	}
+	flush := func(end int) {
+		if inWord {
+			tokens = append(tokens, token{kind: tokenWord, value: word.String(), start: start, end: end})
+			word.Reset()
+			inWord = false
+		}
+	}
+
+	for i, r := range line {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case quote == '"':
+			switch {
+			case r == '"':
+				quote = 0
+			case r == '\\' && i+1 < len(line) && (line[i+1] == '"' || line[i+1] == '\\'):
+				escaped = true
+			default:
+				word.WriteRune(r)
+			}
+		case r == '\\':
+			begin(i)
+			escaped = true
+		case r == '\'' || r == '"':
+			begin(i)
+			quote = r
+		case unicode.IsSpace(r):
+			flush(i)
+		case r == '|':
+			flush(i)
+			tokens = append(tokens, token{kind: tokenPipe, value: "|", start: i, end: i + 1})
+		case r == '>':
+			flush(i)
+			// A second > right after the first means append
+			if n := len(tokens); n > 0 && tokens[n-1].value == ">" && tokens[n-1].end == i {
+				tokens[n-1].value, tokens[n-1].end = ">>", i+1
+			} else {
+				tokens = append(tokens, token{kind: tokenRedirect, value: ">", start: i, end: i + 1})
+			}
+		default:
+			begin(i)
+			word.WriteRune(r)
+		}
+	}
+	// A backslash at the very end has nothing to escape, so keep it
+	if escaped && quote == 0 {
+		word.WriteRune('\\')
+	}
+	flush(len(line))
+	if quote != 0 {
+		return tokens, errUnterminatedQuote
+	}
+	return tokens, nil
+}
+
+// splitArgs returns the words of a command line without any quoting.
+func splitArgs(line string) ([]string, error) {
+	tokens, err := lex(line)
+	if err != nil {
+		return nil, err
+	}
+	var words []string
+	for _, tok := range tokens {
+		words = append(words, tok.value)
+	}
+	return words, nil
+}
+
+// splitCommand returns the command name a stage starts with and the rest of
+// the stage as it was typed.
+func splitCommand(stage string) (name, rest string) {
+	tokens, _ := lex(stage)
+	if len(tokens) == 0 || tokens[0].kind != tokenWord {
+		return "", strings.TrimSpace(stage)
+	}
+	return tokens[0].value, strings.TrimSpace(stage[tokens[0].end:])
+}
+
+// quoteArg escapes the characters lex would otherwise split or unquote, so
+// s reads back as a single word.
+func quoteArg(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.IsSpace(r) || strings.ContainsRune(`'"\|>`, r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// flags holds the flags parseFlags found, by name. Switches have an empty value.
+type flags map[string]string
+
+func (f flags) has(name string) bool {
+	_, ok := f[name]
+	return ok
+}
+
+// parseFlags separates flags from the other arguments. Like Go's flag
+// package, -name and --name are the same flag. Names in switches take no
+// value; names in valued take the next argument, or one given as -name=value.
+// Everything after a lone -- is an argument, as are - and negative numbers.
+func parseFlags(args []string, switches, valued []string) (flags, []string, error) {
+	f := flags{}
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			rest = append(rest, args[i+1:]...)
+			break
+		}
+		if _, err := strconv.Atoi(arg); err == nil || len(arg) < 2 || arg[0] != '-' {
+			rest = append(rest, arg)
+			continue
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		switch {
+		case slices.Contains(switches, name):
+			if hasValue {
+				return nil, nil, fmt.Errorf("flag -%s doesn't take a value", name)
+			}
+			f[name] = ""
+		case slices.Contains(valued, name):
+			if !hasValue {
+				if i+1 == len(args) {
+					return nil, nil, fmt.Errorf("flag -%s needs a value", name)
+				}
+				i++
+				value = args[i]
+			}
+			f[name] = value
+		default:
+			return nil, nil, fmt.Errorf("unknown flag -%s", name)
+		}
+	}
+	return f, rest, nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"image"
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/log"
+)
+
+// neofetch shows an avatar in place of its ASCII art if there's an image for
+// it: avatar.png next to the config file, or whatever the avatar directive
+// names. It's drawn once, when the config is loaded, rather than on every
+// neofetch.
+
+// avatarWidth is how wide the avatar is drawn, in terminal columns.
+const avatarWidth = 32
+
+// loadAvatar draws the avatar for a config read from configFile, returning
+// "" to keep the ASCII art. file is the avatar directive, relative to the
+// config file's directory, and "" to look for avatar.png there.
+func loadAvatar(configFile, file string) string {
+	optional := file == ""
+	if optional {
+		file = "avatar.png"
+	}
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(filepath.Dir(configFile), file)
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		if !optional || !os.IsNotExist(err) {
+			log.Warn("Could not read avatar, using the ASCII art", "path", file, "error", err)
+		}
+		return ""
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		log.Warn("Avatar is not a PNG, JPEG or GIF image, using the ASCII art", "path", file, "error", err)
+		return ""
+	}
+	return renderHalfBlocks(img, avatarWidth)
+}
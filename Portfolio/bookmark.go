@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Bookmarks name directories so go can jump back to them from anywhere.
+// They last for the session, like aliases.
+
+// bookmarkNames returns the names of every bookmark, sorted.
+func (m model) bookmarkNames() []string {
+	var names []string
+	for name := range m.bookmarks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// bookmarkList shows each bookmark and the directory it points at.
+func (m model) bookmarkList() string {
+	if len(m.bookmarks) == 0 {
+		return m.tr("bookmark none")
+	}
+	var b strings.Builder
+	for _, name := range m.bookmarkNames() {
+		dir := "~"
+		if d := m.bookmarks[name]; d != "." {
+			dir += "/" + d
+		}
+		fmt.Fprintf(&b, "%s  %s\n", m.theme.header.Render(name), m.theme.folder.Render(dir))
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func bookmarkCommand(m *model, in commandInput) (string, tea.Cmd) {
+	if len(in.argv) == 0 {
+		return m.bookmarkList(), nil
+	}
+	switch sub := in.argv[0]; {
+	case sub == "list" && len(in.argv) == 1:
+		return m.bookmarkList(), nil
+	case sub == "add" && len(in.argv) == 2:
+		name := in.argv[1]
+		if strings.ContainsAny(name, "/ \t") {
+			return m.tr("bookmark name"), nil
+		}
+		m.bookmarks[name] = m.directory
+		return m.tr("bookmarked", m.displayDirectory(), name, name), nil
+	case sub == "rm" && len(in.argv) == 2:
+		name := in.argv[1]
+		if _, ok := m.bookmarks[name]; !ok {
+			return m.tr("bookmark not found", name), nil
+		}
+		delete(m.bookmarks, name)
+		return "", nil
+	}
+	return m.tr("bookmark usage"), nil
+}
+
+func goCommand(m *model, in commandInput) (string, tea.Cmd) {
+	if len(in.argv) != 1 {
+		return m.tr("go usage") + "\n\n" + m.bookmarkList(), nil
+	}
+	dir, ok := m.bookmarks[in.argv[0]]
+	if !ok {
+		return m.tr("go not found", in.argv[0]), nil
+	}
+	// The directory may have been removed since it was bookmarked
+	if !validatePath(hostPath(dir)) {
+		return m.tr("invalid dir", "~/"+dir), nil
+	}
+	m.directory = dir
+	return "", nil
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	chatHistorySize = 50  // messages replayed to someone joining
+	chatMaxMessage  = 280 // characters per message
+	chatMaxNick     = 16
+)
+
+// chatMessage is a line in the chat room. Notices (joins and leaves) have no nick.
+type chatMessage struct {
+	at   time.Time
+	nick string
+	text string
+}
+
+// chatClosedMsg tells the chat app its connection to the hub is gone.
+type chatClosedMsg struct{}
+
+type chatClient struct {
+	nick string
+	send chan chatMessage
+}
+
+type chatJoin struct {
+	client *chatClient
+	reply  chan error
+}
+
+// chatHub owns the room state. Everything goes through its channels so only
+// the run goroutine touches clients and history.
+type chatHub struct {
+	join      chan chatJoin
+	leave     chan *chatClient
+	broadcast chan chatMessage
+}
+
+var (
+	hub     *chatHub
+	hubOnce sync.Once
+)
+
+// chatRoom returns the shared hub, starting it on first use.
+func chatRoom() *chatHub {
+	hubOnce.Do(func() {
+		hub = &chatHub{
+			join:      make(chan chatJoin),
+			leave:     make(chan *chatClient),
+			broadcast: make(chan chatMessage),
+		}
+		go hub.run()
+	})
+	return hub
+}
+
+func (h *chatHub) run() {
+	clients := map[*chatClient]bool{}
+	var history []chatMessage
+
+	send := func(msg chatMessage) {
+		history = append(history, msg)
+		if len(history) > chatHistorySize {
+			history = history[1:]
+		}
+		for c := range clients {
+			select {
+			case c.send <- msg:
+			default:
+				// Drop messages for clients that fall behind rather than blocking everyone
+			}
+		}
+	}
+
+	for {
+		select {
+		case j := <-h.join:
+			taken := false
+			for c := range clients {
+				if strings.EqualFold(c.nick, j.client.nick) {
+					taken = true
+				}
+			}
+			if taken {
+				j.reply <- errors.New("that nickname is taken")
+				continue
+			}
+			for _, msg := range history {
+				select {
+				case j.client.send <- msg:
+				default:
+				}
+			}
+			clients[j.client] = true
+			j.reply <- nil
+			send(chatMessage{at: time.Now(), text: fmt.Sprintf("%s joined (%d online)", j.client.nick, len(clients))})
+		case c := <-h.leave:
+			if !clients[c] {
+				continue
+			}
+			delete(clients, c)
+			close(c.send)
+			send(chatMessage{at: time.Now(), text: fmt.Sprintf("%s left (%d online)", c.nick, len(clients))})
+		case msg := <-h.broadcast:
+			send(msg)
+		}
+	}
+}
+
+// listen waits for the next message for this client.
+func (c *chatClient) listen() tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-c.send
+		if !ok {
+			return chatClosedMsg{}
+		}
+		return msg
+	}
+}
+
+// sanitize strips control characters so visitors can't send escape sequences
+// to each other's terminals.
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// nickStyle gives each nickname a stable colour from the theme.
+func (c *chatModel) nickStyle(nick string) lipgloss.Style {
+	p := c.theme.colors
+	colors := []lipgloss.Color{p.folder, p.file, p.info, p.warning, p.success, p.accent}
+	h := fnv.New32a()
+	h.Write([]byte(nick))
+	return c.theme.style().Foreground(colors[h.Sum32()%uint32(len(colors))]).Bold(true)
+}
+
+type chatModel struct {
+	theme    *theme
+	client   *chatClient
+	done     <-chan struct{} // closed when the SSH session ends
+	input    textinput.Model
+	viewport viewport.Model
+	lines    []string
+	err      string
+	width    int
+	height   int
+}
+
+func chatCommand(m *model, in commandInput) (string, tea.Cmd) {
+	return "", m.startApp(newChat(m.width, m.height, m.done, m.theme))
+}
+
+func newChat(width, height int, done <-chan struct{}, t *theme) *chatModel {
+	ti := textinput.New()
+	ti.Prompt = "Nickname: "
+	ti.CharLimit = chatMaxNick
+	ti.Focus()
+	c := &chatModel{theme: t, input: ti, done: done, viewport: viewport.New(width, 0)}
+	c.resize(width, height)
+	return c
+}
+
+func (c *chatModel) resize(width, height int) {
+	c.width, c.height = width, height
+	c.input.Width = max(10, width-len(c.input.Prompt)-2)
+	// Title, blank line, blank line and the input
+	c.viewport.Width = width
+	c.viewport.Height = max(1, height-4)
+}
+
+func (c *chatModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (c *chatModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		c.resize(msg.Width, msg.Height)
+		c.viewport.SetContent(strings.Join(c.lines, "\n"))
+		return c, nil
+	case chatMessage:
+		c.addLine(msg)
+		return c, c.client.listen()
+	case chatClosedMsg:
+		return c, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "ctrl+c":
+			if c.client != nil {
+				chatRoom().leave <- c.client
+			}
+			return c, exitApp("", 0, "Left the chat room.")
+		case "enter":
+			return c, c.submit()
+		}
+	}
+
+	var cmd, vpCmd tea.Cmd
+	c.input, cmd = c.input.Update(msg)
+	c.viewport, vpCmd = c.viewport.Update(msg)
+	return c, tea.Batch(cmd, vpCmd)
+}
+
+// submit either picks the nickname or sends a message, depending on state.
+func (c *chatModel) submit() tea.Cmd {
+	text := strings.TrimSpace(sanitize(c.input.Value()))
+	if text == "" {
+		return nil
+	}
+
+	if c.client == nil {
+		if strings.ContainsAny(text, " \t") {
+			c.err = "Nicknames can't contain spaces."
+			return nil
+		}
+		client := &chatClient{nick: text, send: make(chan chatMessage, chatHistorySize+16)}
+		reply := make(chan error)
+		chatRoom().join <- chatJoin{client: client, reply: reply}
+		if err := <-reply; err != nil {
+			c.err = "Can't join: " + err.Error()
+			return nil
+		}
+		c.client, c.err = client, ""
+		c.input.Reset()
+		c.input.Prompt = c.nickStyle(text).Render(text) + "> "
+		c.input.CharLimit = chatMaxMessage
+		c.resize(c.width, c.height)
+
+		// Leave the room if the visitor disconnects without pressing esc
+		if c.done != nil {
+			go func() {
+				<-c.done
+				chatRoom().leave <- client
+			}()
+		}
+		return client.listen()
+	}
+
+	chatRoom().broadcast <- chatMessage{at: time.Now(), nick: c.client.nick, text: text}
+	c.input.Reset()
+	return nil
+}
+
+func (c *chatModel) addLine(msg chatMessage) {
+	stamp := c.theme.subtle.Render(msg.at.Format("15:04"))
+	line := stamp + " " + c.theme.muted.Italic(true).Render(msg.text)
+	if msg.nick != "" {
+		line = stamp + " " + c.nickStyle(msg.nick).Render(msg.nick) + ": " + msg.text
+	}
+	c.lines = append(c.lines, line)
+	atBottom := c.viewport.AtBottom()
+	c.viewport.SetContent(c.theme.style().Width(c.width).Render(strings.Join(c.lines, "\n")))
+	if atBottom {
+		c.viewport.GotoBottom()
+	}
+}
+
+func (c *chatModel) View() string {
+	title := c.theme.header.Render("💬 Chat room") + c.theme.muted.Italic(true).Render("  (esc to leave)")
+	if c.client == nil {
+		intro := "Pick a nickname to join everyone else browsing the portfolio right now."
+		if c.err != "" {
+			intro = c.theme.danger.Render(c.err)
+		}
+		return fmt.Sprintf("%s\n\n%s\n\n%s", title, intro, c.input.View())
+	}
+	return fmt.Sprintf("%s\n%s\n\n%s", title, c.viewport.View(), c.input.View())
+}
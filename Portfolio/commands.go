@@ -0,0 +1,310 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	gowiki "github.com/trietmn/go-wiki"
+)
+
+// commandInput is what a command receives when it runs.
+type commandInput struct {
+	args       string   // the arguments joined by spaces, quotes removed
+	argv       []string // the arguments as separate words
+	stdin      string   // output of the previous command in a pipeline
+	piped      bool     // true when stdin holds the previous command's output
+	toTerminal bool     // false when the output is piped or redirected
+}
+
+// commandFunc runs a command and returns its output. Commands that need to
+// talk to the Bubble Tea runtime (e.g. exit) can also return a tea.Cmd.
+type commandFunc func(m *model, in commandInput) (string, tea.Cmd)
+
+// commands is the dispatch table used by execute.
+var commands = map[string]commandFunc{
+	"bookmark":     bookmarkCommand,
+	"go":           goCommand,
+	"cd":           cdCommand,
+	"compose":      composeCommand,
+	"ls":           lsCommand,
+	"help":         helpCommand,
+	"man":          manCommand,
+	"clear":        clearCommand,
+	"cat":          catCommand,
+	"grep":         grepCommand,
+	"head":         headCommand,
+	"tail":         tailCommand,
+	"wc":           wcCommand,
+	"tree":         treeCommand,
+	"img":          imgCommand,
+	"joke":         jokeCommand,
+	"wiki":         wikiCommand,
+	"weather":      weatherCommand,
+	"github":       githubCommand,
+	"price":        priceCommand,
+	"top":          topCommand,
+	"uptime":       uptimeCommand,
+	"motd":         motdCommand,
+	"copy":         copyCommand,
+	"resume":       resumeCommand,
+	"download":     downloadCommand,
+	"history":      historyCommand,
+	"alias":        aliasCommand,
+	"unalias":      unaliasCommand,
+	"demo":         demoCommand,
+	"theme":        themeCommand,
+	"lang":         langCommand,
+	"pwd":          pwdCommand,
+	"exit":         exitCommand,
+	"whoami":       whoamiCommand,
+	"date":         dateCommand,
+	"echo":         echoCommand,
+	"neofetch":     neofetchCommand,
+	"version":      versionCommand,
+	"skills":       skillsCommand,
+	"contact":      contactCommand,
+	"qr":           qrCommand,
+	"coinflip":     coinflipCommand,
+	"yoda":         yodaCommand,
+	"fortune":      fortuneCommand,
+	"cowsay":       cowsayCommand,
+	"play":         playCommand,
+	"typetest":     typetestCommand,
+	"quiz":         quizCommand,
+	"matrix":       matrixCommand,
+	"fireworks":    fireworksCommand,
+	"timer":        timerCommand,
+	"pomodoro":     pomodoroCommand,
+	"achievements": achievementsCommand,
+	"stats":        statsCommand,
+	"chat":         chatCommand,
+}
+
+// builtinNames lists the built-in commands, sorted. It's filled in by init,
+// since the commands in the table can't refer to the table itself.
+var builtinNames []string
+
+func init() {
+	for name := range commands {
+		builtinNames = append(builtinNames, name)
+	}
+	sort.Strings(builtinNames)
+}
+
+// commandNames returns the names to complete and suggest: the built-in
+// commands, leaving out hidden ones, then any plugins.
+func commandNames() []string {
+	var names []string
+	for _, name := range builtinNames {
+		if p, ok := findManPage(name); !ok || !p.hidden {
+			names = append(names, name)
+		}
+	}
+	return append(names, pluginNames()...)
+}
+
+func cdCommand(m *model, in commandInput) (string, tea.Cmd) {
+	// Like a shell, cd on its own goes home
+	if in.args == "" {
+		m.directory = "."
+		return "", nil
+	}
+	// The root is its own parent, like / in a chroot
+	if in.args == ".." && m.directory == "." {
+		return "", nil
+	}
+	dir, err := m.resolve(in.args)
+	if err != nil {
+		return err.Error(), nil
+	}
+	if !validatePath(hostPath(dir)) {
+		return m.tr("invalid dir", in.args), nil
+	}
+	m.directory = dir
+	return "", nil
+}
+
+func lsCommand(m *model, in commandInput) (string, tea.Cmd) {
+	entries, err := m.readDir(".")
+	if err != nil {
+		return m.tr("read dir error", err), nil
+	}
+
+	// Plain names when piped, so commands like grep see just the file names
+	if !in.toTerminal {
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.name)
+		}
+		return strings.Join(names, "\n"), nil
+	}
+
+	s := "\nName\n------\n"
+	for _, entry := range entries {
+		if entry.isDir {
+			s += m.theme.folder.Render("📁 "+entry.name) + "\n"
+		} else {
+			s += m.theme.file.Render("📄 "+entry.name) + "\n"
+		}
+	}
+	return s, nil
+}
+
+func helpCommand(m *model, in commandInput) (string, tea.Cmd) {
+	if in.args != "" {
+		return manCommand(m, in)
+	}
+	return m.helpText(), nil
+}
+
+func clearCommand(m *model, in commandInput) (string, tea.Cmd) {
+	m.clihistory = []entry{bannerEntry()} // Reset history but keep header
+	return "", nil
+}
+
+func catCommand(m *model, in commandInput) (string, tea.Cmd) {
+	if in.args == "" {
+		// Like the real cat, pass piped input straight through
+		return in.stdin, nil
+	}
+	content, err := m.readFile(in.args)
+	if err != nil {
+		return err.Error(), nil
+	}
+	if !in.toTerminal {
+		return content, nil
+	}
+	m.openPager(in.args, content)
+	return "", nil
+}
+
+func grepCommand(m *model, in commandInput) (string, tea.Cmd) {
+	return m.grep(in), nil
+}
+
+func wikiCommand(m *model, in commandInput) (string, tea.Cmd) {
+	query := in.args
+	if query == "" {
+		return m.tr("wiki usage"), nil
+	}
+	// Perform wiki search
+	search_result, err := gowiki.Summary(query, 5, -1, false, true)
+	if err != nil {
+		return m.tr("wiki error", err), nil
+	}
+	return "\n" + search_result, nil
+}
+
+func historyCommand(m *model, in commandInput) (string, tea.Cmd) {
+	return m.historyView(), nil
+}
+
+func pwdCommand(m *model, in commandInput) (string, tea.Cmd) {
+	return m.tr("pwd", m.displayDirectory()), nil
+}
+
+func exitCommand(m *model, in commandInput) (string, tea.Cmd) {
+	return "", tea.Quit
+}
+
+func whoamiCommand(m *model, in commandInput) (string, tea.Cmd) {
+	return m.tr("whoami"), nil
+}
+
+func echoCommand(m *model, in commandInput) (string, tea.Cmd) {
+	text := in.args
+	if text == "" {
+		// Print piped text, so composed text can be echoed into a file
+		text = strings.TrimSuffix(in.stdin, "\n")
+	}
+	if !in.toTerminal {
+		return text, nil
+	}
+	return m.tr("echo", text), nil
+}
+
+// neofetchArt is drawn beside the system information when there's no
+// avatar.
+const neofetchArt = `                                .88888888:.
+                           88888888.88888.
+                         .8888888888888888.
+                         888888888888888888
+                         88' _` + "`" + `88'_  ` + "`" + `88888
+                         88 88 88 88  88888
+                         88_88_::_88_:88888
+                         88:::,::,:::::8888
+                         88` + "`" + `:::::::::` + "`" + `8888
+                        .88  ` + "`" + `::::` + "`" + `    8:88.
+                   8888            ` + "`" + `8:888.
+                 .8888` + "`" + `             ` + "`" + `888888.
+                .8888:..  .::.  ...:` + "`" + `8888888:.
+           .8888.` + "`" + `     :` + "`" + `     ` + "`" + `::` + "`" + `88:88888
+          .8888        ` + "`" + `         ` + "`" + `.888:8888.
+         888:8         .           888:88888
+   .888:88        .:           88:88888:
+   8888888.       ::           88:888888
+   ` + "`" + `.::.888.      ::          .88888888
+  .::::::.888.    ::         :::` + "`" + `8888` + "`" + `.  :
+ ::::::::::.888   ` + "`" + `         .::::::::::::
+ ::::::::::::.8    ` + "`" + `      .:8::::::::::::.
+.::::::::::::::.        .:888:::::::::::::
+:::::::::::::::88:.__..:88888::::::::::::` + "`" + `
+ ` + "`" + `` + "`" + `.:::::::::::88888888888.88:::::::::
+           ` + "`" + `` + "`" + `:::_:` + "`" + ` -- ` + "`" + `` + "`" + ` -` + "`" + `-` + "`" + ` ` + "`" + `` + "`" + `:_::::`
+
+func neofetchCommand(m *model, in commandInput) (string, tea.Cmd) {
+	typingBest := "no score yet (try typetest)"
+	if wpm, ok := m.highScores["typetest"]; ok {
+		typingBest = fmt.Sprintf("%d WPM", wpm)
+	}
+	info := m.theme.header.Render(fmt.Sprintf(`guest@fred-cli
+-----------------
+OS: Fred's Portfolio CLI
+Kernel: Go Runtime
+Uptime: %s
+Shell: Go CLI v1.0
+Resolution: Terminal Based
+Terminal: Bubbles Tea
+CPU: %s
+Memory: Efficient Go runtime
+Language: Go
+Platform: %s
+Typing: %s`, humanDuration(time.Since(processStarted)), runtime.GOARCH, runtime.GOOS, typingBest))
+
+	art := currentConfig().avatar
+	if art == "" {
+		art = m.theme.header.Render(neofetchArt)
+	}
+	return "\n" + lipgloss.JoinHorizontal(lipgloss.Top, art, "    ", info), nil
+}
+
+func versionCommand(m *model, in commandInput) (string, tea.Cmd) {
+	return "version 1.0.0, built with Go " + runtime.Version() + " on " + runtime.GOOS + "/" + runtime.GOARCH, nil
+}
+
+func skillsCommand(m *model, in commandInput) (string, tea.Cmd) {
+	return `
+Skills:
+================
+• Go Programming
+• Terminal/CLI Development
+• Web Development
+• Game Development
+• GDscript (Godot programming language)
+• LLMS (Large Language Models)
+`, nil
+}
+
+func coinflipCommand(m *model, in commandInput) (string, tea.Cmd) {
+	var num float64 = rand.Float64()
+	if num < 0.5 {
+		return m.tr("heads"), nil
+	}
+	return m.tr("tails"), nil
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// completionKind describes what a command expects as its arguments.
+type completionKind int
+
+const (
+	completeNothing completionKind = iota
+	completeFiles
+	completeDirs
+	completeCommands
+	completeBookmarks
+)
+
+// argCompletion maps a command to what should be completed after it.
+// Commands not listed here get no argument completion.
+var argCompletion = map[string]completionKind{
+	"cd":       completeDirs,
+	"cat":      completeFiles,
+	"grep":     completeFiles,
+	"head":     completeFiles,
+	"tail":     completeFiles,
+	"wc":       completeFiles,
+	"img":      completeFiles,
+	"download": completeFiles,
+	"man":      completeCommands,
+	"help":     completeCommands,
+	"go":       completeBookmarks,
+}
+
+// completionState holds the candidates shown in the menu below the prompt
+// while the user cycles through them with Tab.
+type completionState struct {
+	prefix     string   // input before the word being completed
+	candidates []string // all matches for the word being completed
+	index      int      // selected candidate, -1 when nothing is selected yet
+}
+
+// active reports whether the completion menu should be shown.
+func (c completionState) active() bool {
+	return len(c.candidates) > 1
+}
+
+// completeInput handles a Tab (or Shift+Tab when reverse is true) press.
+func (m *model) completeInput(reverse bool) {
+	// Repeated Tab presses cycle through the menu that is already open.
+	if m.completion.active() {
+		n := len(m.completion.candidates)
+		if reverse {
+			m.completion.index = (m.completion.index - 1 + n) % n
+		} else {
+			m.completion.index = (m.completion.index + 1) % n
+		}
+		m.input.SetValue(m.completion.prefix + quoteArg(m.completion.candidates[m.completion.index]))
+		m.input.CursorEnd()
+		return
+	}
+
+	input := m.input.Value()
+	if input == "" {
+		return // No input to autocomplete
+	}
+
+	// Everything before the current word stays untouched. The input is split
+	// the same way it will be run, so quoted and escaped names complete too.
+	tokens, _ := lex(input)
+	prefix, word := input, ""
+	if n := len(tokens); n > 0 && tokens[n-1].kind == tokenWord && tokens[n-1].end == len(input) {
+		prefix, word = input[:tokens[n-1].start], tokens[n-1].value
+		tokens = tokens[:n-1]
+	}
+
+	// Find the command of the stage being typed, if the word isn't it
+	command, redirect := "", false
+	for _, tok := range tokens {
+		switch {
+		case tok.kind == tokenPipe:
+			command = ""
+		case tok.kind == tokenRedirect:
+			redirect = true
+		case command == "":
+			command = tok.value
+		}
+	}
+
+	var candidates []string
+	switch {
+	case redirect:
+		candidates = m.pathCandidates(word, completeFiles)
+	case command == "":
+		candidates = matchPrefix(append(m.aliasNames(), m.commandautocomplete...), word)
+	default:
+		// Complete an alias's arguments like those of the command it runs
+		command, _ = splitCommand(m.expandAlias(command))
+		switch kind := argCompletion[command]; kind {
+		case completeCommands:
+			candidates = matchPrefix(m.commandautocomplete, word)
+		case completeBookmarks:
+			candidates = matchPrefix(m.bookmarkNames(), word)
+		default:
+			candidates = m.pathCandidates(word, kind)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		m.completion = completionState{}
+	case 1:
+		m.completion = completionState{}
+		m.input.SetValue(prefix + quoteArg(candidates[0]))
+		m.input.CursorEnd()
+	default:
+		m.completion = completionState{prefix: prefix, candidates: candidates, index: -1}
+		// Extend the word as far as all candidates agree
+		if common := commonPrefix(candidates); utf8.RuneCountInString(common) > utf8.RuneCountInString(word) {
+			m.input.SetValue(prefix + quoteArg(common))
+			m.input.CursorEnd()
+		}
+	}
+}
+
+// pathCandidates lists entries matching word relative to the current directory.
+// Directories are returned with a trailing slash so completion can descend into them.
+func (m model) pathCandidates(word string, kind completionKind) []string {
+	if kind == completeNothing {
+		return nil
+	}
+
+	dir, base := "", word
+	if i := strings.LastIndex(word, "/"); i >= 0 {
+		dir, base = word[:i+1], word[i+1:]
+	}
+
+	entries, err := m.readDir(path.Join(".", dir))
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.isDir {
+			names = append(names, dir+entry.name+"/")
+		} else if kind == completeFiles {
+			names = append(names, dir+entry.name)
+		}
+	}
+	return matchPrefix(names, dir+base)
+}
+
+// matchPrefix returns the options starting with word, ignoring case.
+func matchPrefix(options []string, word string) []string {
+	var matches []string
+	for _, option := range options {
+		if strings.HasPrefix(strings.ToLower(option), strings.ToLower(word)) {
+			matches = append(matches, option)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+// commonPrefix returns the longest prefix shared by every string, ignoring
+// case like matchPrefix. The prefix is taken from the first string, and is
+// cut between characters rather than bytes.
+func commonPrefix(strs []string) string {
+	if len(strs) == 0 {
+		return ""
+	}
+	common := []rune(strs[0])
+	for _, s := range strs[1:] {
+		n := 0
+		for _, r := range s {
+			if n == len(common) || unicode.ToLower(r) != unicode.ToLower(common[n]) {
+				break
+			}
+			n++
+		}
+		common = common[:n]
+	}
+	return string(common)
+}
+
+// view renders the candidates wrapped to the given width.
+func (c completionState) view(width int, t *theme) string {
+	var lines []string
+	line, lineWidth := "", 0
+	for i, candidate := range c.candidates {
+		item := t.muted.Render(candidate)
+		if i == c.index {
+			item = t.selected.Render(candidate)
+		}
+		w := lipgloss.Width(candidate) + 2
+		if lineWidth > 0 && lineWidth+w > width {
+			lines = append(lines, line)
+			line, lineWidth = "", 0
+		}
+		line += item + "  "
+		lineWidth += w
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestCommonPrefix(t *testing.T) {
+	tests := []struct {
+		name string
+		strs []string
+		want string
+	}{
+		{"none", nil, ""},
+		{"one", []string{"About/"}, "About/"},
+		{"shared", []string{"Projects/", "Programs/"}, "Pro"},
+		{"nothing shared", []string{"xyz", "abc"}, ""},
+		{"mixed case", []string{"About/", "about.txt"}, "About"},
+		{"from the first", []string{"about.txt", "About/"}, "about"},
+		{"multi-byte", []string{"Café/", "café.md"}, "Café"},
+		{"differs in a multi-byte rune", []string{"cafÉ", "cafe"}, "caf"},
+		{"prefix of another", []string{"go", "golang"}, "go"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := commonPrefix(tt.strs); got != tt.want {
+				t.Errorf("commonPrefix(%q) = %q, want %q", tt.strs, got, tt.want)
+			}
+		})
+	}
+}
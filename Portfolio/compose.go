@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// compose opens an editor for text with line breaks in it, which the prompt
+// can't take, and pipes it into a command line when it's done. Ending a
+// line with a backslash does the same for that line.
+
+// composeMaxLength is the most text the editor takes, in characters.
+const composeMaxLength = 4000
+
+// composeMsg is sent by the editor when the text is ready to run line with.
+type composeMsg struct {
+	line string
+	text string
+}
+
+// continuedLine reports whether input ends with a backslash that isn't
+// itself escaped, returning the line without it.
+func continuedLine(input string) (string, bool) {
+	trimmed := strings.TrimRight(input, "\\")
+	if (len(input)-len(trimmed))%2 == 0 {
+		return input, false
+	}
+	return strings.TrimSpace(input[:len(input)-1]), true
+}
+
+func composeCommand(m *model, in commandInput) (string, tea.Cmd) {
+	return "", m.startApp(newComposer(in.args, m.width, m.height, m.theme))
+}
+
+// composerModel is the editor: a textarea that fills the screen.
+type composerModel struct {
+	theme *theme
+	line  string // what to pipe the text into, "" to print it
+	input textarea.Model
+}
+
+func newComposer(line string, width, height int, t *theme) *composerModel {
+	ta := textarea.New()
+	ta.Placeholder = "Type away, enter starts a new line..."
+	ta.CharLimit = composeMaxLength
+	ta.ShowLineNumbers = false
+	ta.Focus()
+	c := &composerModel{theme: t, line: line, input: ta}
+	c.resize(width, height)
+	return c
+}
+
+func (c *composerModel) resize(width, height int) {
+	c.input.SetWidth(max(20, width-2))
+	// Title, blank line, blank line and the help line
+	c.input.SetHeight(max(3, height-4))
+}
+
+func (c *composerModel) Init() tea.Cmd {
+	return textarea.Blink
+}
+
+func (c *composerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		c.resize(msg.Width, msg.Height)
+		return c, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "ctrl+c":
+			return c, exitApp("", 0, "compose: cancelled")
+		case "ctrl+d":
+			text := c.input.Value()
+			if strings.TrimSpace(text) == "" {
+				return c, exitApp("", 0, "compose: nothing written")
+			}
+			line := c.line
+			return c, func() tea.Msg { return composeMsg{line: line, text: text} }
+		}
+	}
+	var cmd tea.Cmd
+	c.input, cmd = c.input.Update(msg)
+	return c, cmd
+}
+
+func (c *composerModel) View() string {
+	th := c.theme
+	title := th.header.Render("✏️  Compose")
+	if c.line != "" {
+		title += th.muted.Render(" for " + c.line)
+	}
+	help := th.muted.Italic(true).Render(fmt.Sprintf("ctrl+d to finish, esc to cancel · %d of %d characters", len([]rune(c.input.Value())), composeMaxLength))
+	return fmt.Sprintf("%s\n\n%s\n\n%s", title, c.input.View(), help)
+}
+
+// runComposed closes the editor and runs its command line with the text
+// piped in, or prints the text if there's no command.
+func (m *model) runComposed(msg composeMsg) tea.Cmd {
+	m.app = nil
+	m.commandsRun++
+	label := strings.TrimSpace("compose " + msg.line)
+	if msg.line == "" {
+		m.show(label, msg.text)
+		return nil
+	}
+	output, cmd := m.executePiped(msg.line, msg.text, true)
+	m.show(label, output)
+	return cmd
+}
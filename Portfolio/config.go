@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/log"
+)
+
+// config holds settings read from the portfolio's config file. Each line is
+// a directive like the matching command, e.g.
+//
+//	# Shortcuts every visitor gets
+//	alias ll="ls"
+//	alias about="cat About/bio.txt"
+//	theme dracula
+//	avatar me.png
+type config struct {
+	aliases map[string]string
+	theme   string // default theme, empty to match the visitor's terminal
+	avatar  string // neofetch's avatar, drawn in half blocks, or "" for the ASCII art
+}
+
+// portfolioConfig is loaded at startup, and again on SIGHUP, and shared by
+// every session.
+var portfolioConfig = struct {
+	sync.Mutex
+	config
+}{config: config{aliases: map[string]string{}}}
+
+func currentConfig() config {
+	portfolioConfig.Lock()
+	defer portfolioConfig.Unlock()
+	return portfolioConfig.config
+}
+
+// setConfig replaces the config. Sessions pick it up as they start.
+func setConfig(c config) {
+	portfolioConfig.Lock()
+	portfolioConfig.config = c
+	portfolioConfig.Unlock()
+}
+
+// configPath returns the config file location, which can be overridden with
+// the PORTFOLIO_CONFIG environment variable.
+func configPath() string {
+	if p := os.Getenv("PORTFOLIO_CONFIG"); p != "" {
+		return p
+	}
+	return filepath.Join(dataDir(), "config")
+}
+
+// envOr returns the environment variable name, or fallback if it's unset.
+// Flags use it for their defaults so every flag has an environment equivalent.
+func envOr(name, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// envInt is envOr for numbers. Values that don't parse are ignored.
+func envInt(name string, fallback int) int {
+	if n, err := strconv.Atoi(os.Getenv(name)); err == nil {
+		return n
+	}
+	return fallback
+}
+
+// envBool is envOr for switches, accepting the values strconv.ParseBool does.
+func envBool(name string) bool {
+	b, _ := strconv.ParseBool(os.Getenv(name))
+	return b
+}
+
+// loadConfig reads the config file at path. A missing file isn't an error,
+// and bad lines are logged and skipped so one typo can't stop the server.
+func loadConfig(path string) config {
+	cfg, avatar := parseConfig(path)
+	cfg.avatar = loadAvatar(path, avatar)
+	return cfg
+}
+
+// parseConfig reads the directives in the config file at path, returning the
+// avatar file separately to be loaded once the file is closed.
+func parseConfig(path string) (cfg config, avatar string) {
+	cfg = config{aliases: map[string]string{}}
+	f, err := os.Open(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Error("Could not read config", "path", path, "error", err)
+		}
+		return cfg, ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		directive, rest, _ := strings.Cut(line, " ")
+		switch directive {
+		case "alias":
+			name, value, err := parseAlias(strings.TrimSpace(rest))
+			if err != nil {
+				log.Warn("Skipping config line", "path", path, "line", n, "error", err)
+				continue
+			}
+			cfg.aliases[name] = value
+		case "theme":
+			name := strings.TrimSpace(rest)
+			if _, ok := palettes[name]; !ok {
+				log.Warn("Skipping unknown theme in config", "path", path, "line", n, "theme", name)
+				continue
+			}
+			cfg.theme = name
+		case "avatar":
+			avatar = strings.TrimSpace(rest)
+		default:
+			log.Warn("Skipping unknown config directive", "path", path, "line", n, "directive", directive)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		log.Error("Could not read config", "path", path, "error", err)
+	}
+	return cfg, avatar
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+)
+
+// contact send emails me a visitor's message through the SMTP server set by
+// PORTFOLIO_SMTP_ADDR, logging in with PORTFOLIO_SMTP_USER and
+// PORTFOLIO_SMTP_PASSWORD if they're set. Without an address, visitors are
+// pointed at my email instead.
+
+const (
+	// contactTo is where messages go unless PORTFOLIO_CONTACT_TO is set.
+	contactTo = "cli@itsfred.dev"
+	// contactMaxMessage is the longest message, in characters.
+	contactMaxMessage = 2000
+	// contactLimit messages can be sent from one IP address per contactWindow.
+	contactLimit  = 3
+	contactWindow = time.Hour
+)
+
+// contactSent keeps when each IP address last sent messages, for the limit.
+var contactSent = struct {
+	sync.Mutex
+	byIP map[string][]time.Time
+}{byIP: map[string][]time.Time{}}
+
+// allowContact records a message from ip, or reports how long until it may
+// send another.
+func allowContact(ip string) (time.Duration, bool) {
+	contactSent.Lock()
+	defer contactSent.Unlock()
+	now := time.Now()
+	var recent []time.Time
+	for _, at := range contactSent.byIP[ip] {
+		if now.Sub(at) < contactWindow {
+			recent = append(recent, at)
+		}
+	}
+	if len(recent) >= contactLimit {
+		contactSent.byIP[ip] = recent
+		return recent[0].Add(contactWindow).Sub(now), false
+	}
+	contactSent.byIP[ip] = append(recent, now)
+	return 0, true
+}
+
+// smtpSettings returns the mail server and addresses to send with, or ok
+// false if sending isn't set up.
+func smtpSettings() (addr, user, password, from, to string, ok bool) {
+	addr = os.Getenv("PORTFOLIO_SMTP_ADDR")
+	user = os.Getenv("PORTFOLIO_SMTP_USER")
+	password = os.Getenv("PORTFOLIO_SMTP_PASSWORD")
+	from = envOr("PORTFOLIO_CONTACT_FROM", user)
+	to = envOr("PORTFOLIO_CONTACT_TO", contactTo)
+	return addr, user, password, from, to, addr != "" && from != ""
+}
+
+// contactSentMsg reports how sending a message went.
+type contactSentMsg struct {
+	err error
+}
+
+// sendContact emails message to me, with replyTo as its Reply-To so I can
+// answer the visitor directly.
+func sendContact(replyTo, message string) error {
+	addr, user, password, from, to, ok := smtpSettings()
+	if !ok {
+		return fmt.Errorf("sending mail isn't set up")
+	}
+	var auth smtp.Auth
+	if user != "" {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", user, password, host)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: Portfolio CLI <%s>\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Reply-To: %s\r\n", replyTo)
+	fmt.Fprintf(&b, "Subject: Portfolio message from %s\r\n", replyTo)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(message, "\n", "\r\n") + "\r\n")
+	return smtp.SendMail(addr, auth, from, []string{to}, []byte(b.String()))
+}
+
+func contactCommand(m *model, in commandInput) (string, tea.Cmd) {
+	if len(in.argv) == 0 {
+		return m.tr("contact"), nil
+	}
+	if in.argv[0] != "send" {
+		return m.tr("contact usage"), nil
+	}
+	if _, _, _, _, _, ok := smtpSettings(); !ok {
+		return m.tr("contact unset", contactTo), nil
+	}
+	message := strings.Join(in.argv[1:], " ")
+	if message == "" && in.piped {
+		message = strings.TrimSpace(in.stdin)
+	}
+	return "", m.startApp(newContactForm(m.ip, message, m.width, m.logger, m.theme, m.tr))
+}
+
+// contactFormModel asks for the visitor's email address and message, then
+// sends them in the background. Its text is in the session's language, through
+// tr.
+type contactFormModel struct {
+	theme   *theme
+	logger  *log.Logger
+	tr      func(id string, args ...any) string
+	ip      string
+	email   textinput.Model
+	message textarea.Model
+	err     string
+	sending bool
+}
+
+func newContactForm(ip, message string, width int, logger *log.Logger, t *theme, tr func(string, ...any) string) *contactFormModel {
+	email := textinput.New()
+	email.Prompt = tr("contact email")
+	email.Placeholder = "you@example.com"
+	email.CharLimit = 254
+	email.Focus()
+
+	ta := textarea.New()
+	ta.Placeholder = tr("contact message")
+	ta.CharLimit = contactMaxMessage
+	ta.ShowLineNumbers = false
+	ta.SetHeight(8)
+	ta.SetValue(message)
+	ta.Blur()
+
+	c := &contactFormModel{theme: t, logger: logger, tr: tr, ip: ip, email: email, message: ta}
+	c.resize(width)
+	return c
+}
+
+func (c *contactFormModel) resize(width int) {
+	c.email.Width = max(10, width-len(c.email.Prompt)-2)
+	c.message.SetWidth(max(20, width-2))
+}
+
+func (c *contactFormModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (c *contactFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		c.resize(msg.Width)
+		return c, nil
+	case contactSentMsg:
+		c.sending = false
+		if msg.err != nil {
+			c.logger.Error("Could not send contact message", "error", msg.err)
+			c.err = c.tr("contact failed", contactTo)
+			return c, nil
+		}
+		c.logger.Info("Sent contact message", "reply_to", c.email.Value())
+		return c, exitApp("", 0, c.tr("contact sent", c.email.Value()))
+	case tea.KeyMsg:
+		if c.sending {
+			return c, nil
+		}
+		switch msg.String() {
+		case "esc", "ctrl+c":
+			return c, exitApp("", 0, c.tr("contact cancelled"))
+		case "tab", "shift+tab":
+			c.switchField()
+			return c, nil
+		case "enter":
+			if c.email.Focused() {
+				c.switchField()
+				return c, nil
+			}
+		case "ctrl+d":
+			return c, c.send()
+		}
+	}
+	var cmd tea.Cmd
+	if c.email.Focused() {
+		c.email, cmd = c.email.Update(msg)
+	} else {
+		c.message, cmd = c.message.Update(msg)
+	}
+	return c, cmd
+}
+
+func (c *contactFormModel) switchField() {
+	if c.email.Focused() {
+		c.email.Blur()
+		c.message.Focus()
+	} else {
+		c.message.Blur()
+		c.email.Focus()
+	}
+}
+
+// send checks the form and, if it's fine, starts sending it.
+func (c *contactFormModel) send() tea.Cmd {
+	addr, err := mail.ParseAddress(strings.TrimSpace(c.email.Value()))
+	if err != nil || addr.Name != "" || strings.ContainsAny(addr.Address, "\r\n") {
+		c.err = c.tr("contact bad email")
+		if !c.email.Focused() {
+			c.switchField()
+		}
+		return nil
+	}
+	// Like sanitize, but keeping the message's line breaks
+	message := strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && r != '\n' && r != '\t' {
+			return -1
+		}
+		return r
+	}, c.message.Value()))
+	if message == "" {
+		c.err = c.tr("contact empty")
+		if c.email.Focused() {
+			c.switchField()
+		}
+		return nil
+	}
+	if wait, ok := allowContact(c.ip); !ok {
+		c.err = c.tr("contact wait", wait.Round(time.Minute))
+		return nil
+	}
+	c.email.SetValue(addr.Address)
+	c.err, c.sending = "", true
+	return func() tea.Msg {
+		return contactSentMsg{err: sendContact(addr.Address, message)}
+	}
+}
+
+func (c *contactFormModel) View() string {
+	th := c.theme
+	title := th.header.Render(c.tr("contact title")) + th.muted.Italic(true).Render(c.tr("contact keys"))
+	status := ""
+	switch {
+	case c.sending:
+		status = th.muted.Render(c.tr("contact sending"))
+	case c.err != "":
+		status = th.danger.Render(c.err)
+	}
+	return fmt.Sprintf("%s\n\n%s\n\n%s\n\n%s", title, c.email.View(), c.message.View(), status)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// copyMaxSize is about as much as terminals accept in one OSC 52 sequence.
+const copyMaxSize = 64 << 10
+
+// copyShortcuts are things visitors are likely to want on their clipboard.
+var copyShortcuts = map[string]string{
+	"email":  "cli@itsfred.dev",
+	"github": "https://github.com/ItsHotdogFred",
+	"itch":   "https://itshotdogfred.itch.io",
+}
+
+// copyPreview shortens text to one line for the confirmation message.
+func (m model) copyPreview(text string) string {
+	line, rest, _ := strings.Cut(text, "\n")
+	if r := []rune(line); len(r) > 40 {
+		line, rest = string(r[:40]), "…"
+	}
+	if rest != "" {
+		return m.tr("copy preview", line, len([]rune(text)))
+	}
+	return fmt.Sprintf("%q", line)
+}
+
+func copyCommand(m *model, in commandInput) (string, tea.Cmd) {
+	var text string
+	switch {
+	case in.args == "" && in.piped:
+		text = strings.TrimSuffix(ansiEscape.ReplaceAllString(in.stdin, ""), "\n")
+	case in.args == "":
+		return m.tr("copy usage"), nil
+	case copyShortcuts[in.args] != "":
+		text = copyShortcuts[in.args]
+	default:
+		// A file if there is one by that name, otherwise the text itself
+		text = in.args
+		if content, err := m.readFile(in.args); err == nil {
+			text = strings.TrimSuffix(content, "\n")
+		}
+	}
+	if text == "" {
+		return m.tr("copy empty"), nil
+	}
+	if len(text) > copyMaxSize {
+		return m.tr("copy too big"), nil
+	}
+
+	cmd := copyToClipboard(m.out, text)
+	if !in.toTerminal {
+		// Pass the text along, like tee
+		return text, cmd
+	}
+	if cmd == nil {
+		return m.tr("copy no terminal", text), nil
+	}
+	msg := m.tr("copied", m.copyPreview(text)) + "\n"
+	if strings.Contains(text, "\n") {
+		return msg + m.theme.muted.Render(m.tr("copy hint")), cmd
+	}
+	// Short things like an email address can still be copied by hand
+	return msg + m.theme.muted.Render(m.tr("copy by hand")) + "\n" + text, cmd
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// timezoneFromEnv returns the visitor's time zone from the TZ variable
+// their SSH client sends, e.g. TZ=Europe/Madrid. It's nil when TZ isn't
+// set or isn't a zone name Go knows.
+func timezoneFromEnv(env []string) *time.Location {
+	for _, kv := range env {
+		if k, v, ok := strings.Cut(kv, "="); ok && k == "TZ" && v != "" {
+			// A leading colon means "a zone name", as in TZ=:Europe/Madrid
+			if loc, err := time.LoadLocation(strings.TrimPrefix(v, ":")); err == nil {
+				return loc
+			}
+		}
+	}
+	return nil
+}
+
+// loadZone looks up a time zone, forgiving the wrong case, so utc and
+// australia/sydney work as well as UTC and Australia/Sydney.
+func loadZone(name string) (*time.Location, error) {
+	loc, err := time.LoadLocation(name)
+	if err == nil {
+		return loc, nil
+	}
+	if loc, err := time.LoadLocation(strings.ToUpper(name)); err == nil {
+		return loc, nil
+	}
+	title := []rune(strings.ToLower(name))
+	for i := range title {
+		if i == 0 || strings.ContainsRune("/_-", title[i-1]) {
+			title[i] = unicode.ToUpper(title[i])
+		}
+	}
+	if loc, err := time.LoadLocation(string(title)); err == nil {
+		return loc, nil
+	}
+	return nil, err
+}
+
+// strftime formats t with the % directives of the Unix date command.
+// Unknown directives are kept as they are.
+func strftime(t time.Time, format string) string {
+	var b strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i+1 == len(format) {
+			b.WriteByte(format[i])
+			continue
+		}
+		i++
+		switch format[i] {
+		case 'Y':
+			b.WriteString(strconv.Itoa(t.Year()))
+		case 'y':
+			b.WriteString(t.Format("06"))
+		case 'm':
+			b.WriteString(t.Format("01"))
+		case 'd':
+			b.WriteString(t.Format("02"))
+		case 'e':
+			b.WriteString(t.Format("_2"))
+		case 'H':
+			b.WriteString(t.Format("15"))
+		case 'I':
+			b.WriteString(t.Format("03"))
+		case 'M':
+			b.WriteString(t.Format("04"))
+		case 'S':
+			b.WriteString(t.Format("05"))
+		case 'p':
+			b.WriteString(t.Format("PM"))
+		case 'a':
+			b.WriteString(t.Format("Mon"))
+		case 'A':
+			b.WriteString(t.Format("Monday"))
+		case 'b', 'h':
+			b.WriteString(t.Format("Jan"))
+		case 'B':
+			b.WriteString(t.Format("January"))
+		case 'j':
+			fmt.Fprintf(&b, "%03d", t.YearDay())
+		case 'u':
+			b.WriteString(strconv.Itoa((int(t.Weekday())+6)%7 + 1))
+		case 'Z':
+			b.WriteString(t.Format("MST"))
+		case 'z':
+			b.WriteString(t.Format("-0700"))
+		case 's':
+			b.WriteString(strconv.FormatInt(t.Unix(), 10))
+		case 'F':
+			b.WriteString(t.Format("2006-01-02"))
+		case 'T':
+			b.WriteString(t.Format("15:04:05"))
+		case 'R':
+			b.WriteString(t.Format("15:04"))
+		case 'D':
+			b.WriteString(t.Format("01/02/06"))
+		case 'c':
+			b.WriteString(t.Format(time.UnixDate))
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case '%':
+			b.WriteByte('%')
+		default:
+			b.WriteByte('%')
+			b.WriteByte(format[i])
+		}
+	}
+	return b.String()
+}
+
+func dateCommand(m *model, in commandInput) (string, tea.Cmd) {
+	var (
+		loc    *time.Location
+		format string
+	)
+	for _, arg := range in.argv {
+		switch {
+		case strings.HasPrefix(arg, "+"):
+			format = arg[1:]
+		case loc == nil:
+			zone, err := loadZone(arg)
+			if err != nil {
+				return m.tr("date zone", arg), nil
+			}
+			loc = zone
+		default:
+			return m.tr("date usage"), nil
+		}
+	}
+
+	now := time.Now()
+	// A zone or a format asks for a single time, in the visitor's zone if
+	// nothing else is said, so date +%H:%M pipes like the real thing
+	if loc != nil || format != "" {
+		switch {
+		case loc != nil:
+			now = now.In(loc)
+		case m.timezone != nil:
+			now = now.In(m.timezone)
+		}
+		if format == "" {
+			return now.Format(time.UnixDate), nil
+		}
+		return strftime(now, format), nil
+	}
+
+	out := m.tr("date", now.Format(time.UnixDate))
+	if m.timezone != nil {
+		out += "\n" + m.tr("date local", now.In(m.timezone).Format(time.UnixDate), m.timezone)
+	}
+	return out, nil
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// A demo script is a text file with one command per line, typed into the
+// prompt as if a visitor was using the portfolio. Blank lines and lines
+// starting with # are ignored, and "@pause <duration>" waits before the next
+// line, e.g.
+//
+//	ls
+//	@pause 2s
+//	cat About/bio.txt
+//	@pause 4s
+
+const (
+	demoTypingDelay  = 70 * time.Millisecond  // between typed characters
+	demoEnterDelay   = 400 * time.Millisecond // after typing, before enter
+	demoCommandPause = 1500 * time.Millisecond
+	// demoIdleRestart is how long a kiosk waits after the last key press
+	// before starting the demo again.
+	demoIdleRestart = time.Minute
+)
+
+// defaultDemoScript is played by demo and --demo when no file is given.
+const defaultDemoScript = `# A quick tour of the portfolio
+help
+@pause 3s
+ls
+@pause 2s
+cat About/bio.txt
+@pause 4s
+skills
+@pause 2s
+tree
+@pause 3s
+ls | grep md
+@pause 2s
+echo hello there | yoda
+@pause 2s
+neofetch
+@pause 4s
+contact
+@pause 3s
+`
+
+type demoStep struct {
+	command string        // typed and run, empty for a pause
+	pause   time.Duration // how long to wait when command is empty
+}
+
+// parseDemoScript turns a script into steps.
+func parseDemoScript(script string) ([]demoStep, error) {
+	var steps []demoStep
+	for n, line := range strings.Split(script, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, "@pause "):
+			d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(line, "@pause ")))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", n+1, err)
+			}
+			steps = append(steps, demoStep{pause: d})
+		case strings.HasPrefix(line, "@"):
+			return nil, fmt.Errorf("line %d: unknown directive %s", n+1, line)
+		default:
+			steps = append(steps, demoStep{command: line})
+		}
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("the script has no commands")
+	}
+	return steps, nil
+}
+
+// demoTickMsg advances a demo. The id ties it to one run so a stopped demo's
+// ticks don't speed up the next one.
+type demoTickMsg struct{ id int64 }
+
+// demoIdleMsg asks a kiosk to check whether it has been idle long enough to
+// restart the demo.
+type demoIdleMsg struct{}
+
+var demoRuns atomic.Int64
+
+// demoPlayer is the state of a demo that is playing.
+type demoPlayer struct {
+	id    int64
+	steps []demoStep
+	step  int  // index of the current step
+	typed int  // characters of the current command typed so far
+	loop  bool // start over at the end instead of stopping
+}
+
+func (d *demoPlayer) tick(delay time.Duration) tea.Cmd {
+	id := d.id
+	return tea.Tick(delay, func(time.Time) tea.Msg { return demoTickMsg{id: id} })
+}
+
+// startDemo plays steps, returning the command for the first tick.
+func (m *model) startDemo(steps []demoStep, loop bool) tea.Cmd {
+	m.demo = &demoPlayer{id: demoRuns.Add(1), steps: steps, loop: loop}
+	return m.demo.tick(demoCommandPause)
+}
+
+// stopDemo hands control back to the visitor. A kiosk schedules a check to
+// restart the demo once nobody has touched it for a while.
+func (m *model) stopDemo() tea.Cmd {
+	m.demo = nil
+	m.input.Reset()
+	m.print("Demo stopped, the portfolio is all yours.")
+	m.refreshViewport()
+	m.viewport.GotoBottom()
+	if m.kioskScript == nil {
+		return nil
+	}
+	return tea.Tick(demoIdleRestart, func(time.Time) tea.Msg { return demoIdleMsg{} })
+}
+
+// updateDemo handles demo messages and key presses while a demo plays.
+// The bool is false if msg should be handled as usual.
+func (m model) updateDemo(msg tea.Msg) (model, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.demo == nil {
+			return m, nil, false
+		}
+		return m, m.stopDemo(), true
+	case demoIdleMsg:
+		if m.demo != nil || m.kioskScript == nil {
+			return m, nil, true
+		}
+		if idle := time.Since(m.lastInput); idle < demoIdleRestart {
+			return m, tea.Tick(demoIdleRestart-idle, func(time.Time) tea.Msg { return demoIdleMsg{} }), true
+		}
+		return m, m.startDemo(m.kioskScript, true), true
+	case demoTickMsg:
+		if m.demo == nil || msg.id != m.demo.id {
+			return m, nil, true
+		}
+		return m, m.advanceDemo(), true
+	}
+	return m, nil, false
+}
+
+// advanceDemo performs the next bit of the current step: typing a character,
+// pressing enter, or waiting.
+func (m *model) advanceDemo() tea.Cmd {
+	d := m.demo
+	if d.step >= len(d.steps) {
+		if !d.loop {
+			m.demo = nil
+			m.print("That's the end of the demo!")
+			m.refreshViewport()
+			m.viewport.GotoBottom()
+			return nil
+		}
+		d.step = 0
+		m.run("clear")
+	}
+
+	// Whatever the last command opened has had its moment
+	m.app = nil
+	m.fileViewMode = false
+
+	step := d.steps[d.step]
+	if step.command == "" {
+		d.step++
+		return d.tick(step.pause)
+	}
+
+	command := []rune(step.command)
+	if d.typed < len(command) {
+		d.typed++
+		m.input.SetValue(string(command[:d.typed]))
+		m.input.CursorEnd()
+		if d.typed == len(command) {
+			return d.tick(demoEnterDelay)
+		}
+		// A little jitter makes the typing look human
+		return d.tick(demoTypingDelay + time.Duration(rand.Intn(60))*time.Millisecond)
+	}
+
+	m.input.Reset()
+	cmd := m.run(step.command)
+	m.refreshViewport()
+	m.viewport.GotoBottom()
+	d.step++
+	d.typed = 0
+	return tea.Batch(cmd, d.tick(demoCommandPause))
+}
+
+func demoCommand(m *model, in commandInput) (string, tea.Cmd) {
+	script := defaultDemoScript
+	if in.args != "" {
+		content, err := m.readFile(in.args)
+		if err != nil {
+			return err.Error(), nil
+		}
+		script = content
+	}
+	steps, err := parseDemoScript(script)
+	if err != nil {
+		return "demo: " + err.Error(), nil
+	}
+	return "Starting the demo, press any key to take over.", m.startDemo(steps, false)
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+)
+
+const (
+	// downloadTTL is how long a download link works if nobody uses it.
+	downloadTTL = 10 * time.Minute
+	// downloadMaxSize caps what can be downloaded, directories included.
+	downloadMaxSize = 20 << 20
+	// Files wait in memory until they're fetched, so there's a cap on how
+	// much can wait for one IP address, and for everyone together.
+	downloadMaxPendingPerIP = 2 * downloadMaxSize
+	downloadMaxPending      = 10 * downloadMaxSize
+)
+
+var (
+	errDownloadLimit = errors.New("too many downloads are waiting for you")
+	errDownloadsBusy = errors.New("too many downloads are waiting")
+)
+
+// downloads serves files to SSH visitors over HTTP, nil when the server was
+// started without --download-addr.
+var downloads *downloadServer
+
+// download is a file waiting to be fetched from a one-time link.
+type download struct {
+	ip      string // of the visitor who asked for it
+	name    string
+	content []byte
+	expires time.Time
+}
+
+type downloadServer struct {
+	baseURL string // public URL the links start with
+
+	mu      sync.Mutex
+	pending map[string]download // by token
+}
+
+// startDownloads serves download links on addr in the background. Links are
+// handed out as baseURL/d/<token>.
+func startDownloads(addr, baseURL string) {
+	d := &downloadServer{baseURL: strings.TrimSuffix(baseURL, "/"), pending: map[string]download{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/d/", d.serve)
+	srv := &http.Server{
+		Addr:         addr,
+		Handler:      mux,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: time.Minute,
+	}
+	log.Info("Starting download server", "address", addr, "url", d.baseURL)
+	ln, err := listen("downloads", addr)
+	if err != nil {
+		log.Error("Could not start download server", "error", err)
+		return
+	}
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) && !closedListener(err) {
+			log.Error("Could not start download server", "error", err)
+		}
+	}()
+	downloads = d
+}
+
+// add stores a file for the visitor at ip and returns the link to fetch it.
+// It fails with errDownloadLimit or errDownloadsBusy if there's already too
+// much waiting.
+func (d *downloadServer) add(ip, name string, content []byte) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := time.Now()
+	total, mine := len(content), len(content)
+	for t, dl := range d.pending {
+		if now.After(dl.expires) {
+			delete(d.pending, t)
+			continue
+		}
+		total += len(dl.content)
+		if dl.ip == ip {
+			mine += len(dl.content)
+		}
+	}
+	switch {
+	case mine > downloadMaxPendingPerIP:
+		return "", errDownloadLimit
+	case total > downloadMaxPending:
+		return "", errDownloadsBusy
+	}
+	d.pending[token] = download{ip: ip, name: name, content: content, expires: now.Add(downloadTTL)}
+	return d.baseURL + "/d/" + token, nil
+}
+
+// serve hands over a file and forgets its token, so each link works once.
+func (d *downloadServer) serve(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimPrefix(r.URL.Path, "/d/")
+	d.mu.Lock()
+	dl, ok := d.pending[token]
+	delete(d.pending, token)
+	d.mu.Unlock()
+	if !ok || time.Now().After(dl.expires) {
+		http.Error(w, "This download link has expired or was already used.", http.StatusNotFound)
+		return
+	}
+	log.Info("Serving download", "file", dl.name, "remote", r.RemoteAddr)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", dl.name))
+	http.ServeContent(w, r, dl.name, time.Now(), bytes.NewReader(dl.content))
+}
+
+// zipDir archives the visible files below dir.
+func zipDir(dir string) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	err := filepath.WalkDir(dir, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p != dir && strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() || !insideRoot(p) {
+			return nil
+		}
+		// Don't read a file that can't fit anyway
+		if info, err := entry.Info(); err == nil && int64(buf.Len())+info.Size() > downloadMaxSize {
+			return errTooBig
+		}
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		f, err := zw.Create(filepath.ToSlash(rel))
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(content); err != nil {
+			return err
+		}
+		if buf.Len() > downloadMaxSize {
+			return errTooBig
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// errTooBig stops zipDir once the archive passes downloadMaxSize.
+var errTooBig = errors.New("it's too big to download")
+
+func downloadCommand(m *model, in commandInput) (string, tea.Cmd) {
+	if in.args == "" {
+		return m.tr("download usage"), nil
+	}
+	if downloads == nil {
+		return m.tr("download disabled"), nil
+	}
+	// Nothing hidden, like the host key, and nothing outside the portfolio
+	p, err := m.resolve(in.args)
+	if err != nil {
+		return err.Error(), nil
+	}
+
+	name := path.Base(p)
+	var content []byte
+	info, statErr := os.Stat(hostPath(p))
+	if statErr == nil && info.IsDir() {
+		if name == "." {
+			name = "portfolio"
+		}
+		archive, err := zipDir(hostPath(p))
+		if errors.Is(err, errTooBig) {
+			return m.tr("download too big", in.args), nil
+		}
+		if err != nil {
+			return m.tr("download archive", in.args, visitorError(err)), nil
+		}
+		name, content = name+".zip", archive
+	} else {
+		// Check the size on disk first, so a big file isn't read just to be
+		// turned away
+		if statErr == nil && info.Size() > downloadMaxSize {
+			return m.tr("download too big", in.args), nil
+		}
+		text, err := m.readFile(in.args)
+		if err != nil {
+			return err.Error(), nil
+		}
+		if len(text) > downloadMaxSize {
+			return m.tr("download too big", in.args), nil
+		}
+		content = []byte(text)
+	}
+
+	link, err := downloads.add(m.ip, name, content)
+	switch {
+	case errors.Is(err, errDownloadLimit):
+		return m.tr("download limit", int(downloadTTL.Minutes())), nil
+	case errors.Is(err, errDownloadsBusy):
+		return m.tr("download busy"), nil
+	case err != nil:
+		return "download: " + err.Error(), nil
+	}
+	if !in.toTerminal {
+		return link, nil
+	}
+	return m.tr("download link", name, int(downloadTTL.Minutes()), link, qrCode(link, "L", false, m.viewport.Width)), nil
+}
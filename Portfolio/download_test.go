@@ -0,0 +1,38 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDownloadLimits(t *testing.T) {
+	d := &downloadServer{pending: map[string]download{}}
+	half := make([]byte, downloadMaxSize/2)
+	full := make([]byte, downloadMaxSize)
+
+	// One visitor can fill their share, and no more
+	for i := 0; i < downloadMaxPendingPerIP/downloadMaxSize; i++ {
+		if _, err := d.add("10.0.0.1", "full.txt", full); err != nil {
+			t.Fatalf("add %d for 10.0.0.1: %v", i, err)
+		}
+	}
+	if _, err := d.add("10.0.0.1", "half.txt", half); !errors.Is(err, errDownloadLimit) {
+		t.Errorf("add past the per-visitor cap = %v, want %v", err, errDownloadLimit)
+	}
+
+	// Others can add until everything together is full
+	var err error
+	for i := 0; err == nil; i++ {
+		_, err = d.add(string(rune('a'+i)), "full.txt", full)
+	}
+	if !errors.Is(err, errDownloadsBusy) {
+		t.Errorf("add past the overall cap = %v, want %v", err, errDownloadsBusy)
+	}
+	total := 0
+	for _, dl := range d.pending {
+		total += len(dl.content)
+	}
+	if total > downloadMaxPending {
+		t.Errorf("%d bytes waiting, want at most %d", total, downloadMaxPending)
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// easterEgg is a hidden response to a command that doesn't otherwise exist.
+// Eggs only fire when no real command matches, so they can't shadow one.
+type easterEgg struct {
+	command string // command name that triggers the egg
+	args    string // exact arguments required, empty matches any
+	output  string // canned response
+	// animation, if set, is played instead of printing output
+	animation func() animation
+}
+
+// easterEggs is checked in order, so put eggs with specific args before the
+// catch-all for the same command.
+var easterEggs = []easterEgg{
+	{command: "sudo", args: "make me a sandwich", output: "Okay. 🥪"},
+	{command: "sudo", args: "rm -rf /", output: "Nice try. This portfolio is read-only, even for root. 😄"},
+	{command: "sudo", output: "visitor is not in the sudoers file. This incident will be reported. 🚨"},
+	{command: "make", args: "me a sandwich", output: "What? Make it yourself."},
+	{command: "rm", args: "-rf /", output: "rm: it is dangerous to operate recursively on '/'\nrm: luckily this whole portfolio is read-only anyway 😌"},
+	{command: "rm", output: "rm: this portfolio is read-only, nothing was harmed"},
+	{command: "vim", output: "You are now stuck in vim forever.\nJust kidding! Files here are read-only, try cat instead."},
+	{command: "vi", output: "You are now stuck in vi forever.\nJust kidding! Files here are read-only, try cat instead."},
+	{command: "nano", output: "Files here are read-only, try cat instead."},
+	{command: "emacs", output: "A great operating system, lacking only a decent editor. Try cat instead."},
+	{command: "hello", output: "Hi there! 👋 Type help to see what you can do."},
+	{command: "sl", animation: newTrain},
+}
+
+// findEasterEgg returns a command that runs the egg matching name and args.
+func findEasterEgg(name, args string) (commandFunc, bool) {
+	for _, egg := range easterEggs {
+		if egg.command != name || (egg.args != "" && egg.args != args) {
+			continue
+		}
+		egg := egg
+		return func(m *model, in commandInput) (string, tea.Cmd) {
+			if egg.animation != nil && in.toTerminal {
+				return "", m.playAnimation(egg.animation(), animationTick)
+			}
+			return egg.output, nil
+		}, true
+	}
+	return nil, false
+}
+
+var trainSmoke = [][]string{
+	{
+		"                      (@@) (  ) (@)  ( )  @@    ()    @",
+		"                 (   )",
+		"             (@@@@)",
+		"          (    )",
+		"",
+		"        (@@@)",
+	},
+	{
+		"                      (  ) (@@) ( )  (@)  ()    @@    O",
+		"                 (@@@)",
+		"             (    )",
+		"          (@@@@)",
+		"",
+		"        (   )",
+	},
+}
+
+var trainBody = []string{
+	"      ====        ________                ___________ ",
+	"  _D _|  |_______/        \\__I_I_____===__|_________| ",
+	"   |(_)---  |   H\\________/ |   |        =|___ ___|   ",
+	"   /     |  |   H  |  |     |   |         ||_| |_||   ",
+	"  |      |  |   H  |__--------------------| [___] |   ",
+	"  | ________|___H__/__|_____/[][]~\\_______|       |   ",
+	"  |/ |   |-----------I_____I [][] []  D   |=======|__ ",
+}
+
+var trainWheels = [][]string{
+	{
+		"__/ =| o |=-~~\\  /~~\\  /~~\\  /~~\\ ____Y___________|__ ",
+		" |/-=|___|=    ||    ||    ||    |_____/~\\___/        ",
+		"  \\_/      \\O=====O=====O=====O_/      \\_/            ",
+	},
+	{
+		"__/ =| o |=-~~\\  /~~\\  /~~\\  /~~\\ ____Y___________|__ ",
+		" |/-=|___|=O=====O=====O=====O   |_____/~\\___/        ",
+		"  \\_/      \\__/  \\__/  \\__/  \\__/      \\_/            ",
+	},
+}
+
+// train is the sl easter egg: a steam locomotive crossing the screen.
+type train struct {
+	frame, width, height int
+}
+
+func newTrain() animation {
+	return &train{}
+}
+
+func (t *train) resize(width, height int) {
+	t.width, t.height = width, height
+}
+
+func (t *train) step() bool {
+	t.frame++
+	// The train enters from the right edge and is done once it's off the left
+	return t.width-t.frame >= -len(trainBody[0])
+}
+
+func (t *train) draw(c *canvas) {
+	var lines []string
+	lines = append(lines, trainSmoke[(t.frame/4)%len(trainSmoke)]...)
+	lines = append(lines, trainBody...)
+	lines = append(lines, trainWheels[(t.frame/2)%len(trainWheels)]...)
+
+	top := max(0, (t.height-len(lines))/2)
+	for i, line := range lines {
+		c.text(t.width-t.frame, top+i, line, "")
+	}
+}
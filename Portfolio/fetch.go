@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Commands that talk to web APIs share one client so a slow service can't
+// hang a session forever.
+const apiTimeout = 8 * time.Second
+
+var apiClient = &http.Client{Timeout: apiTimeout}
+
+// getJSON fetches url and decodes the JSON response into v.
+func getJSON(url string, v any) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	return doJSON(req, v)
+}
+
+// doJSON sends req and decodes the JSON response into v.
+func doJSON(req *http.Request, v any) error {
+	resp, err := apiClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return &statusError{code: resp.StatusCode, status: resp.Status}
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// statusError is returned by doJSON for any response other than 200 OK.
+type statusError struct {
+	code   int
+	status string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status %s", e.status)
+}
+
+// describeFetchError turns timeouts into something friendlier than Go's
+// "Client.Timeout exceeded while awaiting headers".
+func describeFetchError(err error) string {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return "the service took too long to answer, try again later"
+	}
+	return err.Error()
+}
+
+// fetchResultMsg carries a finished fetch back to the loader that started
+// it, so a cancelled fetch can't answer a later one.
+type fetchResultMsg struct {
+	loader *loaderModel
+	out    string
+}
+
+// loaderModel shows a spinner while fetch runs in the background, then
+// hands its output to the shell.
+type loaderModel struct {
+	theme   *theme
+	label   string
+	fetch   func() string
+	spinner spinner.Model
+}
+
+func newLoader(t *theme, label string, fetch func() string) *loaderModel {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = t.header
+	return &loaderModel{theme: t, label: label, fetch: fetch, spinner: s}
+}
+
+func (l *loaderModel) Init() tea.Cmd {
+	fetch := func() tea.Msg {
+		return fetchResultMsg{loader: l, out: l.fetch()}
+	}
+	return tea.Batch(l.spinner.Tick, fetch)
+}
+
+func (l *loaderModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "ctrl+c", "q":
+			return l, exitApp("", 0, "Cancelled.")
+		}
+	case fetchResultMsg:
+		if msg.loader != l {
+			return l, nil
+		}
+		return l, exitApp("", 0, msg.out)
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		l.spinner, cmd = l.spinner.Update(msg)
+		return l, cmd
+	}
+	return l, nil
+}
+
+func (l *loaderModel) View() string {
+	return fmt.Sprintf("%s %s...\n\n%s", l.spinner.View(), l.label, l.theme.muted.Render("(esc to cancel)"))
+}
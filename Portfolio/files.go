@@ -0,0 +1,147 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// portfolioRoot is the directory on disk that visitors see as ~. Paths are
+// resolved inside it like in a chroot, and nothing outside it can be read.
+var portfolioRoot = "."
+
+// dirEntry is a file or directory visible to the visitor, either on disk or
+// created during the session by output redirection.
+type dirEntry struct {
+	name  string
+	isDir bool
+}
+
+// resolve turns a name relative to the current directory into a clean path
+// relative to the portfolio root, used as the key for session files and,
+// through hostPath, for reading from disk. ~ is the root itself. Absolute
+// paths, paths that climb out of the root with .., hidden files and symlinks
+// pointing outside the root are all refused.
+func (m model) resolve(name string) (string, error) {
+	p := path.Join(m.directory, name)
+	switch {
+	case name == "~":
+		p = "."
+	case strings.HasPrefix(name, "~/"):
+		p = path.Clean(name[2:])
+	case path.IsAbs(name):
+		return "", errors.New(m.tr("outside root", name))
+	}
+	if p == ".." || strings.HasPrefix(p, "../") {
+		return "", errors.New(m.tr("outside root", name))
+	}
+	for _, part := range strings.Split(p, "/") {
+		if strings.HasPrefix(part, ".") && part != "." {
+			return "", errors.New(m.tr("hidden file"))
+		}
+	}
+	if !insideRoot(hostPath(p)) {
+		return "", errors.New(m.tr("outside root", name))
+	}
+	return p, nil
+}
+
+// hostPath returns where a path from resolve lives on disk.
+func hostPath(p string) string {
+	return filepath.Join(portfolioRoot, filepath.FromSlash(p))
+}
+
+// insideRoot reports whether a file on disk, once its symlinks are followed,
+// is still inside the portfolio root. Files that don't exist can't lead
+// anywhere, so they count as inside.
+func insideRoot(file string) bool {
+	target, err := filepath.EvalSymlinks(file)
+	if err != nil {
+		return true
+	}
+	root, err := filepath.EvalSymlinks(portfolioRoot)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(root, target)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// visitorError rewrites the path in a file system error from where the file
+// is on disk to the path visitors see, so errors don't give away where the
+// portfolio lives.
+func visitorError(err error) error {
+	var pathErr *fs.PathError
+	if !errors.As(err, &pathErr) {
+		return err
+	}
+	rel, relErr := filepath.Rel(portfolioRoot, pathErr.Path)
+	if relErr != nil {
+		return errors.New(pathErr.Op + ": " + pathErr.Err.Error())
+	}
+	return &fs.PathError{Op: pathErr.Op, Path: filepath.ToSlash(rel), Err: pathErr.Err}
+}
+
+// readFile returns the contents of a file, preferring session files over disk.
+func (m model) readFile(name string) (string, error) {
+	p, err := m.resolve(name)
+	if err != nil {
+		return "", err
+	}
+	if content, ok := m.sessionFiles[p]; ok {
+		return content, nil
+	}
+	content, err := os.ReadFile(hostPath(p))
+	if err != nil {
+		return "", errors.New(m.tr("read file error", visitorError(err)))
+	}
+	return string(content), nil
+}
+
+// readDir lists the visible entries of a directory, including session files.
+func (m model) readDir(name string) ([]dirEntry, error) {
+	dir, err := m.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(hostPath(dir))
+	if err != nil {
+		return nil, visitorError(err)
+	}
+
+	var list []dirEntry
+	for _, entry := range entries {
+		// Skip hidden files/folders (those starting with .)
+		if !strings.HasPrefix(entry.Name(), ".") {
+			list = append(list, dirEntry{name: entry.Name(), isDir: entry.IsDir()})
+		}
+	}
+	for p := range m.sessionFiles {
+		if path.Dir(p) == dir {
+			list = append(list, dirEntry{name: path.Base(p)})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].name < list[j].name })
+	return list, nil
+}
+
+// writeFile stores content as a session file. Files that exist on disk are
+// part of the portfolio and can't be overwritten.
+func (m *model) writeFile(name, content string, appendTo bool) error {
+	p, err := m.resolve(name)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(hostPath(p)); err == nil {
+		return errors.New(m.tr("read only", name))
+	}
+	if appendTo {
+		content = m.sessionFiles[p] + content
+	}
+	m.sessionFiles[p] = content
+	return nil
+}
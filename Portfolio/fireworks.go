@@ -0,0 +1,126 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	fireworksInterval = 50 * time.Millisecond
+	fireworksGravity  = 0.02 // rows per frame, per frame
+	// Terminal cells are about twice as tall as they are wide, so sideways
+	// speeds are doubled to make bursts round.
+	fireworksAspect = 2
+)
+
+// spark is a rocket on its way up, or a piece of one that has burst.
+type spark struct {
+	x, y   float64
+	vx, vy float64
+	life   int // frames left, rockets burst when it runs out
+	color  lipgloss.Color
+}
+
+type fireworks struct {
+	width, height int
+	colors        []lipgloss.Color
+	rockets       []spark
+	sparks        []spark
+}
+
+func fireworksCommand(m *model, in commandInput) (string, tea.Cmd) {
+	c := m.theme.colors
+	f := &fireworks{colors: []lipgloss.Color{c.accent, c.success, c.danger, c.warning, c.info, c.folder}}
+	return "", m.playAnimation(f, fireworksInterval)
+}
+
+func (f *fireworks) resize(width, height int) {
+	f.width, f.height = width, height
+}
+
+// launch sends up a rocket that bursts somewhere in the top half of the screen.
+func (f *fireworks) launch() {
+	h := float64(f.height)
+	// The speed that stops rising just as it reaches the burst height
+	rise := h*0.5 + rand.Float64()*h*0.4
+	vy := -math.Sqrt(2 * fireworksGravity * rise)
+	f.rockets = append(f.rockets, spark{
+		x:     float64(f.width/6 + rand.Intn(max(1, f.width*2/3))),
+		y:     h - 1,
+		vx:    (rand.Float64() - 0.5) * 0.3,
+		vy:    vy,
+		life:  int(-vy / fireworksGravity),
+		color: f.colors[rand.Intn(len(f.colors))],
+	})
+}
+
+// burst scatters a rocket into a ring of sparks.
+func (f *fireworks) burst(r spark) {
+	n := 24 + rand.Intn(24)
+	power := 0.4 + rand.Float64()*0.4
+	for i := 0; i < n; i++ {
+		angle := 2 * math.Pi * float64(i) / float64(n)
+		speed := power * (0.6 + rand.Float64()*0.4)
+		f.sparks = append(f.sparks, spark{
+			x:     r.x,
+			y:     r.y,
+			vx:    math.Cos(angle) * speed * fireworksAspect,
+			vy:    math.Sin(angle) * speed,
+			life:  20 + rand.Intn(20),
+			color: r.color,
+		})
+	}
+}
+
+func (f *fireworks) step() bool {
+	if (len(f.rockets) < 3 && rand.Intn(12) == 0) || len(f.rockets)+len(f.sparks) == 0 {
+		f.launch()
+	}
+
+	rockets := f.rockets[:0]
+	for _, r := range f.rockets {
+		r.x, r.y = r.x+r.vx, r.y+r.vy
+		r.vy += fireworksGravity
+		if r.life--; r.life <= 0 {
+			f.burst(r)
+			continue
+		}
+		rockets = append(rockets, r)
+	}
+	f.rockets = rockets
+
+	sparks := f.sparks[:0]
+	for _, s := range f.sparks {
+		s.x, s.y = s.x+s.vx, s.y+s.vy
+		// Air slows the sparks down while they fall
+		s.vx *= 0.92
+		s.vy = s.vy*0.92 + fireworksGravity
+		if s.life--; s.life > 0 && s.y < float64(f.height) {
+			sparks = append(sparks, s)
+		}
+	}
+	f.sparks = sparks
+	return true
+}
+
+func (f *fireworks) draw(c *canvas) {
+	for _, r := range f.rockets {
+		c.set(int(r.x), int(r.y)+1, '.', r.color)
+		c.set(int(r.x), int(r.y), '^', r.color)
+	}
+	for _, s := range f.sparks {
+		// Sparks fade as they burn out
+		glyph := '*'
+		switch {
+		case s.life < 8:
+			glyph = '.'
+		case s.life < 16:
+			glyph = '+'
+		}
+		c.set(int(math.Round(s.x)), int(math.Round(s.y)), glyph, s.color)
+	}
+}
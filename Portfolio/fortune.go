@@ -0,0 +1,116 @@
+package main
+
+import (
+	_ "embed"
+	"math/rand"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The fortunes use the classic fortune file format: entries separated by
+// lines holding a single %.
+//
+//go:embed Extra/fortunes.txt
+var fortuneFile string
+
+// cowsayMaxWidth is the widest a speech bubble's text gets, like cowsay's
+// default of 40 columns.
+const cowsayMaxWidth = 40
+
+const cow = `        \   ^__^
+         \  (oo)\_______
+            (__)\       )\/\
+                ||----w |
+                ||     ||`
+
+// fortunes splits the embedded file into its entries.
+func fortunes() []string {
+	var list []string
+	for _, f := range strings.Split(fortuneFile, "\n%\n") {
+		if f = strings.TrimSpace(f); f != "" {
+			list = append(list, f)
+		}
+	}
+	return list
+}
+
+func fortuneCommand(m *model, in commandInput) (string, tea.Cmd) {
+	list := fortunes()
+	return list[rand.Intn(len(list))], nil
+}
+
+// wrapWords breaks text into lines at most width columns wide, splitting
+// words that don't fit on a line of their own. Line breaks in text are kept.
+func wrapWords(text string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			for lipgloss.Width(word) > width {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				r := []rune(word)
+				lines = append(lines, string(r[:width]))
+				word = string(r[width:])
+			}
+			switch {
+			case line == "":
+				line = word
+			case lipgloss.Width(line)+1+lipgloss.Width(word) <= width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// cowsay draws a cow saying text, wrapped to fit width columns.
+func cowsay(text string, width int) string {
+	// The bubble adds four columns around the text
+	lines := wrapWords(text, max(10, min(cowsayMaxWidth, width-4)))
+	longest := 0
+	for _, line := range lines {
+		longest = max(longest, lipgloss.Width(line))
+	}
+
+	var b strings.Builder
+	b.WriteString(" " + strings.Repeat("_", longest+2) + "\n")
+	for i, line := range lines {
+		left, right := "|", "|"
+		switch {
+		case len(lines) == 1:
+			left, right = "<", ">"
+		case i == 0:
+			left, right = "/", "\\"
+		case i == len(lines)-1:
+			left, right = "\\", "/"
+		}
+		b.WriteString(left + " " + line + strings.Repeat(" ", longest-lipgloss.Width(line)) + " " + right + "\n")
+	}
+	b.WriteString(" " + strings.Repeat("-", longest+2) + "\n")
+	b.WriteString(cow)
+	return b.String()
+}
+
+func cowsayCommand(m *model, in commandInput) (string, tea.Cmd) {
+	text := in.args
+	if text == "" {
+		text = strings.TrimSpace(ansiEscape.ReplaceAllString(in.stdin, ""))
+	}
+	if text == "" {
+		return m.tr("cowsay usage"), nil
+	}
+	width := cowsayMaxWidth + 4
+	if m.ready {
+		width = m.viewport.Width
+	}
+	return cowsay(text, width), nil
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	githubAPI         = "https://api.github.com"
+	githubDefaultUser = "ItsHotdogFred"
+	githubCacheTTL    = 10 * time.Minute
+	githubTopRepos    = 8
+	githubGraphWeeks  = 12
+)
+
+// githubUserPattern matches GitHub's rules for user names, so nothing odd
+// ends up in an API URL.
+var githubUserPattern = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9-]{0,38})$`)
+
+// githubLevels are the greens of the graph on github.com, from a little
+// activity to lots. Days without any use the theme's subtle colour.
+var githubLevels = []lipgloss.Color{"#0E4429", "#006D32", "#26A641", "#39D353"}
+
+type githubRepo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Language    string `json:"language"`
+	Stars       int    `json:"stargazers_count"`
+	Fork        bool   `json:"fork"`
+}
+
+type githubEvent struct {
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type githubProfile struct {
+	Login       string `json:"login"`
+	Name        string `json:"name"`
+	Followers   int    `json:"followers"`
+	PublicRepos int    `json:"public_repos"`
+	repos       []githubRepo
+	events      []githubEvent
+}
+
+// githubCache keeps profiles for githubCacheTTL. Unauthenticated requests
+// are limited to 60 an hour per IP, and every visitor shares the server's IP.
+var githubCache = struct {
+	sync.Mutex
+	entries map[string]githubCacheEntry
+}{entries: map[string]githubCacheEntry{}}
+
+type githubCacheEntry struct {
+	fetched time.Time
+	profile *githubProfile
+}
+
+func githubGet(path string, v any) error {
+	req, err := http.NewRequest("GET", githubAPI+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	// A token is optional but raises the rate limit to 5000 an hour
+	if token := os.Getenv("PORTFOLIO_GITHUB_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return doJSON(req, v)
+}
+
+// fetchGitHub returns the profile for user, from the cache if it's fresh.
+func fetchGitHub(user string) (*githubProfile, error) {
+	key := strings.ToLower(user)
+	githubCache.Lock()
+	entry, ok := githubCache.entries[key]
+	githubCache.Unlock()
+	if ok && time.Since(entry.fetched) < githubCacheTTL {
+		return entry.profile, nil
+	}
+
+	profile := &githubProfile{}
+	if err := githubGet("/users/"+user, profile); err != nil {
+		return nil, err
+	}
+	if err := githubGet("/users/"+user+"/repos?per_page=100&sort=updated", &profile.repos); err != nil {
+		return nil, err
+	}
+	// The events API covers the last 90 days, at most 300 events
+	for page := 1; page <= 3; page++ {
+		var events []githubEvent
+		if err := githubGet(fmt.Sprintf("/users/%s/events/public?per_page=100&page=%d", user, page), &events); err != nil {
+			return nil, err
+		}
+		profile.events = append(profile.events, events...)
+		if len(events) < 100 {
+			break
+		}
+	}
+
+	githubCache.Lock()
+	githubCache.entries[key] = githubCacheEntry{fetched: time.Now(), profile: profile}
+	githubCache.Unlock()
+	return profile, nil
+}
+
+func (p *githubProfile) render(t *theme) string {
+	var b strings.Builder
+
+	stars := 0
+	var repos []githubRepo
+	for _, repo := range p.repos {
+		stars += repo.Stars
+		if !repo.Fork {
+			repos = append(repos, repo)
+		}
+	}
+	sort.SliceStable(repos, func(i, j int) bool { return repos[i].Stars > repos[j].Stars })
+
+	title := p.Login
+	if p.Name != "" && p.Name != p.Login {
+		title = p.Name + " (" + p.Login + ")"
+	}
+	b.WriteString(t.header.Render(title) + "\n")
+	fmt.Fprintf(&b, "%d public repos • %s %d stars • %d followers\n",
+		p.PublicRepos, t.warning.Render("★"), stars, p.Followers)
+	b.WriteString(t.muted.Render("github.com/"+p.Login) + "\n\n")
+
+	b.WriteString(t.header.Render("Top repositories") + "\n")
+	if len(repos) == 0 {
+		b.WriteString(t.muted.Render("  No public repositories yet") + "\n")
+	}
+	for _, repo := range repos[:min(len(repos), githubTopRepos)] {
+		description := repo.Description
+		if len(description) > 50 {
+			description = description[:47] + "..."
+		}
+		fmt.Fprintf(&b, "  %s %-4d %-24s %s %s\n",
+			t.warning.Render("★"), repo.Stars, repo.Name,
+			t.info.Render(fmt.Sprintf("%-12s", repo.Language)), t.muted.Render(description))
+	}
+
+	b.WriteString("\n" + t.header.Render(fmt.Sprintf("Public activity, last %d weeks", githubGraphWeeks)) + "\n")
+	b.WriteString(p.graph(t))
+	return b.String()
+}
+
+// graph draws public events per day as a grid of weeks, oldest on the left,
+// like the contribution graph on a GitHub profile.
+func (p *githubProfile) graph(t *theme) string {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	// Start on a Sunday so each column is one calendar week
+	start := today.AddDate(0, 0, -int(today.Weekday())-7*(githubGraphWeeks-1))
+
+	counts := make([]int, githubGraphWeeks*7)
+	for _, event := range p.events {
+		day := int(event.CreatedAt.UTC().Truncate(24*time.Hour).Sub(start).Hours() / 24)
+		if day >= 0 && day < len(counts) {
+			counts[day]++
+		}
+	}
+	busiest := 0
+	for _, n := range counts {
+		busiest = max(busiest, n)
+	}
+
+	levels := []lipgloss.Style{t.subtle}
+	for _, c := range githubLevels {
+		levels = append(levels, t.style().Foreground(c))
+	}
+
+	var b strings.Builder
+	days := []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+	for weekday := 0; weekday < 7; weekday++ {
+		b.WriteString("  " + t.muted.Render(days[weekday]) + " ")
+		for week := 0; week < githubGraphWeeks; week++ {
+			day := week*7 + weekday
+			if start.AddDate(0, 0, day).After(today) {
+				break
+			}
+			level := 0
+			if counts[day] > 0 {
+				// Scale the rest of the levels to the busiest day
+				level = 1 + (counts[day]-1)*(len(levels)-2)/max(1, busiest-1)
+			}
+			b.WriteString(levels[level].Render("■") + " ")
+		}
+		b.WriteString("\n")
+	}
+
+	legend := t.muted.Render("  Less ")
+	for _, style := range levels {
+		legend += style.Render("■") + " "
+	}
+	b.WriteString(legend + t.muted.Render("More"))
+	return b.String()
+}
+
+// githubResult returns the rendered profile for user, or a readable error in
+// the language of tr.
+func githubResult(user string, t *theme, tr func(string, ...any) string) string {
+	profile, err := fetchGitHub(user)
+	var statusErr *statusError
+	switch {
+	case errors.As(err, &statusErr) && statusErr.code == http.StatusNotFound:
+		return tr("github no user", user)
+	case errors.As(err, &statusErr) && statusErr.code == http.StatusForbidden:
+		return tr("github rate limit")
+	case err != nil:
+		return tr("github error", describeFetchError(err))
+	}
+	return profile.render(t)
+}
+
+func githubCommand(m *model, in commandInput) (string, tea.Cmd) {
+	user := in.args
+	if user == "" {
+		user = githubDefaultUser
+	}
+	if !githubUserPattern.MatchString(user) {
+		return m.tr("github usage"), nil
+	}
+	if !in.toTerminal {
+		return githubResult(user, m.theme, m.tr), nil
+	}
+	t, tr := m.theme, m.tr
+	return "", m.startApp(newLoader(t, tr("github loading", user), func() string {
+		return githubResult(user, t, tr)
+	}))
+}
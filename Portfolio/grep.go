@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// grepFile is a file grep searches, by its path relative to the portfolio root.
+type grepFile struct {
+	path    string
+	content string
+}
+
+// grep searches every visible file under the current directory (or the given
+// path), including files created this session, for pattern and returns the
+// matches formatted as file:line: text. When input is piped in and no path is
+// given, the piped lines are filtered instead. Matches are only highlighted,
+// and counted, when the output goes to the terminal. Piped or redirected, the
+// output is only the matching lines, and nothing if there are none.
+func (m model) grep(in commandInput) string {
+	f, rest, err := parseFlags(in.argv, []string{"i"}, nil)
+	if err != nil {
+		return "grep: " + err.Error() + "\n" + m.tr("grep usage")
+	}
+	if len(rest) == 0 || len(rest) > 2 {
+		return m.tr("grep usage")
+	}
+
+	pattern := rest[0]
+	if f.has("i") {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return m.tr("grep pattern", err)
+	}
+	highlight := func(line string) string {
+		if !in.toTerminal {
+			return line
+		}
+		return m.theme.highlightMatches(re, line)
+	}
+
+	if in.piped && len(rest) == 1 {
+		var lines []string
+		for _, line := range strings.Split(in.stdin, "\n") {
+			if re.MatchString(line) {
+				lines = append(lines, highlight(line))
+			}
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	dir := "."
+	if len(rest) == 2 {
+		dir = rest[1]
+	}
+	// Same rules as cat and cd
+	dir, err = m.resolve(dir)
+	if err != nil {
+		return err.Error()
+	}
+	files, err := m.grepFiles(dir)
+	if err != nil {
+		return m.tr("grep error", visitorError(err))
+	}
+
+	var b strings.Builder
+	matches := 0
+	for _, file := range files {
+		rel, err := filepath.Rel(filepath.FromSlash(m.directory), filepath.FromSlash(file.path))
+		if err != nil {
+			rel = file.path
+		}
+		rel = filepath.ToSlash(rel)
+		for i, line := range strings.Split(file.content, "\n") {
+			if !re.MatchString(line) {
+				continue
+			}
+			matches++
+			if in.toTerminal {
+				fmt.Fprintf(&b, "%s:%s: %s\n", m.theme.file.Render(rel), m.theme.success.Render(fmt.Sprint(i+1)), highlight(line))
+			} else {
+				fmt.Fprintf(&b, "%s:%d: %s\n", rel, i+1, line)
+			}
+		}
+	}
+	switch {
+	case !in.toTerminal:
+		return strings.TrimSuffix(b.String(), "\n")
+	case matches == 0:
+		return m.tr("grep none", rest[0])
+	case matches == 1:
+		b.WriteString("\n" + m.tr("grep match"))
+	default:
+		b.WriteString("\n" + m.tr("grep matches", matches))
+	}
+	return b.String()
+}
+
+// grepFiles returns the visible text files under dir, a path from resolve,
+// on disk and from this session, sorted by path.
+func (m model) grepFiles(dir string) ([]grepFile, error) {
+	var files []grepFile
+	root := hostPath(dir)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != root && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		// Symlinked directories aren't walked, but files are read through their links
+		if d.IsDir() || !insideRoot(path) {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		// Skip unreadable and binary files rather than failing the whole search
+		if err != nil || bytes.IndexByte(content, 0) >= 0 {
+			return nil
+		}
+		rel, err := filepath.Rel(portfolioRoot, path)
+		if err != nil {
+			return nil
+		}
+		files = append(files, grepFile{path: filepath.ToSlash(rel), content: string(content)})
+		return nil
+	})
+	// A directory that only holds session files doesn't exist on disk
+	if err != nil && !(errors.Is(err, fs.ErrNotExist) && m.hasSessionFilesIn(dir)) {
+		return nil, err
+	}
+
+	for p, content := range m.sessionFiles {
+		if inDir(p, dir) {
+			files = append(files, grepFile{path: p, content: content})
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+	return files, nil
+}
+
+// hasSessionFilesIn reports whether any session file is under dir.
+func (m model) hasSessionFilesIn(dir string) bool {
+	for p := range m.sessionFiles {
+		if inDir(p, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// inDir reports whether p is dir or under it, both paths from resolve.
+func inDir(p, dir string) bool {
+	return dir == "." || p == dir || strings.HasPrefix(p, dir+"/")
+}
+
+// highlightMatches renders every match of re inside line in the accent colour.
+func (t *theme) highlightMatches(re *regexp.Regexp, line string) string {
+	return re.ReplaceAllStringFunc(line, func(match string) string {
+		return t.accent.Render(match)
+	})
+}
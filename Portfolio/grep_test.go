@@ -0,0 +1,26 @@
+package main
+
+import "testing"
+
+func TestGrepSummary(t *testing.T) {
+	tests := []struct {
+		name       string
+		argv       []string
+		toTerminal bool
+		want       string
+	}{
+		{"terminal", []string{"Godot", "About"}, true, "About/skills.txt:2: Godot\n\n1 match"},
+		{"terminal matches", []string{"Go", "About"}, true, "About/bio.txt:2: I make games in Go.\nAbout/skills.txt:1: Go\nAbout/skills.txt:2: Godot\n\n3 matches"},
+		{"terminal none", []string{"nothing-here"}, true, "No matches for: nothing-here"},
+		{"piped", []string{"Go", "About"}, false, "About/bio.txt:2: I make games in Go.\nAbout/skills.txt:1: Go\nAbout/skills.txt:2: Godot"},
+		{"piped none", []string{"nothing-here"}, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestState(t)
+			if got := m.grep(commandInput{argv: tt.argv, toTerminal: tt.toTerminal}); got != tt.want {
+				t.Errorf("grep %q = %q, want %q", tt.argv, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+// Execute runs one command line without a screen to draw on, as for -c and
+// telnet, returning what it printed and the session as the command left it.
+// Commands behave as if their output were piped: web lookups wait for their
+// answer instead of showing a spinner, and nothing is drawn. Anything a
+// command would have done through a tea.Cmd, like ringing the bell, is
+// dropped.
+//
+// The returned state carries changes like the current directory and
+// aliases on to the next call. Its maps are shared with the state passed in.
+func Execute(state model, input string) (string, model) {
+	state.headless = true
+	timer := state.timer
+	output, _ := state.execute(input)
+	if state.app != nil || state.fileViewMode || (state.timer != nil && state.timer != timer) {
+		// Games, chat and timers need someone at the keyboard
+		state.app, state.fileViewMode, state.timer = nil, false, timer
+		output = state.tr("needs terminal")
+	}
+	// Achievements still unlock, but announcing them would end up in
+	// scripts' output, so they're only listed by the achievements command
+	state.progress.pending = nil
+	return output, state
+}
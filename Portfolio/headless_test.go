@@ -0,0 +1,304 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// newTestState returns a fresh session browsing a small portfolio in a
+// temporary directory, with its own config directory.
+func newTestState(t *testing.T) model {
+	t.Helper()
+	return newTestStateWithRenderer(t, lipgloss.NewRenderer(io.Discard))
+}
+
+func newTestStateWithRenderer(t *testing.T, r *lipgloss.Renderer) model {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{
+		"About/bio.txt":       "Hi, I'm Fred.\nI make games in Go.\n",
+		"About/skills.txt":    "Go\nGodot\nPython\n",
+		"Projects/snake.md":   "# Snake\nA snake game.\n",
+		"Projects/plans.txt":  "Nothing yet\n",
+		"Extra/cake/gone.txt": "The cake is a lie.\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	oldRoot := portfolioRoot
+	portfolioRoot = root
+	t.Cleanup(func() { portfolioRoot = oldRoot })
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	return initialModel(r, "en")
+}
+
+// executeTests run their inputs one after another in the same session, and
+// check the output of the last. Each want is one or more whole lines the
+// output must have, ignoring spaces at the ends of lines, and match is a
+// pattern for output that changes from run to run. A nil want with no match
+// expects no output at all.
+var executeTests = []struct {
+	name    string
+	inputs  []string
+	want    []string
+	notWant []string
+	match   *regexp.Regexp
+}{
+	// Navigation and files
+	{"pwd", []string{"pwd"}, []string{"Current directory: ~"}, nil, nil},
+	{"cd", []string{"cd About", "pwd"}, []string{"Current directory: ~/About"}, nil, nil},
+	{"cd home", []string{"cd About", "cd", "pwd"}, []string{"Current directory: ~"}, nil, nil},
+	{"cd missing", []string{"cd nowhere"}, []string{"Invalid directory: nowhere"}, nil, nil},
+	{"cd outside", []string{"cd ../.."}, []string{"Access denied: ../.. is outside the portfolio"}, nil, nil},
+	{"ls", []string{"ls"}, []string{"About", "Projects", "Extra"}, nil, nil},
+	{"ls in directory", []string{"cd About", "ls"}, []string{"bio.txt", "skills.txt"}, []string{"Projects"}, nil},
+	{"cat", []string{"cat About/bio.txt"}, []string{"I make games in Go."}, nil, nil},
+	{"cat usage", []string{"cat"}, []string{"Usage: cat <file> (see man cat for more)"}, nil, nil},
+	{"cat missing", []string{"cat nope.txt"}, []string{"Error reading file: open nope.txt: no such file or directory"}, nil, nil},
+	{"cat outside", []string{"cat ../../etc/passwd"}, []string{"Access denied: ../../etc/passwd is outside the portfolio"}, []string{"root:"}, nil},
+	{"head", []string{"head -n 1 About/skills.txt"}, []string{"Go"}, []string{"Python"}, nil},
+	{"head usage", []string{"head"}, []string{"Usage: head [-n count] <file> (see man head for more)"}, nil, nil},
+	{"tail", []string{"tail -n 1 About/skills.txt"}, []string{"Python"}, []string{"Godot"}, nil},
+	{"tail usage", []string{"tail"}, []string{"Usage: tail [-n count] <file> (see man tail for more)"}, nil, nil},
+	{"wc", []string{"wc About/skills.txt"}, []string{"3       3      16 About/skills.txt"}, nil, nil},
+	{"wc usage", []string{"wc"}, []string{"Usage: wc <file> (see man wc for more)"}, nil, nil},
+	{"tree", []string{"tree"}, []string{"📁 ~\n├── About\n│   ├── bio.txt\n│   └── skills.txt\n├── Extra\n│   └── cake\n└── Projects\n    ├── plans.txt\n    └── snake.md\n\n4 directories, 4 files"}, nil, nil},
+	{"tree depth", []string{"tree 1"}, []string{"📁 ~\n├── About\n├── Extra\n└── Projects\n\n3 directories, 0 files"}, nil, nil},
+	{"tree bad depth", []string{"tree Projects"}, []string{"Usage: tree [depth]"}, nil, nil},
+	{"grep", []string{"grep -i godot About"}, []string{"About/skills.txt:2: Godot"}, []string{"match"}, nil},
+	{"grep no matches", []string{"grep nothing-here"}, nil, nil, nil},
+	{"pipe grep files", []string{"grep Go About | wc"}, []string{"3      10      85"}, nil, nil},
+	{"grep usage", []string{"grep"}, []string{"Usage: grep [-i] <pattern> [path] (see man grep for more)"}, nil, nil},
+	{"grep session file", []string{"echo Go rocks > notes.txt", "grep rocks"}, []string{"notes.txt:1: Go rocks"}, nil, nil},
+	{"grep session directory", []string{"echo deep Go > new/x.txt", "grep Go new"}, []string{"new/x.txt:1: deep Go"}, nil, nil},
+	{"grep missing directory", []string{"grep Go nowhere"}, []string{"Error searching: lstat nowhere: no such file or directory"}, nil, nil},
+	{"grep bad pattern", []string{"grep ("}, []string{"Invalid pattern: error parsing regexp: missing closing ): `(`"}, nil, nil},
+	{"grep bad flag", []string{"grep -x Go"}, []string{"grep: unknown flag -x"}, nil, nil},
+	{"img usage", []string{"img"}, []string{"Usage: img <file> (see man img for more)"}, nil, nil},
+	{"img not an image", []string{"img About/bio.txt"}, []string{"img: About/bio.txt is not a PNG, JPEG or GIF image"}, nil, nil},
+	{"download usage", []string{"download"}, []string{"Usage: download <file> (see man download for more)"}, nil, nil},
+	{"download disabled", []string{"download About/bio.txt"}, []string{"download: links are only available when the portfolio is served with --download-addr"}, nil, nil},
+	{"bookmark list", []string{"bookmark"}, []string{"No bookmarks yet. Save this directory with bookmark add <name>."}, nil, nil},
+	{"bookmark add", []string{"cd About", "bookmark add bio"}, []string{"Bookmarked ~/About as bio, go bio to come back."}, nil, nil},
+	{"go", []string{"cd About", "bookmark add bio", "cd", "go bio", "pwd"}, []string{"Current directory: ~/About"}, nil, nil},
+	{"go usage", []string{"go"}, []string{"Usage: go <bookmark> (see man go for more)"}, nil, nil},
+	{"go missing", []string{"go nosuch"}, []string{"go: nosuch: no such bookmark, see bookmark list"}, nil, nil},
+
+	// Information
+	{"help", []string{"help"}, []string{"Available Commands:", "Navigation:"}, nil, nil},
+	{"man", []string{"man ls"}, []string{"NAME", "    ls - List files and directories"}, nil, nil},
+	{"man usage", []string{"man"}, []string{"Usage: man <command> (see man man for more)"}, nil, nil},
+	{"man missing", []string{"man nosuch"}, []string{"No manual entry for nosuch"}, nil, nil},
+	{"whoami", []string{"whoami"}, []string{"Current user: guest"}, nil, nil},
+	{"date", []string{"date"}, nil, nil, regexp.MustCompile(`^Server time: \w{3} \w{3} [ \d]\d \d\d:\d\d:\d\d \w+ \d{4}$`)},
+	{"version", []string{"version"}, nil, nil, regexp.MustCompile(`^version 1\.0\.0, built with Go \S+ on \S+/\S+$`)},
+	{"uptime", []string{"uptime"}, nil, nil, regexp.MustCompile(`^Server up +\d+ seconds?, since .+\nYour session +\d+ seconds?, 0 commands run$`)},
+	{"skills", []string{"skills"}, []string{"Skills:", "• Go Programming"}, nil, nil},
+	{"contact", []string{"contact"}, []string{"- GitHub:   github.com/ItsHotdogFred"}, nil, nil},
+	{"resume", []string{"resume"}, []string{"Experience"}, nil, nil},
+	{"neofetch", []string{"neofetch"}, nil, nil, regexp.MustCompile(`(?m) guest@fred-cli\n.* -+\n.* OS: Fred's Portfolio CLI$`)},
+	{"motd", []string{"motd"}, []string{"No announcements right now."}, nil, nil},
+	{"history", []string{"history"}, []string{"No commands in history yet."}, nil, nil},
+	{"achievements", []string{"achievements"}, []string{"Achievements: 1 of 8 unlocked"}, nil, regexp.MustCompile(`(?m)^ 👣 First steps  Run your first command · \d\d? \w{3} \d{4}$`)},
+	{"stats", []string{"stats"}, []string{"Visitor analytics are only recorded when running as a server."}, nil, nil},
+
+	// Text and fun
+	{"echo", []string{"echo hi there"}, []string{"hi there"}, nil, nil},
+	{"echo quoted", []string{`echo "a  b" c\ d`}, []string{"a  b c d"}, nil, nil},
+	{"yoda", []string{"yoda --seed 1 I am your father"}, []string{"Yoda says: Your father, I am. Hmm."}, nil, nil},
+	{"yoda usage", []string{"yoda"}, []string{"Usage: yoda [--seed n] <text> (see man yoda for more)"}, nil, nil},
+	{"cowsay", []string{"cowsay moo"}, []string{" _____\n< moo >\n -----\n" + cow}, nil, nil},
+	{"cowsay usage", []string{"cowsay"}, []string{"Usage: cowsay <text> (see man cowsay for more)"}, nil, nil},
+	{"fortune", []string{"fortune"}, nil, nil, oneOf(fortunes()...)},
+	{"coinflip", []string{"coinflip"}, nil, nil, oneOf("Result: Heads", "Result: Tails")},
+	{"qr", []string{"qr hi"}, []string{"QR code for: hi"}, nil, nil},
+	{"qr level", []string{"qr --level Q hi"}, []string{"QR code for: hi"}, nil, nil},
+	{"qr bad level", []string{"qr --level Z hi"}, []string{"qr: the level must be L, M, Q or H"}, nil, nil},
+	{"qr usage", []string{"qr"}, []string{"Usage: qr [--level L|M|Q|H] [--compact] <text> (see man qr for more)"}, nil, nil},
+	{"copy", []string{"copy hello"}, []string{"hello"}, nil, nil},
+	{"copy usage", []string{"copy"}, []string{"Usage: copy <text|file> (see man copy for more)"}, nil, nil},
+	{"joke bad category", []string{"joke nonsense"}, []string{"Usage: joke [category]\nCategories: programming, misc, pun, spooky, christmas"}, nil, nil},
+	{"wiki usage", []string{"wiki"}, []string{"Usage: wiki <term> (see man wiki for more)"}, nil, nil},
+	{"weather usage", []string{"weather"}, []string{"Usage: weather <city> (see man weather for more)"}, nil, nil},
+	{"github bad user", []string{"github not_valid!!"}, []string{"Usage: github [user]"}, nil, nil},
+	{"price usage", []string{"price"}, []string{"Usage: price <symbol> (see man price for more)"}, nil, nil},
+	{"price bad symbol", []string{"price !!!"}, []string{"Usage: price <symbol>"}, nil, nil},
+	{"easter egg", []string{"sudo"}, []string{"visitor is not in the sudoers file. This incident will be reported. 🚨"}, nil, nil},
+
+	// Session settings
+	{"alias list", []string{"alias"}, []string{`alias ll="ls"`}, nil, nil},
+	{"alias define", []string{`alias hey="echo hello"`, "hey"}, []string{"hello"}, nil, nil},
+	{"alias pipeline", []string{`alias skills-go="cat About/skills.txt | grep Go"`, "skills-go"}, []string{"Go", "Godot"}, []string{"Python"}, nil},
+	{"alias builtin", []string{"alias ls=pwd"}, []string{"alias: ls is a built-in command"}, nil, nil},
+	{"alias hidden builtin", []string{"alias stats=pwd"}, []string{"alias: stats is a built-in command"}, nil, nil},
+	{"unalias", []string{`alias hey="echo hello"`, "unalias hey", "hey"}, []string{"hey is not a valid command. Did you mean head?"}, nil, nil},
+	{"unalias usage", []string{"unalias"}, []string{"Usage: unalias <name> (see man unalias for more)"}, nil, nil},
+	{"unalias missing", []string{"unalias nosuch"}, []string{"unalias: nosuch: not found"}, nil, nil},
+	{"theme list", []string{"theme"}, []string{"Themes:\n* dark       ████████████\n  light      ████████████\n  solarized  ████████████\n  dracula    ████████████"}, nil, nil},
+	{"theme set", []string{"theme light", "theme"}, []string{"* light      ████████████"}, nil, nil},
+	{"theme unknown", []string{"theme nope"}, []string{`theme: unknown theme "nope", try one of: dark, light, solarized, dracula`}, nil, nil},
+	{"lang list", []string{"lang"}, []string{"* en   English", "  es   Español"}, nil, nil},
+	{"lang unknown", []string{"lang xx"}, []string{`lang: unknown language "xx", try one of: en, es`}, nil, nil},
+	{"lang download", []string{"lang es", "download About/bio.txt"}, []string{"download: los enlaces solo están disponibles si el portafolio se sirve con --download-addr"}, nil, nil},
+	{"lang alias", []string{"lang es", "alias bad"}, []string{"alias: bad: no encontrado"}, nil, nil},
+	{"lang alias error", []string{"lang es", `alias x="ls > out"`}, []string{"alias: los alias no pueden redirigir la salida"}, nil, nil},
+	{"lang usage", []string{"lang es", "tree x"}, []string{"Uso: tree [profundidad]"}, nil, nil},
+	{"lang error", []string{"lang es", "head -n x About/bio.txt"}, []string{"head: Número de líneas no válido: x"}, nil, nil},
+	{"lang grep", []string{"lang es", "grep ("}, []string{"Patrón no válido: error parsing regexp: missing closing ): `(`"}, nil, nil},
+	{"lang full-screen", []string{"lang es", "play"}, []string{"Este comando necesita una terminal a pantalla completa, pruébalo por SSH."}, nil, nil},
+	{"timer usage", []string{"timer"}, []string{"Usage: timer <minutes> | timer stop"}, nil, nil},
+	{"timer bad duration", []string{"timer 0"}, []string{"timer: give a number of minutes (up to a day), or a duration like 90s"}, nil, nil},
+	{"timer stop", []string{"timer stop"}, []string{"No timer is running."}, nil, nil},
+	{"demo", []string{"demo"}, []string{"Starting the demo, press any key to take over."}, nil, nil},
+	{"clear", []string{"clear"}, nil, nil, nil},
+	{"exit", []string{"exit"}, nil, nil, nil},
+
+	// Full-screen commands
+	{"play", []string{"play"}, []string{"This command needs a full-screen terminal, try it over SSH."}, nil, nil},
+	{"typetest", []string{"typetest"}, []string{"This command needs a full-screen terminal, try it over SSH."}, nil, nil},
+	{"quiz", []string{"quiz"}, []string{"This command needs a full-screen terminal, try it over SSH."}, nil, nil},
+	{"matrix", []string{"matrix"}, []string{"This command needs a full-screen terminal, try it over SSH."}, nil, nil},
+	{"fireworks", []string{"fireworks"}, []string{"This command needs a full-screen terminal, try it over SSH."}, nil, nil},
+	{"top", []string{"top"}, []string{"This command needs a full-screen terminal, try it over SSH."}, nil, nil},
+	{"chat", []string{"chat"}, []string{"This command needs a full-screen terminal, try it over SSH."}, nil, nil},
+	{"compose", []string{"compose"}, []string{"This command needs a full-screen terminal, try it over SSH."}, nil, nil},
+	{"pomodoro", []string{"pomodoro"}, []string{"This command needs a full-screen terminal, try it over SSH."}, nil, nil},
+
+	// Pipes
+	{"pipe grep", []string{"cat About/bio.txt | grep Go"}, []string{"I make games in Go."}, []string{"Hi,"}, nil},
+	{"pipe wc", []string{"cat About/skills.txt | wc"}, []string{"3       3      16"}, nil, nil},
+	{"pipe wc no trailing newline", []string{"ls | wc"}, []string{"3       3      20"}, nil, nil},
+	{"pipe ls", []string{"ls | grep About"}, []string{"About"}, []string{"Projects"}, nil},
+	{"pipe chain", []string{"cat About/skills.txt | grep Go | head -n 1"}, []string{"Go"}, []string{"Godot"}, nil},
+	{"pipe empty stage", []string{"echo a |"}, []string{"Syntax error: empty command in pipeline"}, nil, nil},
+	{"pipe unknown command", []string{"echo a | nosuch"}, []string{"nosuch is not a valid command, try running help for commands"}, nil, nil},
+
+	// Redirects
+	{"redirect", []string{"echo hello > note.txt", "cat note.txt"}, []string{"hello"}, nil, nil},
+	{"redirect quiet", []string{"echo hello > note.txt"}, nil, nil, nil},
+	{"redirect append", []string{"echo one > note.txt", "echo two >> note.txt", "cat note.txt"}, []string{"one\ntwo"}, nil, nil},
+	{"redirect pipeline", []string{"cat About/skills.txt | grep Go > go.txt", "wc go.txt"}, []string{"2       2       9 go.txt"}, nil, nil},
+	{"redirect outside", []string{"echo hi > ../x"}, []string{"Access denied: ../x is outside the portfolio"}, nil, nil},
+	{"redirect read-only", []string{"echo hi >> About/bio.txt"}, []string{"Permission denied: About/bio.txt is read-only"}, nil, nil},
+	{"redirect two files", []string{"echo hi > a b"}, []string{"Syntax error: > takes a single file name, quote names with spaces"}, nil, nil},
+
+	// Errors
+	{"unknown command", []string{"nosuch"}, []string{"nosuch is not a valid command, try running help for commands"}, nil, nil},
+	{"did you mean", []string{"neofecth"}, []string{"neofecth is not a valid command. Did you mean neofetch?"}, nil, nil},
+	{"unterminated quote", []string{`echo "unterminated`}, []string{"Syntax error: unterminated quote"}, nil, nil},
+	{"empty line", []string{""}, nil, nil, nil},
+}
+
+// oneOf returns a pattern matching exactly one of outputs.
+func oneOf(outputs ...string) *regexp.Regexp {
+	quoted := make([]string, len(outputs))
+	for i, output := range outputs {
+		quoted[i] = regexp.QuoteMeta(output)
+	}
+	return regexp.MustCompile(`^(?:` + strings.Join(quoted, "|") + `)$`)
+}
+
+func TestExecute(t *testing.T) {
+	for _, tt := range executeTests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := newTestState(t)
+			var output string
+			for _, input := range tt.inputs {
+				output, state = Execute(state, input)
+			}
+			if tt.want == nil && tt.match == nil && output != "" {
+				t.Errorf("Execute(%q) = %q, want no output", tt.inputs[len(tt.inputs)-1], output)
+			}
+			// Tables and art pad their lines out
+			lines := strings.Split(output, "\n")
+			for i, line := range lines {
+				lines[i] = strings.TrimRight(line, " ")
+			}
+			trimmed := strings.Join(lines, "\n")
+			for _, want := range tt.want {
+				if !strings.Contains("\n"+trimmed+"\n", "\n"+want+"\n") {
+					t.Errorf("Execute(%q) = %q, want it to have the lines %q", tt.inputs[len(tt.inputs)-1], output, want)
+				}
+			}
+			if tt.match != nil && !tt.match.MatchString(trimmed) {
+				t.Errorf("Execute(%q) = %q, want it to match %s", tt.inputs[len(tt.inputs)-1], output, tt.match)
+			}
+			// Visitors shouldn't learn where the portfolio is on disk
+			if strings.Contains(output, portfolioRoot) {
+				t.Errorf("Execute(%q) = %q, which gives away the portfolio's path", tt.inputs[len(tt.inputs)-1], output)
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(output, notWant) {
+					t.Errorf("Execute(%q) = %q, want it not to contain %q", tt.inputs[len(tt.inputs)-1], output, notWant)
+				}
+			}
+		})
+	}
+}
+
+// TestExecuteCoversEveryCommand makes sure new commands get a case above.
+func TestExecuteCoversEveryCommand(t *testing.T) {
+	tested := map[string]bool{}
+	for _, tt := range executeTests {
+		for _, input := range tt.inputs {
+			for _, stage := range strings.Split(input, "|") {
+				if words := strings.Fields(stage); len(words) > 0 {
+					tested[words[0]] = true
+				}
+			}
+		}
+	}
+	for name := range commands {
+		if !tested[name] {
+			t.Errorf("no Execute test runs %s", name)
+		}
+	}
+}
+
+func TestExecuteDoesNotAnnounceAchievements(t *testing.T) {
+	output, state := Execute(newTestState(t), "pwd")
+	if want := "Current directory: ~"; output != want {
+		t.Errorf("Execute(%q) = %q, want %q without an achievement", "pwd", output, want)
+	}
+	if _, ok := state.progress.Unlocked["first-command"]; !ok {
+		t.Errorf("Execute(%q) didn't unlock the first command's achievement", "pwd")
+	}
+}
+
+// TestExecuteGrepIsPlain checks grep doesn't style output that isn't going
+// to a terminal, even when the terminal has colours.
+func TestExecuteGrepIsPlain(t *testing.T) {
+	r := lipgloss.NewRenderer(io.Discard)
+	r.SetColorProfile(termenv.TrueColor)
+	state := newTestStateWithRenderer(t, r)
+	for _, input := range []string{"grep Go About", "cat About/skills.txt | grep Go"} {
+		if output, _ := Execute(state, input); strings.Contains(output, "\x1b[") {
+			t.Errorf("Execute(%q) = %q, want no escape codes", input, output)
+		}
+	}
+}
+
+func TestExecuteKeepsState(t *testing.T) {
+	state := newTestState(t)
+	_, after := Execute(state, "cd Projects")
+	if after.directory != "Projects" {
+		t.Errorf("directory after cd = %q, want %q", after.directory, "Projects")
+	}
+	if state.directory != "." {
+		t.Errorf("Execute changed the directory of the state passed in to %q", state.directory)
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/ssh"
+)
+
+// historyMaxSize is the number of commands kept in a history file.
+// It can be overridden with the PORTFOLIO_HISTORY_SIZE environment variable.
+var historyMaxSize = 500
+
+func init() {
+	if v := os.Getenv("PORTFOLIO_HISTORY_SIZE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			historyMaxSize = n
+		}
+	}
+}
+
+// dataDir is where the portfolio keeps its own state. It lives outside the
+// portfolio tree so visitors can't read it with cat.
+func dataDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "fred-cli")
+}
+
+// historyDir returns the directory history files are stored in.
+func historyDir() string {
+	return filepath.Join(dataDir(), "history")
+}
+
+// localHistoryPath is the history file used when running without the server.
+func localHistoryPath() string {
+	return filepath.Join(historyDir(), "local")
+}
+
+// sessionHistoryPath keys the history file on the visitor's public key.
+// Keyboard-interactive logins can share an IP, so they only keep their
+// history for the session.
+func sessionHistoryPath(s ssh.Session) string {
+	if key := keyID(s); key != "" {
+		return filepath.Join(historyDir(), key)
+	}
+	return ""
+}
+
+// keyID names the visitor's public key in file names, or returns "" if they
+// didn't log in with one.
+func keyID(s ssh.Session) string {
+	key := s.PublicKey()
+	if key == nil {
+		return ""
+	}
+	sum := sha256.Sum256(key.Marshal())
+	return "key-" + hex.EncodeToString(sum[:])
+}
+
+// remoteIP returns the visitor's IP address without the port.
+func remoteIP(s ssh.Session) string {
+	host, _, err := net.SplitHostPort(s.RemoteAddr().String())
+	if err != nil {
+		return s.RemoteAddr().String()
+	}
+	return host
+}
+
+// loadHistory reads a history file, returning nil if it doesn't exist yet.
+func loadHistory(path string) []string {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var history []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			history = append(history, line)
+		}
+	}
+	return trimHistory(history)
+}
+
+// saveHistory writes the most recent historyMaxSize entries to path.
+func saveHistory(path string, history []string) {
+	if path == "" {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		log.Error("Could not create history directory", "error", err)
+		return
+	}
+	data := strings.Join(trimHistory(history), "\n") + "\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		log.Error("Could not save history", "error", err)
+	}
+}
+
+func trimHistory(history []string) []string {
+	if len(history) > historyMaxSize {
+		return history[len(history)-historyMaxSize:]
+	}
+	return history
+}
+
+// expandHistory replaces a "!N" reference with the Nth history entry.
+// The returned bool is false if the reference doesn't exist.
+func (m model) expandHistory(input string) (string, bool) {
+	ref := strings.TrimPrefix(input, "!")
+	if ref == "!" {
+		if len(m.history) == 0 {
+			return input, false
+		}
+		return m.history[len(m.history)-1], true
+	}
+	n, err := strconv.Atoi(ref)
+	if err != nil || n < 1 || n > len(m.history) {
+		return input, false
+	}
+	return m.history[n-1], true
+}
+
+// historyView numbers every entry so it can be re-run with !N.
+func (m model) historyView() string {
+	if len(m.history) == 0 {
+		return m.tr("history empty")
+	}
+	var b strings.Builder
+	for i, entry := range m.history {
+		fmt.Fprintf(&b, "%5d  %s\n", i+1, entry)
+	}
+	b.WriteString("\n" + m.tr("history hint"))
+	return b.String()
+}
@@ -0,0 +1,499 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// defaultLanguage is used when the visitor's locale isn't one we have.
+const defaultLanguage = "en"
+
+// languages lists the supported languages in the order lang shows them.
+var languages = []string{"en", "es"}
+
+// languageNames are shown by lang, each in its own language.
+var languageNames = map[string]string{
+	"en": "English",
+	"es": "Español",
+}
+
+// messages holds the text shown to visitors, by language and then message
+// id. Messages with verbs are formatted with fmt.Sprintf. A message missing
+// from a language falls back to English. Command summaries for help come
+// from manPages, with "summary <command>" overriding them in other languages.
+var messages = map[string]map[string]string{
+	"en": {
+		"welcome":             "Welcome to Fred's Portfolio CLI!\n\nNavigation:\n• Use scroll wheel or arrow keys to browse command history\n• Use Page Up/Page Down to navigate viewport\n• Type 'help' to see all available commands\n\nGet started with 'ls' to explore or 'help' for guidance.",
+		"telnet welcome":      "Welcome to Fred's Portfolio CLI!\n\nThis is the plain text version. Type 'help' to see the commands, 'ls' to explore, or 'exit' to leave. Games and other full-screen commands need SSH.",
+		"help title":          "Available Commands:",
+		"category navigation": "Navigation:",
+		"category system":     "System Info:",
+		"category portfolio":  "Portfolio:",
+		"category utilities":  "Utilities:",
+		"category plugins":    "Plugins:",
+		"help tips": `Navigation Tips:
+  - Use up/down arrows to browse command history
+  - Press Tab to complete, Tab again to cycle through matches
+  - Use Page Up/Page Down to navigate viewport
+  - Press Ctrl+T to pin the output while you read, and again to follow new output
+  - Press 'q' or 'esc' to exit file viewer
+  - Use 'cd ..' to go to parent directory
+  - Press '/' on an empty prompt to search the output, like less
+  - Chain commands with '|' and save output with '>' or '>>'
+  - Run 'man <command>' for the details of a command
+
+Examples:
+  cd Portfolio   - Navigate to Portfolio directory
+  cat README.md  - View README file
+  wiki golang    - Search Wikipedia for 'golang'
+  grep -i godot  - Find every mention of 'godot'
+  ls | grep md   - List only markdown files
+  wiki golang > golang.txt - Save a summary to a file
+  echo Hello!    - Display 'Hello!'`,
+		"unknown command":    "%s is not a valid command, try running help for commands",
+		"did you mean":       "%s is not a valid command. Did you mean %s?",
+		"scroll pinned":      "📌 pinned, ctrl+t to follow",
+		"usage":              "Usage: %s (see man %s for more)",
+		"missing redirect":   "Syntax error: missing file name after >",
+		"empty stage":        "Syntax error: empty command in pipeline",
+		"redirect target":    "Syntax error: > takes a single file name, quote names with spaces",
+		"unterminated quote": "Syntax error: unterminated quote",
+		"hidden file":        "Access denied: Hidden files are not accessible",
+		"outside root":       "Access denied: %s is outside the portfolio",
+		"invalid dir":        "Invalid directory: %s",
+		"read dir error":     "Error reading directory: %v",
+		"read file error":    "Error reading file: %v",
+		"read only":          "Permission denied: %s is read-only",
+		"pwd":                "Current directory: %s",
+		"whoami":             "Current user: guest",
+		"date":               "Server time: %s",
+		"date local":         "Your time:   %s (%s)",
+		"date zone":          "date: unknown time zone %s, try a name like Europe/London",
+		"echo":               "Echoing: %s",
+		"wiki usage":         "Please provide a search term.",
+		"wiki error":         "Error fetching Wikipedia summary: %v",
+		"qr usage":           "Usage: qr [--level L|M|Q|H] [--compact] <text>",
+		"qr title":           "QR code for: %s",
+		"heads":              "Result: Heads",
+		"tails":              "Result: Tails",
+		"contact":            "You can find me on:\n- GitHub:   github.com/ItsHotdogFred\n- Itch.io:  itshotdogfred.itch.io\n- Email:    cli@itsfred.dev",
+		"idle warning":       " ⏳ Disconnecting in %ds due to inactivity, press any key to stay ",
+		"languages":          "Languages:",
+		"lang hint":          "Use 'lang <code>' to switch.",
+		"lang switched":      "Switched to English.",
+		"lang unknown":       "lang: unknown language %q, try one of: %s",
+		"search none":        "Pattern not found: %s (esc to stop)",
+		"search matches":     "Match %d of %d (n/N to move, esc to stop)",
+		"needs terminal":     "This command needs a full-screen terminal, try it over SSH.",
+		"alias none":         "No aliases defined.",
+		"alias not found":    "alias: %s: not found",
+		"alias builtin":      "alias: %s is a built-in command",
+		"alias syntax":       "expected name=value",
+		"alias name":         "invalid alias name %q",
+		"alias no command":   "alias %s has no command",
+		"alias redirect":     "aliases can't redirect output",
+		"unalias usage":      "Usage: unalias <name>",
+		"unalias not found":  "unalias: %s: not found",
+		"download usage":     "Usage: download <file>",
+		"download disabled":  "download: links are only available when the portfolio is served with --download-addr",
+		"download archive":   "download: can't archive %s: %v",
+		"download too big":   "download: %s is too big to download",
+		"download limit":     "download: you have too many downloads waiting, fetch them or try again in %d minutes",
+		"download busy":      "download: the server has too many downloads waiting, please try again in a few minutes",
+		"head usage":         "Usage: head [-n count] <file>",
+		"tail usage":         "Usage: tail [-n count] <file>",
+		"wc usage":           "Usage: wc <file>",
+		"line count":         "Invalid line count: %s",
+		"tree usage":         "Usage: tree [depth]",
+		"grep usage":         "Usage: grep [-i] <pattern> [path]",
+		"grep pattern":       "Invalid pattern: %v",
+		"grep error":         "Error searching: %v",
+		"grep none":          "No matches for: %s",
+		"grep match":         "1 match",
+		"grep matches":       "%d matches",
+		"history empty":      "No commands in history yet.",
+		"history hint":       "Run an entry again with !N, or !! for the last command.",
+		"weather usage":      "Usage: weather <city>",
+		"weather error":      "Error fetching weather: %s",
+		"weather loading":    "Fetching the weather for %s",
+		"github usage":       "Usage: github [user]",
+		"github no user":     "github: no user called %s",
+		"github rate limit":  "github: the API rate limit was hit, try again in a few minutes",
+		"github error":       "Error fetching GitHub profile: %s",
+		"github loading":     "Fetching GitHub profile for %s",
+		"resume usage":       "Usage: resume [download]",
+		"resume error":       "Error reading resume: %v",
+		"resume download":    "Download my resume as a PDF:\n%s\n\nOr scan this with your phone:\n\n%s",
+		"img usage":          "Usage: img <file>",
+		"img not image":      "img: %s is not a PNG, JPEG or GIF image",
+		"joke usage":         "Usage: joke [category]\nCategories: %s",
+		"joke loading":       "Thinking of a joke",
+		"joke source":        "JokeAPI",
+		"joke offline":       "the offline joke list, JokeAPI couldn't be reached",
+		"joke from":          "(%s joke from %s)",
+		"play usage":         "Usage: play [snake]",
+		"stats disabled":     "Visitor analytics are only recorded when running as a server.",
+		"stats error":        "Error reading stats: %v",
+		"stats denied":       "Permission denied: stats is only available to the operator.",
+		"stats password":     "Operator password: ",
+		"stats bad password": "Permission denied: wrong password",
+		"stats cancelled":    "stats: cancelled",
+		"stats esc":          "(esc to cancel)",
+		"download link":      "Download %s from this link, which works once in the next %d minutes:\n%s\n\nOr scan this with your phone:\n\n%s",
+		"contact usage":      "Usage: contact [send [message]]",
+		"contact unset":      "Sending messages isn't set up on this server. Email me at %s instead.",
+		"contact title":      "✉️  Send me a message",
+		"contact keys":       "  (tab to switch fields, ctrl+d to send, esc to cancel)",
+		"contact email":      "Your email: ",
+		"contact message":    "Say hello, ask a question or offer me a job...",
+		"contact bad email":  "That doesn't look like an email address.",
+		"contact empty":      "Write a message first.",
+		"contact wait":       "You've sent a few messages already, please wait %s before sending another.",
+		"contact sending":    "Sending...",
+		"contact failed":     "Could not send your message, please try again later or email me at %s.",
+		"contact sent":       "✉️  Message sent, thanks! I'll get back to you at %s.",
+		"contact cancelled":  "contact: cancelled, nothing was sent",
+		"copy usage":         "Usage: copy <text|file>",
+		"copy empty":         "copy: nothing to copy",
+		"copy too big":       "copy: that's too big for the clipboard, try download instead",
+		"copy no terminal":   "copy: there's no terminal to copy to, so here it is:\n%s",
+		"copied":             "Copied %s to your clipboard.",
+		"copy preview":       "%q… (%d characters)",
+		"copy hint":          "Nothing there? Your terminal may not support OSC 52 (in tmux, set-clipboard has to be on).",
+		"copy by hand":       "Nothing there? Your terminal may not support OSC 52 (in tmux, set-clipboard has to be on), so here it is to copy by hand:",
+		"cowsay usage":       "Usage: cowsay <text>",
+		"price usage":        "Usage: price <symbol>",
+		"price no symbol":    "price: no symbol called %s",
+		"price error":        "Error fetching price: %s",
+		"price loading":      "Fetching the price of %s",
+		"timer usage":        "Usage: timer <minutes> | timer stop",
+		"timer duration":     "timer: give a number of minutes (up to a day), or a duration like 90s",
+		"timer label":        "%s timer",
+		"timer done":         "⏰ Time's up! Your %s has finished.",
+		"timer started":      "%s started, %s to go.",
+		"timer left":         "%s: %s left",
+		"timer none":         "No timer is running.",
+		"timer stopped":      "%s stopped.",
+		"timer return":       "Press any key to return to the shell",
+		"timer keys":         "b to keep it running in the background • x to stop it",
+		"pomodoro usage":     "Usage: pomodoro [stop]",
+		"pomodoro focus":     "Focus",
+		"pomodoro break":     "Break",
+		"focus done":         "🍅 Focus session done, time for a 5 minute break.",
+		"break done":         "☕ Break's over, back to work!",
+		"yoda usage":         "Usage: yoda [--seed n] <text>",
+		"yoda seed":          "yoda: the seed must be a whole number",
+		"yoda says":          "Yoda says: %s",
+		"bookmark usage":     "Usage: bookmark [add <name> | list | rm <name>]",
+		"bookmark none":      "No bookmarks yet. Save this directory with bookmark add <name>.",
+		"bookmark name":      "bookmark: names can't contain slashes or spaces",
+		"bookmarked":         "Bookmarked %s as %s, go %s to come back.",
+		"bookmark not found": "bookmark: %s: not found",
+		"go usage":           "Usage: go <bookmark>",
+		"go not found":       "go: %s: no such bookmark, see bookmark list",
+		"date usage":         "Usage: date [zone] [+format]",
+	},
+	"es": {
+		"welcome":             "¡Bienvenido al portfolio CLI de Fred!\n\nNavegación:\n• Usa la rueda del ratón o las flechas para ver el historial\n• Usa Re Pág/Av Pág para moverte por la pantalla\n• Escribe 'help' para ver todos los comandos\n\nEmpieza con 'ls' para explorar o con 'help' para orientarte.",
+		"telnet welcome":      "¡Bienvenido al portfolio CLI de Fred!\n\nEsta es la versión en texto plano. Escribe 'help' para ver los comandos, 'ls' para explorar o 'exit' para salir. Los juegos y otros comandos a pantalla completa necesitan SSH.",
+		"help title":          "Comandos disponibles:",
+		"category navigation": "Navegación:",
+		"category system":     "Sistema:",
+		"category portfolio":  "Portfolio:",
+		"category utilities":  "Utilidades:",
+		"category plugins":    "Complementos:",
+		"help tips": `Consejos:
+  - Usa las flechas arriba/abajo para recorrer el historial
+  - Pulsa Tab para completar, y otra vez para ver más opciones
+  - Usa Re Pág/Av Pág para moverte por la pantalla
+  - Pulsa Ctrl+T para fijar la salida mientras lees, y otra vez para seguir la nueva
+  - Pulsa 'q' o 'esc' para salir del visor
+  - Usa 'cd ..' para ir al directorio superior
+  - Pulsa '/' con la línea vacía para buscar en la salida, como en less
+  - Encadena comandos con '|' y guarda la salida con '>' o '>>'
+  - Usa 'man <comando>' para ver los detalles de un comando (en inglés)
+
+Ejemplos:
+  cd Portfolio   - Entra en el directorio Portfolio
+  cat README.md  - Lee el README
+  wiki golang    - Busca 'golang' en Wikipedia
+  grep -i godot  - Encuentra todas las menciones de 'godot'
+  ls | grep md   - Lista solo los archivos markdown
+  wiki golang > golang.txt - Guarda un resumen en un archivo
+  echo ¡Hola!    - Muestra '¡Hola!'`,
+		"summary pwd":          "Muestra el directorio actual",
+		"summary ls":           "Lista archivos y directorios",
+		"summary tree":         "Muestra el árbol de directorios (profundidad 2 por defecto)",
+		"summary cd":           "Cambia de directorio (usa '..' para subir)",
+		"summary bookmark":     "Guarda directorios para volver a ellos",
+		"summary go":           "Salta a un directorio guardado",
+		"summary cat":          "Muestra un archivo en el visor",
+		"summary head":         "Muestra las primeras n líneas de un archivo (10 por defecto)",
+		"summary tail":         "Muestra las últimas n líneas de un archivo (10 por defecto)",
+		"summary wc":           "Cuenta líneas, palabras y caracteres de un archivo",
+		"summary img":          "Muestra una imagen PNG, JPEG o GIF en la terminal",
+		"summary grep":         "Busca en el contenido de los archivos",
+		"summary whoami":       "Muestra el usuario actual",
+		"summary date":         "Muestra la fecha y la hora en cualquier lugar del mundo",
+		"summary version":      "Muestra la versión del CLI",
+		"summary neofetch":     "Muestra información del sistema con arte ASCII",
+		"summary skills":       "Muestra mis habilidades técnicas",
+		"summary resume":       "Lee mi currículum ('resume download' para el PDF)",
+		"summary download":     "Crea un enlace de un solo uso para descargar un archivo o directorio",
+		"summary contact":      "Muestra mis datos de contacto o me envía un mensaje",
+		"summary github":       "Muestra repositorios, estrellas y actividad en GitHub",
+		"summary qr":           "Genera un código QR",
+		"summary coinflip":     "Lanza una moneda (cara o cruz)",
+		"summary play":         "Juega a la serpiente (q para volver a la terminal)",
+		"summary timer":        "Cuenta atrás un número de minutos",
+		"summary pomodoro":     "Concéntrate 25 minutos y luego descansa 5",
+		"summary copy":         "Copia texto o un archivo a tu portapapeles",
+		"summary motd":         "Muestra el último anuncio",
+		"summary achievements": "Mira las insignias que has conseguido",
+		"summary quiz":         "Responde un pequeño cuestionario de preguntas",
+		"summary matrix":       "Mira cómo caen caracteres verdes por la pantalla",
+		"summary fireworks":    "Lanza fuegos artificiales en tu terminal",
+		"summary typetest":     "Mide tu velocidad de escritura",
+		"summary chat":         "Habla con el resto de visitantes conectados",
+		"summary compose":      "Escribe texto de varias líneas para un comando",
+		"summary echo":         "Repite el texto",
+		"summary joke":         "Cuenta un chiste (en inglés)",
+		"summary wiki":         "Busca un término en Wikipedia",
+		"summary weather":      "Muestra el tiempo actual en una ciudad",
+		"summary uptime":       "Muestra cuánto tiempo llevan activos el servidor y tu sesión",
+		"summary top":          "Muestra en vivo las estadísticas del servidor",
+		"summary price":        "Muestra el precio de una acción o criptomoneda con una gráfica de 24h",
+		"summary yoda":         "Dilo como Yoda",
+		"summary fortune":      "Muestra una cita al azar",
+		"summary cowsay":       "Haz que una vaca diga algo",
+		"summary history":      "Muestra el historial de comandos (repite con !N)",
+		"summary alias":        "Lista los alias o añade uno para esta sesión",
+		"summary unalias":      "Elimina un alias",
+		"summary demo":         "Mira un recorrido guiado por el portfolio",
+		"summary theme":        "Lista los temas de color o cambia de tema",
+		"summary lang":         "Lista los idiomas o cambia de idioma",
+		"summary clear":        "Limpia la pantalla",
+		"summary man":          "Muestra el manual de un comando",
+		"summary help":         "Muestra esta ayuda",
+		"summary exit":         "Sale del CLI",
+		"unknown command":      "%s no es un comando válido, prueba con help para ver los comandos",
+		"did you mean":         "%s no es un comando válido. ¿Quisiste decir %s?",
+		"scroll pinned":        "📌 fijado, ctrl+t para seguir",
+		"usage":                "Uso: %s (más detalles en man %s)",
+		"missing redirect":     "Error de sintaxis: falta el nombre del archivo después de >",
+		"empty stage":          "Error de sintaxis: comando vacío en la tubería",
+		"redirect target":      "Error de sintaxis: > admite un solo nombre de archivo, usa comillas si tiene espacios",
+		"unterminated quote":   "Error de sintaxis: falta cerrar una comilla",
+		"hidden file":          "Acceso denegado: los archivos ocultos no son accesibles",
+		"outside root":         "Acceso denegado: %s está fuera del portafolio",
+		"invalid dir":          "Directorio no válido: %s",
+		"read dir error":       "Error al leer el directorio: %v",
+		"read file error":      "Error al leer el archivo: %v",
+		"read only":            "Permiso denegado: %s es de solo lectura",
+		"pwd":                  "Directorio actual: %s",
+		"whoami":               "Usuario actual: invitado",
+		"date":                 "Hora del servidor: %s",
+		"date local":           "Tu hora:           %s (%s)",
+		"date zone":            "date: zona horaria desconocida %s, prueba con un nombre como Europe/Madrid",
+		"echo":                 "Repitiendo: %s",
+		"wiki usage":           "Indica un término de búsqueda.",
+		"wiki error":           "Error al obtener el resumen de Wikipedia: %v",
+		"qr usage":             "Uso: qr [--level L|M|Q|H] [--compact] <texto>",
+		"qr title":             "Código QR de: %s",
+		"heads":                "Resultado: Cara",
+		"tails":                "Resultado: Cruz",
+		"contact":              "Puedes encontrarme en:\n- GitHub:   github.com/ItsHotdogFred\n- Itch.io:  itshotdogfred.itch.io\n- Email:    cli@itsfred.dev",
+		"idle warning":         " ⏳ Desconectando en %ds por inactividad, pulsa cualquier tecla para seguir ",
+		"languages":            "Idiomas:",
+		"lang hint":            "Usa 'lang <código>' para cambiar.",
+		"lang switched":        "Ahora en español.",
+		"lang unknown":         "lang: idioma desconocido %q, prueba con: %s",
+		"search none":          "No se encontró: %s (esc para salir)",
+		"search matches":       "Coincidencia %d de %d (n/N para moverte, esc para salir)",
+		"needs terminal":       "Este comando necesita una terminal a pantalla completa, pruébalo por SSH.",
+		"alias none":           "No hay alias definidos.",
+		"alias not found":      "alias: %s: no encontrado",
+		"alias builtin":        "alias: %s es un comando integrado",
+		"alias syntax":         "se esperaba nombre=valor",
+		"alias name":           "nombre de alias no válido %q",
+		"alias no command":     "el alias %s no tiene comando",
+		"alias redirect":       "los alias no pueden redirigir la salida",
+		"unalias usage":        "Uso: unalias <nombre>",
+		"unalias not found":    "unalias: %s: no encontrado",
+		"download usage":       "Uso: download <archivo>",
+		"download disabled":    "download: los enlaces solo están disponibles si el portafolio se sirve con --download-addr",
+		"download archive":     "download: no se pudo comprimir %s: %v",
+		"download too big":     "download: %s es demasiado grande para descargarlo",
+		"download limit":       "download: tienes demasiadas descargas pendientes, descárgalas o vuelve a intentarlo en %d minutos",
+		"download busy":        "download: el servidor tiene demasiadas descargas pendientes, vuelve a intentarlo en unos minutos",
+		"head usage":           "Uso: head [-n número] <archivo>",
+		"tail usage":           "Uso: tail [-n número] <archivo>",
+		"wc usage":             "Uso: wc <archivo>",
+		"line count":           "Número de líneas no válido: %s",
+		"tree usage":           "Uso: tree [profundidad]",
+		"grep usage":           "Uso: grep [-i] <patrón> [ruta]",
+		"grep pattern":         "Patrón no válido: %v",
+		"grep error":           "Error al buscar: %v",
+		"grep none":            "Sin coincidencias para: %s",
+		"grep match":           "1 coincidencia",
+		"grep matches":         "%d coincidencias",
+		"history empty":        "Todavía no hay comandos en el historial.",
+		"history hint":         "Repite una entrada con !N, o !! para el último comando.",
+		"weather usage":        "Uso: weather <ciudad>",
+		"weather error":        "Error al obtener el tiempo: %s",
+		"weather loading":      "Consultando el tiempo en %s",
+		"github usage":         "Uso: github [usuario]",
+		"github no user":       "github: no hay ningún usuario llamado %s",
+		"github rate limit":    "github: se alcanzó el límite de la API, inténtalo de nuevo en unos minutos",
+		"github error":         "Error al obtener el perfil de GitHub: %s",
+		"github loading":       "Consultando el perfil de GitHub de %s",
+		"resume usage":         "Uso: resume [download]",
+		"resume error":         "Error al leer el currículum: %v",
+		"resume download":      "Descarga mi currículum en PDF:\n%s\n\nO escanea esto con el móvil:\n\n%s",
+		"img usage":            "Uso: img <archivo>",
+		"img not image":        "img: %s no es una imagen PNG, JPEG o GIF",
+		"joke usage":           "Uso: joke [categoría]\nCategorías: %s",
+		"joke loading":         "Pensando un chiste",
+		"joke source":          "JokeAPI",
+		"joke offline":         "la lista de chistes sin conexión, no se pudo contactar con JokeAPI",
+		"joke from":            "(chiste de %s, de %s)",
+		"play usage":           "Uso: play [snake]",
+		"stats disabled":       "Las estadísticas de visitas solo se registran cuando se ejecuta como servidor.",
+		"stats error":          "Error al leer las estadísticas: %v",
+		"stats denied":         "Permiso denegado: stats solo está disponible para el operador.",
+		"stats password":       "Contraseña de operador: ",
+		"stats bad password":   "Permiso denegado: contraseña incorrecta",
+		"stats cancelled":      "stats: cancelado",
+		"stats esc":            "(esc para cancelar)",
+		"download link":        "Descarga %s desde este enlace, que funciona una vez en los próximos %d minutos:\n%s\n\nO escanea esto con el móvil:\n\n%s",
+		"contact usage":        "Uso: contact [send [mensaje]]",
+		"contact unset":        "El envío de mensajes no está configurado en este servidor. Escríbeme a %s.",
+		"contact title":        "✉️  Envíame un mensaje",
+		"contact keys":         "  (tab para cambiar de campo, ctrl+d para enviar, esc para cancelar)",
+		"contact email":        "Tu email: ",
+		"contact message":      "Saluda, haz una pregunta u ofréceme un trabajo...",
+		"contact bad email":    "Eso no parece una dirección de email.",
+		"contact empty":        "Escribe un mensaje primero.",
+		"contact wait":         "Ya has enviado varios mensajes, espera %s antes de enviar otro.",
+		"contact sending":      "Enviando...",
+		"contact failed":       "No se pudo enviar tu mensaje, inténtalo más tarde o escríbeme a %s.",
+		"contact sent":         "✉️  ¡Mensaje enviado, gracias! Te responderé en %s.",
+		"contact cancelled":    "contact: cancelado, no se envió nada",
+		"copy usage":           "Uso: copy <texto|archivo>",
+		"copy empty":           "copy: no hay nada que copiar",
+		"copy too big":         "copy: es demasiado grande para el portapapeles, prueba con download",
+		"copy no terminal":     "copy: no hay ninguna terminal a la que copiar, así que aquí lo tienes:\n%s",
+		"copied":               "Se copió %s al portapapeles.",
+		"copy preview":         "%q… (%d caracteres)",
+		"copy hint":            "¿No aparece nada? Puede que tu terminal no admita OSC 52 (en tmux, set-clipboard tiene que estar activado).",
+		"copy by hand":         "¿No aparece nada? Puede que tu terminal no admita OSC 52 (en tmux, set-clipboard tiene que estar activado), así que aquí lo tienes para copiarlo a mano:",
+		"cowsay usage":         "Uso: cowsay <texto>",
+		"price usage":          "Uso: price <símbolo>",
+		"price no symbol":      "price: no hay ningún símbolo llamado %s",
+		"price error":          "Error al obtener el precio: %s",
+		"price loading":        "Consultando el precio de %s",
+		"timer usage":          "Uso: timer <minutos> | timer stop",
+		"timer duration":       "timer: indica un número de minutos (hasta un día), o una duración como 90s",
+		"timer label":          "Temporizador de %s",
+		"timer done":           "⏰ ¡Se acabó el tiempo! %s ha terminado.",
+		"timer started":        "%s: en marcha, quedan %s.",
+		"timer left":           "%s: quedan %s",
+		"timer none":           "No hay ningún temporizador en marcha.",
+		"timer stopped":        "%s: detenido.",
+		"timer return":         "Pulsa cualquier tecla para volver a la terminal",
+		"timer keys":           "b para dejarlo en segundo plano • x para detenerlo",
+		"pomodoro usage":       "Uso: pomodoro [stop]",
+		"pomodoro focus":       "Concentración",
+		"pomodoro break":       "Descanso",
+		"focus done":           "🍅 Sesión de concentración terminada, toca un descanso de 5 minutos.",
+		"break done":           "☕ ¡Se acabó el descanso, a trabajar!",
+		"yoda usage":           "Uso: yoda [--seed n] <texto>",
+		"yoda seed":            "yoda: la semilla tiene que ser un número entero",
+		"yoda says":            "Yoda dice: %s",
+		"bookmark usage":       "Uso: bookmark [add <nombre> | list | rm <nombre>]",
+		"bookmark none":        "Todavía no hay marcadores. Guarda este directorio con bookmark add <nombre>.",
+		"bookmark name":        "bookmark: los nombres no pueden tener barras ni espacios",
+		"bookmarked":           "%s guardado como %s, usa go %s para volver.",
+		"bookmark not found":   "bookmark: %s: no encontrado",
+		"go usage":             "Uso: go <marcador>",
+		"go not found":         "go: %s: no existe ese marcador, mira bookmark list",
+		"date usage":           "Uso: date [zona] [+formato]",
+	},
+}
+
+// tr returns message id in the session's language, formatted with args.
+func (m model) tr(id string, args ...any) string {
+	msg, ok := messages[m.lang][id]
+	if !ok {
+		msg = messages[defaultLanguage][id]
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// messageError is an error whose text is message id, formatted with args,
+// so it can be shown in the visitor's language.
+type messageError struct {
+	id   string
+	args []any
+}
+
+func (e messageError) Error() string {
+	return fmt.Sprintf(messages[defaultLanguage][e.id], e.args...)
+}
+
+// errorText explains err in the session's language, if it's a messageError
+// or syntaxError.
+func (m *model) errorText(err error) string {
+	var me messageError
+	if errors.As(err, &me) {
+		return m.tr(me.id, me.args...)
+	}
+	return m.syntaxError(err)
+}
+
+// languageFromEnv picks a supported language from locale variables like
+// LANG=es_ES.UTF-8, checked in the order the C library uses them.
+func languageFromEnv(env []string) string {
+	vars := map[string]string{}
+	for _, kv := range env {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			vars[k] = v
+		}
+	}
+	for _, k := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		code, _, _ := strings.Cut(strings.ToLower(vars[k]), "_")
+		code, _, _ = strings.Cut(code, ".")
+		if _, ok := messages[code]; ok {
+			return code
+		}
+	}
+	return defaultLanguage
+}
+
+func langCommand(m *model, in commandInput) (string, tea.Cmd) {
+	code := strings.ToLower(in.args)
+	if code == "" {
+		var b strings.Builder
+		b.WriteString(m.tr("languages") + "\n")
+		for _, code := range languages {
+			marker := "  "
+			if code == m.lang {
+				marker = m.theme.accent.Render("* ")
+			}
+			fmt.Fprintf(&b, "%s%-4s %s\n", marker, code, languageNames[code])
+		}
+		b.WriteString("\n" + m.tr("lang hint"))
+		return b.String(), nil
+	}
+	if _, ok := messages[code]; !ok {
+		return m.tr("lang unknown", in.args, strings.Join(languages, ", ")), nil
+	}
+	m.lang = code
+	return m.tr("lang switched"), nil
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestMessagesHaveEnglish makes sure every message has the English text
+// other languages fall back to. Command summaries come from manPages instead.
+func TestMessagesHaveEnglish(t *testing.T) {
+	for _, lang := range languages {
+		for id := range messages[lang] {
+			if strings.HasPrefix(id, "summary ") {
+				continue
+			}
+			if messages[defaultLanguage][id] == "" {
+				t.Errorf("message %q in %s has no English text", id, lang)
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+)
+
+// idleTimeout disconnects SSH visitors who haven't pressed a key for this
+// long, so abandoned connections don't hold PTYs open forever. It can be set
+// with PORTFOLIO_IDLE_TIMEOUT (e.g. "30m"), and "0" turns it off.
+var idleTimeout = 15 * time.Minute
+
+// idleWarning is how long before the disconnect a countdown is shown.
+const idleWarning = time.Minute
+
+func init() {
+	if v := os.Getenv("PORTFOLIO_IDLE_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			log.Warn("Ignoring invalid PORTFOLIO_IDLE_TIMEOUT", "value", v)
+			return
+		}
+		idleTimeout = d
+	}
+}
+
+// idleCheckMsg asks the model to check how long the visitor has been idle.
+type idleCheckMsg struct{}
+
+func idleCheckAfter(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(time.Time) tea.Msg { return idleCheckMsg{} })
+}
+
+// checkIdle quits once the timeout is reached. Until then it sleeps until
+// the warning is due, then ticks every second to update the countdown.
+func (m model) checkIdle() tea.Cmd {
+	// Someone waiting on a timer isn't idle, and it ringing counts as input
+	if m.timer != nil {
+		return idleCheckAfter(idleWarning)
+	}
+	remaining := m.idleTimeout - time.Since(m.lastInput)
+	if remaining <= 0 {
+		return tea.Quit
+	}
+	if remaining > idleWarning {
+		return idleCheckAfter(remaining - idleWarning)
+	}
+	return idleCheckAfter(time.Second)
+}
+
+// idleFooter returns the countdown shown before an idle visitor is
+// disconnected, or "" if there's nothing to warn about yet.
+func (m model) idleFooter() string {
+	if m.idleTimeout == 0 || m.timer != nil {
+		return ""
+	}
+	remaining := m.idleTimeout - time.Since(m.lastInput)
+	if remaining > idleWarning {
+		return ""
+	}
+	seconds := max(0, int(remaining.Round(time.Second).Seconds()))
+	style := m.theme.style().Foreground(m.theme.colors.onAccent).Background(m.theme.colors.warning)
+	return style.Render(m.tr("idle warning", seconds))
+}
+
+// withFooter puts footer on the last line of view, dropping the top line if
+// that's what it takes to still fit the terminal.
+func (m model) withFooter(view, footer string) string {
+	if footer == "" {
+		return view
+	}
+	lines := strings.Split(view, "\n")
+	if m.height > 0 && len(lines) >= m.height {
+		lines = lines[len(lines)-m.height+1:]
+	}
+	return strings.Join(append(lines, footer), "\n")
+}
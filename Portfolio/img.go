@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// imgMaxWidth caps how wide an image is drawn, in terminal columns.
+const imgMaxWidth = 120
+
+// renderHalfBlocks draws img as rows of "▀" characters. Each character shows
+// two pixels: the top one in the foreground colour and the bottom one in the
+// background colour, which roughly squares up terminal cells.
+func renderHalfBlocks(img image.Image, width int) string {
+	bounds := img.Bounds()
+	width = max(1, min(width, bounds.Dx()))
+	// Two pixel rows per line, keeping the aspect ratio
+	height := max(2, bounds.Dy()*width/bounds.Dx())
+	height += height % 2
+
+	var b strings.Builder
+	for y := 0; y < height; y += 2 {
+		for x := 0; x < width; x++ {
+			top := averageColor(img, x, y, width, height)
+			bottom := averageColor(img, x, y+1, width, height)
+			fmt.Fprintf(&b, "\x1b[38;2;%d;%d;%dm\x1b[48;2;%d;%d;%dm▀", top.R, top.G, top.B, bottom.R, bottom.G, bottom.B)
+		}
+		b.WriteString("\x1b[0m\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// averageColor returns the mean colour of the source pixels that map onto
+// cell (x, y) of a width x height grid. Transparent pixels blend to black.
+func averageColor(img image.Image, x, y, width, height int) color.RGBA {
+	bounds := img.Bounds()
+	x0 := bounds.Min.X + x*bounds.Dx()/width
+	x1 := max(x0+1, bounds.Min.X+(x+1)*bounds.Dx()/width)
+	y0 := bounds.Min.Y + y*bounds.Dy()/height
+	y1 := max(y0+1, bounds.Min.Y+(y+1)*bounds.Dy()/height)
+
+	var r, g, bl, n uint32
+	for py := y0; py < y1 && py < bounds.Max.Y; py++ {
+		for px := x0; px < x1 && px < bounds.Max.X; px++ {
+			// RGBA is alpha-premultiplied, so transparency fades to black for free
+			pr, pg, pb, _ := img.At(px, py).RGBA()
+			r, g, bl = r+pr>>8, g+pg>>8, bl+pb>>8
+			n++
+		}
+	}
+	if n == 0 {
+		return color.RGBA{}
+	}
+	return color.RGBA{R: uint8(r / n), G: uint8(g / n), B: uint8(bl / n), A: 255}
+}
+
+func imgCommand(m *model, in commandInput) (string, tea.Cmd) {
+	if in.args == "" {
+		return m.tr("img usage"), nil
+	}
+	content, err := m.readFile(in.args)
+	if err != nil {
+		return err.Error(), nil
+	}
+	img, _, err := image.Decode(strings.NewReader(content))
+	if err != nil {
+		return m.tr("img not image", in.args), nil
+	}
+
+	width := imgMaxWidth
+	if m.ready {
+		width = min(width, m.viewport.Width-2)
+	}
+	return renderHalfBlocks(img, width), nil
+}
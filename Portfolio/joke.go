@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Jokes come from JokeAPI, or from an embedded list when it can't be
+// reached. Each entry in the list starts with a line naming its category and
+// entries are separated by lines holding a single %, like the fortunes.
+//
+//go:embed Extra/jokes.txt
+var jokeFile string
+
+// jokeTimeout is how long to wait for JokeAPI before telling an offline
+// joke instead. It's shorter than apiTimeout, as nobody waits long for a joke.
+const jokeTimeout = 3 * time.Second
+
+// jokeCategories are the JokeAPI categories visitors can ask for. The API's
+// Dark category is left out, and so are jokes it flags as unsafe.
+var jokeCategories = []string{"programming", "misc", "pun", "spooky", "christmas"}
+
+type joke struct {
+	category string
+	text     string
+}
+
+// offlineJokes returns the embedded jokes in category, or all of them for "".
+func offlineJokes(category string) []joke {
+	var list []joke
+	for _, entry := range strings.Split(jokeFile, "\n%\n") {
+		cat, text, _ := strings.Cut(strings.TrimSpace(entry), "\n")
+		if text != "" && (category == "" || cat == category) {
+			list = append(list, joke{category: cat, text: text})
+		}
+	}
+	return list
+}
+
+// fetchJoke asks JokeAPI for a joke in category, any category for "".
+func fetchJoke(category string) (joke, error) {
+	name := "Any"
+	if category != "" {
+		name = strings.ToUpper(category[:1]) + category[1:]
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), jokeTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://v2.jokeapi.dev/joke/"+name+"?safe-mode", nil)
+	if err != nil {
+		return joke{}, err
+	}
+	var data struct {
+		Error    bool   `json:"error"`
+		Message  string `json:"message"`
+		Category string `json:"category"`
+		Type     string `json:"type"`
+		Joke     string `json:"joke"`
+		Setup    string `json:"setup"`
+		Delivery string `json:"delivery"`
+	}
+	if err := doJSON(req, &data); err != nil {
+		return joke{}, err
+	}
+	if data.Error {
+		return joke{}, fmt.Errorf("%s", data.Message)
+	}
+	text := data.Joke
+	if data.Type == "twopart" {
+		text = data.Setup + "\n" + data.Delivery
+	}
+	return joke{category: strings.ToLower(data.Category), text: text}, nil
+}
+
+// jokeResult tells a joke from JokeAPI, falling back to the offline list,
+// with where it came from underneath in the language of tr.
+func jokeResult(category string, t *theme, tr func(string, ...any) string) string {
+	j, err := fetchJoke(category)
+	source := tr("joke source")
+	if err != nil {
+		list := offlineJokes(category)
+		j = list[rand.Intn(len(list))]
+		source = tr("joke offline")
+	}
+	return j.text + "\n" + t.muted.Render(tr("joke from", j.category, source))
+}
+
+func jokeCommand(m *model, in commandInput) (string, tea.Cmd) {
+	category := strings.ToLower(in.args)
+	if category == "any" {
+		category = ""
+	}
+	if category != "" && len(offlineJokes(category)) == 0 {
+		return m.tr("joke usage", strings.Join(jokeCategories, ", ")), nil
+	}
+	if !in.toTerminal {
+		return jokeResult(category, m.theme, m.tr), nil
+	}
+	t, tr := m.theme, m.tr
+	return "", m.startApp(newLoader(t, tr("joke loading"), func() string {
+		return jokeResult(category, t, tr)
+	}))
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+)
+
+// Connection caps for the SSH server. They can be changed with the
+// PORTFOLIO_MAX_SESSIONS and PORTFOLIO_MAX_SESSIONS_PER_IP environment
+// variables.
+var (
+	maxSessions      = 100
+	maxSessionsPerIP = 3
+)
+
+func init() {
+	for name, limit := range map[string]*int{
+		"PORTFOLIO_MAX_SESSIONS":        &maxSessions,
+		"PORTFOLIO_MAX_SESSIONS_PER_IP": &maxSessionsPerIP,
+	} {
+		if v := os.Getenv(name); v != "" {
+			if n, err := strconv.Atoi(v); err == nil && n > 0 {
+				*limit = n
+			} else {
+				log.Warn("Ignoring invalid session limit", "variable", name, "value", v)
+			}
+		}
+	}
+}
+
+// sessionLimiter counts open sessions, overall and per client IP.
+type sessionLimiter struct {
+	mu    sync.Mutex
+	total int
+	perIP map[string]int
+}
+
+// acquire reserves a session slot for ip. It returns the reason if the
+// session should be turned away.
+func (l *sessionLimiter) acquire(ip string) (string, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	switch {
+	case l.total >= maxSessions:
+		return "max sessions reached", false
+	case l.perIP[ip] >= maxSessionsPerIP:
+		return "max sessions per IP reached", false
+	}
+	l.total++
+	l.perIP[ip]++
+	return "", true
+}
+
+// active returns how many sessions are open.
+func (l *sessionLimiter) active() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.total
+}
+
+func (l *sessionLimiter) release(ip string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.total--
+	if l.perIP[ip]--; l.perIP[ip] <= 0 {
+		delete(l.perIP, ip)
+	}
+}
+
+// sessions counts the open SSH and web sessions, for the caps and top.
+var sessions = &sessionLimiter{perIP: map[string]int{}}
+
+// limitMiddleware turns sessions away once the caps are reached, so a single
+// client can't exhaust the host.
+func limitMiddleware() wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			ip := remoteIP(s)
+			reason, ok := sessions.acquire(ip)
+			if !ok {
+				log.Warn("Rejected connection", "ip", ip, "user", s.User(), "reason", reason,
+					"max_sessions", maxSessions, "max_sessions_per_ip", maxSessionsPerIP)
+				wish.Fatalln(s, "Sorry, the portfolio is busy right now. Please try again in a few minutes!")
+				return
+			}
+			defer sessions.release(ip)
+			next(s)
+		}
+	}
+}
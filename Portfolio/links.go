@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// linkPattern finds links in output: URLs, email addresses and bare domains
+// like github.com/ItsHotdogFred. Bare domains are limited to a few common
+// endings so file names like bio.txt aren't mistaken for links.
+var linkPattern = regexp.MustCompile(`https?://[^\s\x1b<>"']*[^\s\x1b<>"'.,;:!?)]` +
+	`|[\w.+-]+@[\w-]+(?:\.[\w-]+)+` +
+	`|\b(?:[\w-]+\.)+(?:com|dev|io|org|net|app)\b(?:/[^\s\x1b<>"']*[^\s\x1b<>"'.,;:!?)])?`)
+
+// href returns the address a link found by linkPattern points to.
+func href(link string) string {
+	switch {
+	case strings.Contains(link, "://"):
+		return link
+	case strings.Contains(link, "@"):
+		return "mailto:" + link
+	default:
+		return "https://" + link
+	}
+}
+
+// supportsHyperlinks guesses from the terminal's environment whether it
+// understands OSC 8 hyperlinks. Terminals that don't would print the escape
+// codes, so anything unknown gets plain text.
+func supportsHyperlinks(env []string, term string) bool {
+	vars := map[string]string{"TERM": term}
+	for _, kv := range env {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			vars[k] = v
+		}
+	}
+	switch vars["TERM_PROGRAM"] {
+	case "iTerm.app", "WezTerm", "vscode", "ghostty", "Hyper":
+		return true
+	}
+	if vars["WT_SESSION"] != "" || vars["KITTY_WINDOW_ID"] != "" {
+		return true
+	}
+	// GNOME Terminal and other VTE terminals since 0.50
+	if v, err := strconv.Atoi(vars["VTE_VERSION"]); err == nil && v >= 5000 {
+		return true
+	}
+	switch vars["TERM"] {
+	case "xterm-kitty", "xterm-ghostty", "wezterm", "foot", "alacritty":
+		return true
+	}
+	return false
+}
+
+// hyperlink wraps the links in each line of view in OSC 8 sequences. This
+// happens last, on the finished view, because lipgloss counts the link
+// addresses as visible text. Lines that would then look too wide to Bubble
+// Tea's renderer, which cuts them off, are left alone.
+func (m model) hyperlink(view string) string {
+	if !m.hyperlinks {
+		return view
+	}
+	lines := strings.Split(view, "\n")
+	for i, line := range lines {
+		if !linkPattern.MatchString(line) {
+			continue
+		}
+		linked := linkPattern.ReplaceAllStringFunc(line, func(link string) string {
+			return "\x1b]8;;" + href(link) + "\x1b\\" + link + "\x1b]8;;\x1b\\"
+		})
+		if m.width == 0 || lipgloss.Width(linked) <= m.width {
+			lines[i] = linked
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// linkAt returns the address of the link at column x of a rendered line.
+func linkAt(line string, x int) (string, bool) {
+	plain := ansiEscape.ReplaceAllString(line, "")
+	for _, loc := range linkPattern.FindAllStringIndex(plain, -1) {
+		start := lipgloss.Width(plain[:loc[0]])
+		end := start + lipgloss.Width(plain[loc[0]:loc[1]])
+		if x >= start && x < end {
+			return href(plain[loc[0]:loc[1]]), true
+		}
+	}
+	return "", false
+}
+
+// clickedLink returns the link under a left click on vp, which is showing
+// content with its top row on screen row top.
+func clickedLink(vp viewport.Model, content string, top int, msg tea.MouseMsg) (string, bool) {
+	if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+		return "", false
+	}
+	row := msg.Y - top
+	lines := strings.Split(content, "\n")
+	if row < 0 || row >= vp.Height || vp.YOffset+row >= len(lines) {
+		return "", false
+	}
+	return linkAt(lines[vp.YOffset+row], msg.X)
+}
+
+// copyToClipboard asks the visitor's terminal to put text on the clipboard
+// with OSC 52, which works over SSH too.
+func copyToClipboard(out io.Writer, text string) tea.Cmd {
+	if out == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		fmt.Fprintf(out, "\x1b]52;c;%s\x07", base64.StdEncoding.EncodeToString([]byte(text)))
+		return nil
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/charmbracelet/log"
+)
+
+const (
+	// logMaxSize is how big a log file grows before it's rotated.
+	logMaxSize = 10 << 20
+	// logMaxBackups is how many rotated files are kept, as app.log.1 and so on.
+	logMaxBackups = 5
+)
+
+// setupLogging configures the default logger from the --log-* flags. The
+// level is debug, info, warn or error, and the format text, json or logfmt.
+// With a file, logs go there instead of stderr and are rotated by size.
+func setupLogging(level, format, file string) error {
+	lvl, err := log.ParseLevel(level)
+	if err != nil {
+		return fmt.Errorf("invalid log level %q", level)
+	}
+	log.SetLevel(lvl)
+
+	switch format {
+	case "text":
+		log.SetFormatter(log.TextFormatter)
+	case "json":
+		log.SetFormatter(log.JSONFormatter)
+	case "logfmt":
+		log.SetFormatter(log.LogfmtFormatter)
+	default:
+		return fmt.Errorf("invalid log format %q, use text, json or logfmt", format)
+	}
+	log.SetReportTimestamp(true)
+
+	if file != "" {
+		f, err := openRotatingFile(file, logMaxSize, logMaxBackups)
+		if err != nil {
+			return err
+		}
+		log.SetOutput(f)
+	}
+	return nil
+}
+
+// newSessionID returns a short random id that ties a session's log lines
+// together.
+func newSessionID() string {
+	b := make([]byte, 4)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// rotatingFile is a log file that's moved aside once it reaches maxSize,
+// keeping the most recent backups.
+type rotatingFile struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64
+	backups int
+	file    *os.File
+	size    int64
+}
+
+func openRotatingFile(path string, maxSize int64, backups int) (*rotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	r := &rotatingFile{path: path, maxSize: maxSize, backups: backups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.file, r.size = f, info.Size()
+	return nil
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			// Keep logging to the full file rather than losing lines
+			fmt.Fprintf(os.Stderr, "Could not rotate log file: %v\n", err)
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate shifts app.log to app.log.1, app.log.1 to app.log.2 and so on,
+// dropping the oldest, and starts a new file. The old file stays open until
+// the new one is, so if anything fails, logging carries on where it was.
+func (r *rotatingFile) rotate() error {
+	for i := r.backups - 1; i >= 1; i-- {
+		os.Rename(r.path+"."+strconv.Itoa(i), r.path+"."+strconv.Itoa(i+1))
+	}
+	if err := os.Rename(r.path, r.path+".1"); err != nil {
+		return err
+	}
+	old := r.file
+	if err := r.open(); err != nil {
+		return err
+	}
+	return old.Close()
+}
@@ -2,15 +2,14 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
-	"math/rand"
+	"io"
 	"net"
-	"net/http"
 	"os"
 	"os/signal"
-	"runtime"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -25,105 +24,324 @@ import (
 	"github.com/charmbracelet/wish/activeterm"
 	"github.com/charmbracelet/wish/bubbletea"
 	"github.com/charmbracelet/wish/logging"
-	"github.com/mdp/qrterminal/v3"
-	gowiki "github.com/trietmn/go-wiki"
+	gossh "golang.org/x/crypto/ssh"
 )
 
 type model struct {
 	input               textinput.Model
 	viewport            viewport.Model
 	ready               bool
-	startingpath        string
-	directory           string
+	directory           string         // current directory, relative to portfolioRoot
+	timezone            *time.Location // the visitor's time zone, nil if unknown
+	connected           time.Time      // when the session started
+	commandsRun         int            // command lines run this session
+	timer               *countdown     // the running timer or pomodoro, nil if none
+	alarm               string         // shown at the bottom after a timer rings, until a key is pressed
 	text                string
 	history             []string
-	historyIndex        int // -1 means not browsing history
-	clihistory          []string
+	historyIndex        int            // -1 means not browsing history
+	historyFile         string         // where history is persisted, empty to disable
+	clihistory          []entry        // the scrollback, rendered by refreshViewport
 	fileViewMode        bool           // true if viewing a file
 	fileViewport        viewport.Model // dedicated viewport for file viewing
 	fileContent         string         // content of the file being viewed
 	commandautocomplete []string
-	fileautocomplete    []string
-	autocompletelist    []string
+	completion          completionState   // tab-completion menu state
+	sessionFiles        map[string]string // files created by output redirection, keyed by path
+	app                 tea.Model         // full-screen sub-model, nil while in the shell
+	highScores          map[string]int    // best score per game this session
+	progress            *progress         // achievements unlocked, saved per SSH key
+	width, height       int               // terminal size, used to start apps
+	operator            bool              // connected with an operator SSH key
+	ip                  string            // the visitor's IP address, empty locally
+	statsSession        int64             // analytics row for this visitor, 0 if not recorded
+	done                <-chan struct{}   // closed when the SSH session ends, nil locally
+	aliases             map[string]string // alias name to the command it runs
+	pinned              bool              // keep the scrollback where it is instead of following new output
+	bookmarks           map[string]string // bookmark name to the directory go jumps to
+	demo                *demoPlayer       // the demo being played, if any
+	kioskScript         []demoStep        // set with --demo to replay the demo whenever idle
+	lastInput           time.Time         // last key press, for the kiosk demo and idle timeout
+	idleTimeout         time.Duration     // disconnect after this long without a key press, 0 for never
+	theme               *theme            // colours for this session, changed with the theme command
+	lang                string            // language code for messages, changed with the lang command
+	scrollSearch        search            // searching the scrollback with /
+	pagerSearch         search            // searching the file viewer with /
+	pagerNotice         string            // shown under the file viewer until the next key press
+	pagerTitle          string            // shown in the file viewer's header
+	hyperlinks          bool              // the terminal understands OSC 8 links
+	out                 io.Writer         // the visitor's terminal, for escape codes like OSC 52
+	headless            bool              // run through Execute, with no terminal to draw on
+	logger              *log.Logger       // tags log lines with the session, discards them locally
 }
 
-var headerstyle = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
-
-const (
-	host = ""
-	port = "2222"
-)
-
-const isServer = false // Set to true to enable the server, false to disable it
+// startServer serves the portfolio over SSH on addr until interrupted.
+func startServer(addr, hostKeyPath string) {
+	if a, err := openAnalytics(analyticsPath()); err != nil {
+		log.Error("Could not open analytics database", "error", err)
+	} else {
+		visitorStats = a
+	}
 
-func startServer() {
 	s, err := wish.NewServer(
-		wish.WithAddress(net.JoinHostPort(host, port)),
-		wish.WithHostKeyPath(".ssh/id_ed25519"),
+		wish.WithAddress(addr),
+		wish.WithHostKeyPath(hostKeyPath),
+		// Anyone can log in. Keys are only asked for so history, achievements
+		// and the operator commands can follow visitors between sessions.
+		wish.WithPublicKeyAuth(func(ssh.Context, ssh.PublicKey) bool { return true }),
+		wish.WithKeyboardInteractiveAuth(func(ssh.Context, gossh.KeyboardInteractiveChallenge) bool { return true }),
 		wish.WithMiddleware(
 			bubbletea.Middleware(teaHandler),
 			activeterm.Middleware(), // Bubble Tea apps usually require a PTY.
-			logging.Middleware(),
+			limitMiddleware(),
+			logging.MiddlewareWithLogger(log.Default()),
 		),
 	)
 	if err != nil {
 		log.Error("Could not start server", "error", err)
 	}
 
-	done := make(chan os.Signal, 1)
-	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
-	log.Info("Starting SSH server", "host", host, "port", port)
+	ln, err := listen("ssh", addr)
+	if err != nil {
+		log.Error("Could not start server", "error", err)
+		return
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGUSR2)
+	failed := make(chan struct{})
+	log.Info("Starting SSH server", "address", addr)
 	go func() {
-		if err = s.ListenAndServe(); err != nil && !errors.Is(err, ssh.ErrServerClosed) {
+		if err = s.Serve(ln); err != nil && !errors.Is(err, ssh.ErrServerClosed) && !closedListener(err) {
 			log.Error("Could not start server", "error", err)
-			done <- nil
+			close(failed)
 		}
 	}()
 
-	<-done
+	timeout := 30 * time.Second
+wait:
+	for {
+		select {
+		case <-failed:
+			break wait
+		case sig := <-signals:
+			switch sig {
+			case syscall.SIGHUP:
+				reload()
+				continue
+			case syscall.SIGUSR2:
+				if err := handOver(); err != nil {
+					log.Error("Could not start new server", "error", err)
+					continue
+				}
+				// The new server is accepting now, so let this one's
+				// visitors finish their sessions
+				drain()
+				timeout = 5 * time.Second
+			}
+			break wait
+		}
+	}
+
 	log.Info("Stopping SSH server")
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer func() { cancel() }()
 	if err := s.Shutdown(ctx); err != nil && !errors.Is(err, ssh.ErrServerClosed) {
 		log.Error("Could not stop server", "error", err)
 	}
 }
 
+// visitor describes a remote connection, whichever way it came in.
+type visitor struct {
+	transport     string             // ssh, web or telnet
+	renderer      *lipgloss.Renderer // knows the visitor's terminal, not the server's
+	env           []string           // variables like LANG and TZ, as KEY=value
+	ip            string
+	width, height int
+	out           io.Writer
+	done          <-chan struct{} // closed when the connection ends
+	historyFile   string          // empty for no saved history
+	progressFile  string          // empty to keep achievements for the session only
+	hyperlinks    bool
+}
+
+// newVisitorModel sets up the shell for a remote visitor, over SSH or the web.
+func newVisitorModel(v visitor) model {
+	m := initialModel(v.renderer, languageFromEnv(v.env))
+	m.historyFile = v.historyFile
+	m.hyperlinks = v.hyperlinks
+	m.timezone = timezoneFromEnv(v.env)
+	m.out = v.out
+	m.history = loadHistory(m.historyFile)
+	m.progress = loadProgress(v.progressFile)
+	m.done = v.done
+	m.ip = v.ip
+	m.idleTimeout = idleTimeout
+	m.lastInput = time.Now()
+	m.logger = log.With("session", newSessionID(), "transport", v.transport, "ip", v.ip)
+	m.logger.Info("Session started", "width", v.width, "height", v.height)
+	if visitorStats != nil {
+		m.statsSession = visitorStats.startSession(v.ip, v.width, v.height)
+	}
+	logger, statsSession, connected := m.logger, m.statsSession, m.connected
+	go func() {
+		<-v.done
+		logger.Info("Session ended", "duration", time.Since(connected).Round(time.Second))
+		if statsSession != 0 {
+			visitorStats.endSession(statsSession, connected)
+		}
+	}()
+	return m
+}
+
 func teaHandler(s ssh.Session) (tea.Model, []tea.ProgramOption) {
-	m := initialModel()
+	pty, _, _ := s.Pty()
+	m := newVisitorModel(visitor{
+		transport:    "ssh",
+		renderer:     bubbletea.MakeRenderer(s),
+		env:          s.Environ(),
+		ip:           remoteIP(s),
+		width:        pty.Window.Width,
+		height:       pty.Window.Height,
+		out:          s,
+		done:         s.Context().Done(),
+		historyFile:  sessionHistoryPath(s),
+		progressFile: sessionProgressPath(s),
+		hyperlinks:   supportsHyperlinks(s.Environ(), pty.Term),
+	})
+	m.operator = isOperator(s)
 	return m, []tea.ProgramOption{tea.WithAltScreen(), tea.WithMouseCellMotion()}
 }
 
-func initialModel() model {
+func initialModel(r *lipgloss.Renderer, lang string) model {
 	ti := textinput.New()
 	ti.Placeholder = ""
 	ti.Focus()
 	ti.CharLimit = 156
 	ti.Width = 60
 	vp := viewport.New(0, 0)
-	return model{
+	m := model{
+		theme:               defaultTheme(r),
+		lang:                lang,
 		input:               ti,
 		viewport:            vp,
-		startingpath:        ".",
 		directory:           ".",
+		connected:           time.Now(),
 		text:                "nothing yet...",
 		historyIndex:        -1,
-		clihistory:          []string{headerView(), "Welcome to Fred's Portfolio CLI!\n\nNavigation:\n• Use scroll wheel or arrow keys to browse command history\n• Use Page Up/Page Down to navigate viewport\n• Type 'help' to see all available commands\n\nGet started with 'ls' to explore or 'help' for guidance."},
-		commandautocomplete: []string{"help", "ls", "pwd", "cd", "cat", "whoami", "date", "version", "neofetch", "skills", "contact", "qr", "coinflip", "echo", "joke", "wiki", "clear", "exit", "yoda"},
+		sessionFiles:        map[string]string{},
+		highScores:          map[string]int{},
+		progress:            loadProgress(""),
+		logger:              log.New(io.Discard),
+		aliases:             sessionAliases(),
+		bookmarks:           map[string]string{},
+		commandautocomplete: commandNames(),
 	}
+	m.clihistory = []entry{bannerEntry(), messageEntry("welcome")}
+	return m
 }
 
 // Init implements the tea.Model interface.
 func (m model) Init() tea.Cmd {
-	return textinput.Blink
+	cmds := []tea.Cmd{textinput.Blink}
+	if m.kioskScript != nil {
+		// Nobody has pressed a key yet, so the demo starts right away
+		cmds = append(cmds, func() tea.Msg { return demoIdleMsg{} })
+	}
+	if m.idleTimeout > 0 {
+		cmds = append(cmds, m.checkIdle())
+	}
+	return tea.Batch(cmds...)
 }
 
 func main() {
-	if isServer {
-		startServer()
+	demo := flag.Bool("demo", false, "replay the demo in a loop, restarting it whenever the portfolio is left idle")
+	demoScript := flag.String("demo-script", "", "file with a demo script to play instead of the built-in one (implies --demo)")
+	serve := flag.Bool("serve", envBool("PORTFOLIO_SERVE"), "run as an SSH server instead of in this terminal (env PORTFOLIO_SERVE)")
+	host := flag.String("host", envOr("PORTFOLIO_HOST", ""), "address to listen on with --serve, empty for all interfaces (env PORTFOLIO_HOST)")
+	port := flag.Int("port", envInt("PORTFOLIO_PORT", 2222), "port to listen on with --serve (env PORTFOLIO_PORT)")
+	hostKey := flag.String("host-key", envOr("PORTFOLIO_HOST_KEY", ".ssh/id_ed25519"), "SSH host key, generated if it doesn't exist (env PORTFOLIO_HOST_KEY)")
+	downloadAddr := flag.String("download-addr", envOr("PORTFOLIO_DOWNLOAD_ADDR", ""), "address to serve download links on with --serve, e.g. :8080, empty to disable (env PORTFOLIO_DOWNLOAD_ADDR)")
+	downloadURL := flag.String("download-url", envOr("PORTFOLIO_DOWNLOAD_URL", ""), "public URL of the download server, e.g. https://dl.example.com (env PORTFOLIO_DOWNLOAD_URL)")
+	pluginDir := flag.String("plugins", envOr("PORTFOLIO_PLUGINS", "plugins"), "directory of plugin executables that add commands (env PORTFOLIO_PLUGINS)")
+	webAddr := flag.String("web-addr", envOr("PORTFOLIO_WEB_ADDR", ""), "address to serve the browser terminal on with --serve, e.g. :8081, empty to disable (env PORTFOLIO_WEB_ADDR)")
+	telnetAddr := flag.String("telnet-addr", envOr("PORTFOLIO_TELNET_ADDR", ""), "address to serve a plain text, line by line version on over telnet with --serve, e.g. :2323, empty to disable (env PORTFOLIO_TELNET_ADDR)")
+	root := flag.String("root", envOr("PORTFOLIO_ROOT", "."), "directory visitors can browse, shown to them as ~ (env PORTFOLIO_ROOT)")
+	command := flag.String("c", "", "run a single command line, print its output and exit")
+	logLevel := flag.String("log-level", envOr("PORTFOLIO_LOG_LEVEL", "info"), "lowest level to log: debug, info, warn or error (env PORTFOLIO_LOG_LEVEL)")
+	logFormat := flag.String("log-format", envOr("PORTFOLIO_LOG_FORMAT", "text"), "log format: text, json or logfmt (env PORTFOLIO_LOG_FORMAT)")
+	logFile := flag.String("log-file", envOr("PORTFOLIO_LOG_FILE", ""), "file to log to instead of stderr, rotated as it grows (env PORTFOLIO_LOG_FILE)")
+	flag.Parse()
+
+	if err := setupLogging(*logLevel, *logFormat, *logFile); err != nil {
+		fmt.Printf("Could not set up logging: %v\n", err)
+		os.Exit(2)
+	}
+
+	if !validatePath(*root) {
+		fmt.Printf("Invalid root %s: not a directory\n", *root)
+		os.Exit(2)
+	}
+	portfolioRoot = *root
+	setConfig(loadConfig(configPath()))
+	loadPlugins(*pluginDir)
+	watchMOTD(motdPath())
+	if *command != "" {
+		m := initialModel(lipgloss.DefaultRenderer(), languageFromEnv(os.Environ()))
+		output, _ := Execute(m, *command)
+		if output != "" {
+			fmt.Println(output)
+		}
+		return
+	}
+	if *serve {
+		if *port < 1 || *port > 65535 {
+			fmt.Printf("Invalid port %d\n", *port)
+			os.Exit(2)
+		}
+		inheritListeners()
+		writePIDFile()
+		if *downloadAddr != "" {
+			url := *downloadURL
+			if url == "" {
+				url = "http://localhost" + *downloadAddr
+				log.Warn("No --download-url given, links will only work on this machine", "url", url)
+			}
+			startDownloads(*downloadAddr, url)
+		}
+		if *webAddr != "" {
+			startWeb(*webAddr)
+		}
+		if *telnetAddr != "" {
+			startTelnet(*telnetAddr)
+		}
+		startServer(net.JoinHostPort(*host, strconv.Itoa(*port)), *hostKey)
 	} else {
+		m := initialModel(lipgloss.DefaultRenderer(), languageFromEnv(os.Environ()))
+		m.historyFile = localHistoryPath()
+		m.hyperlinks = supportsHyperlinks(os.Environ(), os.Getenv("TERM"))
+		m.out = os.Stdout
+		m.history = loadHistory(m.historyFile)
+		m.progress = loadProgress(localProgressPath())
+		if *demo || *demoScript != "" {
+			script := defaultDemoScript
+			if *demoScript != "" {
+				data, err := os.ReadFile(*demoScript)
+				if err != nil {
+					fmt.Printf("Could not read demo script: %v\n", err)
+					os.Exit(1)
+				}
+				script = string(data)
+			}
+			steps, err := parseDemoScript(script)
+			if err != nil {
+				fmt.Printf("Invalid demo script %s: %v\n", *demoScript, err)
+				os.Exit(1)
+			}
+			m.kioskScript = steps
+		}
 		p := tea.NewProgram(
-			initialModel(),
+			m,
 			tea.WithAltScreen(),
 			tea.WithMouseCellMotion(),
 		)
@@ -139,15 +357,50 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmd  tea.Cmd
 		cmds []tea.Cmd
 	)
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		m.lastInput = time.Now()
+		m.alarm = ""
+	case idleCheckMsg:
+		return m, m.checkIdle()
+	case timerTickMsg:
+		return m.updateTimer(msg)
+	case timerStopMsg:
+		if m.timer != nil && m.timer.id == msg.id {
+			m.timer = nil
+		}
+		return m, nil
+	}
+	// A demo gives way as soon as a key is pressed
+	var handled bool
+	if m, cmd, handled = m.updateDemo(msg); handled {
+		return m, cmd
+	}
+	// A running app (like a game) gets every message until it exits
+	if m.app != nil {
+		return m.updateApp(msg)
+	}
 	// Handle file view mode
 	if m.fileViewMode {
 		switch msg := msg.(type) {
+		case tea.MouseMsg:
+			content := m.pagerSearch.highlight(m.fileContent, m.theme)
+			if link, ok := clickedLink(m.fileViewport, content, lipgloss.Height(m.fileHeaderView()), msg); ok {
+				m.pagerNotice = "Copied " + link + " to your clipboard"
+				return m, copyToClipboard(m.out, link)
+			}
 		case tea.KeyMsg:
+			m.pagerNotice = ""
+			if m.pagerSearch.handleKey(msg, &m.fileViewport, m.fileContent) {
+				m.fileViewport.SetContent(m.pagerSearch.highlight(m.fileContent, m.theme))
+				return m, nil
+			}
 			switch msg.String() {
 			case "q", "esc":
 				m.fileViewMode = false
 				m.fileContent = ""
 				m.fileViewport = viewport.Model{}
+				m.pagerSearch = search{}
 				return m, nil
 			}
 		case tea.WindowSizeMsg:
@@ -161,13 +414,34 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		var fileCmd tea.Cmd
 		m.fileViewport, fileCmd = m.fileViewport.Update(msg)
+		if m.pagerTitle == "resume" && m.fileViewport.AtBottom() {
+			if _, read := m.progress.Unlocked["hiring-manager"]; !read {
+				m.progress.unlock("hiring-manager")
+				m.announceAchievements()
+				m.pagerNotice = "🏆 Achievement unlocked: 📄 Hiring manager"
+			}
+		}
 		return m, fileCmd
 	}
+	// / on an empty prompt searches the scrollback, and any key the search
+	// doesn't use goes back to the prompt
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && (m.scrollSearch.active() || keyMsg.String() == "/" && m.input.Value() == "") {
+		if m.scrollSearch.handleKey(keyMsg, &m.viewport, m.scrollback()) {
+			m.refreshViewport()
+			return m, nil
+		}
+		m.scrollSearch = search{}
+	}
 	switch msg := msg.(type) {
 
 	// Is it a key press?
 	case tea.KeyMsg:
 
+		// Any key other than Tab closes the completion menu
+		if msg.String() != "tab" && msg.String() != "shift+tab" {
+			m.completion = completionState{}
+		}
+
 		switch msg.String() {
 
 		// These keys should exit the program.
@@ -175,386 +449,34 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		case "enter":
 			inputValue := m.input.Value()
-			text := inputValue
-			m.text = text
-			m.history = append(m.history, text)
-			m.historyIndex = -1 // Reset history navigation on new entry
-			if len(inputValue) >= 3 && inputValue[:3] == "cd " {
-
-				if m.text != "" && m.text != "nothing yet..." {
-					dirToAdd := m.text[3:] // get everything after 'cd '
-					if dirToAdd == ".." {
-						parts := strings.Split(m.directory, "/")
-						if len(parts) > 1 {
-							m.directory = strings.Join(parts[:len(parts)-1], "/")
-						} else {
-							m.directory = m.startingpath
-						}
-					} else {
-						// Check if trying to access a hidden directory
-						if strings.HasPrefix(dirToAdd, ".") {
-							m.text = "Access denied: Hidden directories are not accessible"
-						} else if validatePath(m.directory + "/" + dirToAdd) {
-							m.directory = m.directory + "/" + dirToAdd
-						} else {
-							m.text = "Invalid directory: " + dirToAdd
-						}
-					}
-				}
-				m.input.Reset()
-
-			} else if inputValue == "ls" {
-				// If the command is 'ls', use validatePath to list files in the current directory
-				s := ""
-				entries, err := os.ReadDir(m.directory)
-				if err != nil {
-					s += fmt.Sprintf("Error reading directory: %v\n", err)
-				}
-				s += "\nName\n------\n"
-
-				// Define styles for folders and files
-				folderStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#90EE90")) // Pastel green
-				fileStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#DDA0DD"))   // Pastel purple
-
-				for _, entry := range entries {
-					// Skip hidden files/folders (those starting with .)
-					if !strings.HasPrefix(entry.Name(), ".") {
-						if entry.IsDir() {
-							s += folderStyle.Render("📁 "+entry.Name()) + "\n"
-						} else {
-							s += fileStyle.Render("📄 "+entry.Name()) + "\n"
-						}
-					}
-				}
-				m.text = s
-				m.input.Reset()
-
-			} else if inputValue == "help" {
-				m.text = `Available Commands:
-===================
-
-Navigation:
-  pwd        - Show current directory
-  ls         - List files and directories
-  cd <dir>   - Change directory (use '..' to go up)
-  cat <file> - View file contents in pager mode
-
-System Info:
-  whoami     - Show current user
-  date       - Show current date
-  version    - Show CLI version and build info
-  neofetch   - Display system information with ASCII art
-
-Portfolio:
-  skills     - Show my technical skills
-  contact    - Show contact information
-  qr <text>  - Generate QR code for text
-  coinflip   - Flip a coin (heads or tails)
-Utilities:
-  echo <text> - Echo back the provided text
-  joke        - Get a random dad joke
-  wiki <term> - Search Wikipedia for a term
-  clear       - Clear the terminal output
-  help        - Show this help message
-  exit        - Exit the CLI
-
-Navigation Tips:
-  - Use up/down arrows to browse command history
-  - Use Page Up/Page Down to navigate viewport
-  - Press 'q' or 'esc' to exit file viewer
-  - Use 'cd ..' to go to parent directory
-
-Examples:
-  cd Portfolio   - Navigate to Portfolio directory
-  cat README.md  - View README file
-  wiki golang    - Search Wikipedia for 'golang'
-  echo Hello!    - Display 'Hello!'`
-				m.input.Reset()
-			} else if inputValue == "clear" {
-				m.clihistory = []string{headerView()} // Reset history but keep header
-				m.input.Reset()
-				// Don't append 'clear' to clihistory
-				break
-			} else if len(inputValue) >= 4 && inputValue[:4] == "cat " {
-				filename := inputValue[4:]
-				// Check if trying to access a hidden file
-				if strings.HasPrefix(filename, ".") {
-					m.text = "Access denied: Hidden files are not accessible"
-					m.input.Reset()
-					break
-				}
-				content, err := os.ReadFile(m.directory + "/" + filename)
-				if err != nil {
-					m.text = fmt.Sprintf("Error reading file: %v", err)
+			// Expand !N and !! before anything else sees the input
+			if strings.HasPrefix(inputValue, "!") {
+				expanded, ok := m.expandHistory(inputValue)
+				if !ok {
+					m.print(inputValue + ": event not found")
 					m.input.Reset()
 					break
 				}
-				m.fileContent = string(content)
-				m.fileViewMode = true
-				// Initialize with proper size that will be updated by WindowSizeMsg
-				// Get current terminal size for file viewport
-				if m.ready {
-					headerHeight := lipgloss.Height(m.fileHeaderView())
-					footerHeight := lipgloss.Height(m.fileFooterView())
-					exitInstructionHeight := 1
-					verticalMarginHeight := headerHeight + footerHeight + exitInstructionHeight
-					m.fileViewport = viewport.New(m.viewport.Width, m.viewport.Height+2-verticalMarginHeight) // +2 to account for prompt height difference
-				} else {
-					m.fileViewport = viewport.New(80, 24) // Fallback dimensions
-				}
-				m.fileViewport.SetContent(m.fileContent)
-				m.fileViewport.YPosition = 0
-				m.input.Reset()
-			} else if inputValue == "joke" {
-				m.input.Reset()
-				client := &http.Client{}
-				req, err := http.NewRequest("GET", "https://icanhazdadjoke.com/", nil)
-				if err != nil {
-					m.text = fmt.Sprintf("Error creating request: %v", err)
-					m.input.Reset()
-					break
-				}
-				req.Header.Set("Accept", "application/json")
-				resp, err := client.Do(req)
-				if err != nil {
-					m.text = fmt.Sprintf("Error fetching joke: %v", err)
-				} else {
-					defer resp.Body.Close()
-
-					var jokeData struct {
-						ID     string `json:"id"`
-						Joke   string `json:"joke"`
-						Status int    `json:"status"`
-					}
-
-					if err := json.NewDecoder(resp.Body).Decode(&jokeData); err != nil {
-						m.text = fmt.Sprintf("Error parsing joke: %v", err)
-					} else {
-						m.text = jokeData.Joke
-					}
-				}
-				if err != nil {
-					m.text = fmt.Sprintf("Error fetching joke: %v", err)
-				}
-
-			} else if len(inputValue) >= 5 && inputValue[:5] == "wiki " {
-				m.input.Reset()
-				query := inputValue[5:] // Get everything after 'wiki '
-				if query == "" {
-					m.text = "Please provide a search term."
-				} else {
-					// Perform wiki search
-					m.text = "Searching Wikipedia for: " + query
-					search_result, err := gowiki.Summary(query, 5, -1, false, true)
-					if err != nil {
-						m.text = "Error fetching Wikipedia summary: " + err.Error()
-					} else {
-						m.text = "\n" + search_result
-					}
-				}
-			} else if inputValue == "pwd" {
-				m.text = "Current directory: " + m.directory
-				m.input.Reset()
-			} else if inputValue == "exit" {
-				return m, tea.Quit
-			} else if inputValue == "whoami" {
-				m.text = "Current user: guest"
-				m.input.Reset()
-			} else if inputValue == "date" {
-				m.text = "Current date: " + time.Now().Format("2006-01-02")
-				m.input.Reset()
-			} else if len(inputValue) >= 5 && inputValue[:5] == "echo " {
-				m.text = "Echoing: " + inputValue[5:]
-				m.input.Reset()
-			} else if inputValue == "neofetch" {
-				style := lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
-				neofetchStyle := style
-				m.text = neofetchStyle.Render(fmt.Sprintf(`
-				.88888888:.              guest@fred-cli
-			   88888888.88888.           -----------------
-			 .8888888888888888.         OS: Fred's Portfolio CLI
-			 888888888888888888         Kernel: Go Runtime
-			 88' _`+"`"+`88'_  `+"`"+`88888         Uptime: Running since startup
-			 88 88 88 88  88888         Shell: Go CLI v1.0
-			 88_88_::_88_:88888         Resolution: Terminal Based
-			 88:::,::,:::::8888         Terminal: Bubbles Tea
-			 88`+"`"+`:::::::::`+"`"+`8888          CPU: %s
-			.88  `+"`"+`::::`+"`"+`    8:88.        Memory: Efficient Go runtime
-		   8888            `+"`"+`8:888.      Language: Go
-		 .8888`+"`"+`             `+"`"+`888888.    Platform: %s
-		.8888:..  .::.  ...:`+"`"+`8888888:.   
-	   .8888.`+"`"+`     :`+"`"+`     `+"`"+`::`+"`"+`88:88888  
-	  .8888        `+"`"+`         `+"`"+`.888:8888. 
-	 888:8         .           888:88888 
-   .888:88        .:           88:88888:
-   8888888.       ::           88:888888 
-   `+"`"+`.::.888.      ::          .88888888  
-  .::::::.888.    ::         :::`+"`"+`8888`+"`"+`.  :
- ::::::::::.888   `+"`"+`         .::::::::::::
- ::::::::::::.8    `+"`"+`      .:8::::::::::::.
-.::::::::::::::.        .:888:::::::::::::
-:::::::::::::::88:.__..:88888::::::::::::`+"`"+`
- `+"`"+``+"`"+`.:::::::::::88888888888.88:::::::::  
-	   `+"`"+``+"`"+`:::_:`+"`"+` -- `+"`"+``+"`"+` -`+"`"+`-`+"`"+` `+"`"+``+"`"+`:_::::      
-`, runtime.GOARCH, runtime.GOOS))
-				m.input.Reset()
-			} else if inputValue == "version" {
-				m.text += " verson 1.0.0, built with Go " + runtime.Version() + " on " + runtime.GOOS + "/" + runtime.GOARCH
-				m.input.Reset()
-			} else if inputValue == "skills" {
-				m.text = `
-Skills:
-================
-• Go Programming
-• Terminal/CLI Development
-• Web Development
-• Game Development
-• GDscript (Godot programming language)
-• LLMS (Large Language Models)
-`
-				m.input.Reset()
-			} else if inputValue == "contact" {
-				m.text = "You can find me on:\n- GitHub:   github.com/ItsHotdogFred\n- Itch.io:  itshotdogfred.itch.io\n- Email:    cli@itsfred.dev"
-				m.input.Reset()
-			} else if len(inputValue) >= 3 && inputValue[:3] == "qr " {
-				m.text = "Generating QR code for: " + inputValue[3:]
-				qrterminal.Generate(inputValue[3:], qrterminal.L, os.Stdout)
-				// Generate QR code to a string buffer instead of stdout
-				var qrBuffer strings.Builder
-				qrterminal.Generate(inputValue[3:], qrterminal.L, &qrBuffer)
-				m.text = "QR code for: " + inputValue[3:] + "\n\n" + qrBuffer.String()
-				m.input.Reset()
-			} else if inputValue == "coinflip" {
-				var num float64 = rand.Float64()
-				if num < 0.5 {
-					m.text += " Result: Heads"
-				} else {
-					m.text += " Result: Tails"
-				}
-				m.input.Reset()
-			} else if len(inputValue) >= 5 && inputValue[:5] == "yoda " {
-				text := inputValue[5:]
-				words := strings.Fields(text)
-				var yodaText string
-
-				if len(words) < 2 {
-					yodaText = text + ", mmm."
-				} else {
-					// Simple Yoda transformation: move some words around and add Yoda-isms
-					var result []string
-
-					// If sentence starts with "I am", change to "Am I"
-					if len(words) >= 2 && strings.ToLower(words[0]) == "i" && strings.ToLower(words[1]) == "am" {
-						result = append(result, strings.Title(words[1]), strings.ToLower(words[0]))
-						result = append(result, words[2:]...)
-					} else if len(words) >= 3 {
-						// Move last word or phrase to beginning
-						result = append(result, words[len(words)-1])
-						result = append(result, words[:len(words)-1]...)
-					} else {
-						result = words
-					}
-
-					// Add Yoda-isms
-					yodaisms := []string{", mmm.", ", yes.", ", hmm.", ", indeed."}
-					ending := yodaisms[rand.Intn(len(yodaisms))]
-
-					yodaText = strings.Join(result, " ") + ending
-				}
-				m.text = "Yoda says: " + yodaText
-				m.input.Reset()
-			} else {
-				m.text += " is not a valid command, try running help for commands"
-				m.input.Reset()
+				inputValue = expanded
 			}
-			// Only append to clihistory if not just cleared
-			if inputValue != "clear" {
-				m.clihistory = append(m.clihistory, m.text)
+			m.history = trimHistory(append(m.history, inputValue))
+			m.historyIndex = -1 // Reset history navigation on new entry
+			saveHistory(m.historyFile, m.history)
+			m.input.Reset()
+			// A trailing backslash continues the line in the compose editor
+			if line, ok := continuedLine(inputValue); ok {
+				cmds = append(cmds, m.startApp(newComposer(line, m.width, m.height, m.theme)))
+				break
 			}
+			cmds = append(cmds, m.run(inputValue))
 
 		// Autocomplete handling
 		case "tab":
-			if m.input.Value() == "" {
-				return m, nil // No input to autocomplete
-			}
-
-			m.autocompletelist = []string{} // Reset autocompletion list
-
-			entries, _ := os.ReadDir(m.directory)
-			m.fileautocomplete = []string{}
-			for _, entry := range entries {
-				if !strings.HasPrefix(entry.Name(), ".") {
-					m.fileautocomplete = append(m.fileautocomplete, entry.Name())
-				}
-			}
-
-			// Perform autocompletion logic here
-			// For example, you could suggest commands based on the current input
-			m.autocompletelist = append(m.commandautocomplete, m.fileautocomplete...)
-			input := m.input.Value()
-
-			// Split input into words to get the current word being typed
-			words := strings.Fields(input)
-			if len(words) == 0 {
-				return m, nil
-			}
-
-			currentWord := words[len(words)-1]
-			var bestMatch string
-
-			for _, option := range m.autocompletelist {
-				// Check if option starts with the current word (prefix match)
-				if strings.HasPrefix(strings.ToLower(option), strings.ToLower(currentWord)) {
-					if bestMatch == "" {
-						bestMatch = option
-					} else {
-						// If we have multiple matches, prefer files over commands
-						// and among files, prefer the shortest complete filename
-						isCurrentFile := false
-						isBestFile := false
-
-						// Check if current option is a file (contains extension)
-						for _, fileOption := range m.fileautocomplete {
-							if option == fileOption {
-								isCurrentFile = true
-								break
-							}
-						}
-
-						// Check if best match is a file
-						for _, fileOption := range m.fileautocomplete {
-							if bestMatch == fileOption {
-								isBestFile = true
-								break
-							}
-						}
-
-						// Prefer files over commands, and shorter files over longer files
-						if isCurrentFile && !isBestFile {
-							bestMatch = option
-						} else if isCurrentFile && isBestFile && len(option) < len(bestMatch) {
-							bestMatch = option
-						} else if !isCurrentFile && !isBestFile && len(option) < len(bestMatch) {
-							bestMatch = option
-						}
-					}
-				}
-			}
-
-			// If we found a match, replace only the current word
-			if bestMatch != "" {
-				if len(words) == 1 {
-					// Only one word, replace entirely
-					m.input.SetValue(bestMatch)
-				} else {
-					// Multiple words, replace only the last word
-					words[len(words)-1] = bestMatch
-					m.input.SetValue(strings.Join(words, " "))
-				}
-				// Position cursor at the end of the input
-				m.input.CursorEnd()
-			}
+			m.completeInput(false)
+			return m, nil
 
+		case "shift+tab":
+			m.completeInput(true)
 			return m, nil
 
 		case "up", "ctrl+p":
@@ -584,6 +506,12 @@ Skills:
 			}
 			return m, nil
 
+		case "ctrl+t":
+			// Toggle between following new output and staying put
+			m.pinned = !m.pinned
+			m.followOutput()
+			return m, nil
+
 		case "pageup":
 			// Page up for viewport
 			m.viewport.LineUp(m.viewport.Height / 2)
@@ -596,33 +524,23 @@ Skills:
 		}
 
 	case tea.WindowSizeMsg:
-		// The prompt line acts as the footer for the main view.
-		// We account for the prompt line itself plus a newline.
-		promptHeight := 2
-		verticalMarginHeight := promptHeight
+		m.resizeViewport(msg)
 
-		if !m.ready {
-			m.viewport = viewport.New(msg.Width, msg.Height-verticalMarginHeight)
-			m.viewport.YPosition = 0 // Viewport starts at the top
-			m.ready = true
-		} else {
-			m.viewport.Width = msg.Width
-			m.viewport.Height = msg.Height - verticalMarginHeight
+	case tea.MouseMsg:
+		content := m.scrollSearch.highlight(m.scrollback(), m.theme)
+		if link, ok := clickedLink(m.viewport, content, 0, msg); ok {
+			m.print("Copied " + link + " to your clipboard.")
+			cmds = append(cmds, copyToClipboard(m.out, link))
 		}
 	}
 
 	// This block now correctly handles setting the viewport content
 	// after any command is run or the window is resized.
-	var contentBuilder strings.Builder
-	for i := 0; i < len(m.clihistory); i++ {
-		contentBuilder.WriteString(m.clihistory[i])
-		contentBuilder.WriteString("\n")
-	}
-	m.viewport.SetContent(contentBuilder.String())
+	m.refreshViewport()
 
-	// After an enter press, scroll to the bottom of the viewport
+	// After an enter press, scroll to the new output
 	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "enter" {
-		m.viewport.GotoBottom()
+		m.followOutput()
 	}
 
 	// Handle input and viewport updates
@@ -645,7 +563,32 @@ Skills:
 	return m, tea.Batch(cmds...)
 }
 
-func headerView() string {
+// run executes a command line and adds its output to the scrollback.
+func (m *model) run(inputValue string) tea.Cmd {
+	m.commandsRun++
+	output, cmd := m.execute(inputValue)
+	m.show(inputValue, output)
+	return cmd
+}
+
+// show adds a command line and its output to the scrollback, or opens the
+// output in the pager if it won't fit on the screen.
+func (m *model) show(inputValue, output string) {
+	m.text = output
+	// Output taller than the screen is easier to read in the pager, like less
+	if m.ready && !m.fileViewMode && m.app == nil && output != "" &&
+		lipgloss.Height(m.render(outputEntry(inputValue, output))) > m.viewport.Height {
+		m.openPager(inputValue, output)
+		output = ""
+	}
+	// Only append to clihistory if not just cleared
+	if inputValue != "clear" {
+		m.clihistory = append(m.clihistory, outputEntry(inputValue, output))
+	}
+	m.announceAchievements()
+}
+
+func headerView(t *theme) string {
 	header := `
 ███████╗██████╗ ███████╗██████╗      ██████╗██╗     ██╗
 ██╔════╝██╔══██╗██╔════╝██╔══██╗    ██╔════╝██║     ██║
@@ -654,42 +597,124 @@ func headerView() string {
 ██║     ██║  ██║███████╗██████╔╝    ╚██████╗███████╗██║
 ╚═╝     ╚═╝  ╚═╝╚══════╝╚═════╝      ╚═════╝╚══════╝╚═╝
         `
-	title := headerstyle.Render(header)
+	title := t.header.Render(header)
 	return title
 }
 
 func (m model) View() string {
+	footer := m.idleFooter()
+	if footer == "" {
+		footer = m.alarmFooter()
+	}
+	return m.hyperlink(m.withFooter(m.view(), footer))
+}
+
+func (m model) view() string {
+	if m.app != nil {
+		return m.app.View()
+	}
 	if m.fileViewMode {
 		if !m.ready {
 			return "Initializing file viewer..."
 		}
-		return fmt.Sprintf("%s\n%s\n%s\n(Press 'q' or 'esc' to exit)", m.fileHeaderView(), m.fileViewport.View(), m.fileFooterView())
+		hint := "(Press 'q' or 'esc' to exit, '/' to search)"
+		if m.pagerSearch.active() {
+			hint = m.searchStatus(m.pagerSearch)
+		} else if m.pagerNotice != "" {
+			hint = m.pagerNotice
+		}
+		return fmt.Sprintf("%s\n%s\n%s\n%s", m.fileHeaderView(), m.fileViewport.View(), m.fileFooterView(), hint)
 	}
 	if !m.ready {
 		return "Initializing terminal size..."
 	}
 	// Add lipgloss color to "guest@fred"
-	promptStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
-	prompt := promptStyle.Render("guest@fred:")
+	prompt := m.theme.prompt.Render("guest@fred:")
 
 	// Construct the prompt line which now acts as our footer
-	promptLine := prompt + (func() string {
-		displayDir := m.directory
-		if displayDir == "." || displayDir == "" {
-			return "~"
-		} else if strings.HasPrefix(displayDir, "./") {
-			return "~" + displayDir[1:]
+	promptLine := m.timerPrompt() + prompt + m.displayDirectory() + "$" + m.input.View()
+	if indicator := m.scrollIndicator(); indicator != "" {
+		if gap := m.viewport.Width - lipgloss.Width(promptLine) - lipgloss.Width(indicator); gap > 0 {
+			promptLine += strings.Repeat(" ", gap) + indicator
 		}
-		return displayDir
-	}()) + "$" + m.input.View()
+	}
+	if m.scrollSearch.active() {
+		promptLine = m.searchStatus(m.scrollSearch)
+	}
 
 	// Assemble the final view correctly. The header is now inside the viewport.
+	if m.completion.active() {
+		// Make room for the completion menu by hiding the top of the viewport
+		menu := m.completion.view(m.viewport.Width, m.theme)
+		menuHeight := lipgloss.Height(menu)
+		vp := m.viewport
+		vp.Height = max(0, vp.Height-menuHeight)
+		vp.SetYOffset(vp.YOffset + menuHeight)
+		return fmt.Sprintf("%s\n%s\n%s",
+			vp.View(),
+			promptLine,
+			menu,
+		)
+	}
 	return fmt.Sprintf("%s\n%s",
 		m.viewport.View(),
 		promptLine,
 	)
 }
 
+// resizeViewport fits the main viewport to the terminal.
+func (m *model) resizeViewport(msg tea.WindowSizeMsg) {
+	m.width, m.height = msg.Width, msg.Height
+
+	// The prompt line acts as the footer for the main view.
+	// We account for the prompt line itself plus a newline.
+	promptHeight := 2
+	verticalMarginHeight := promptHeight
+
+	if !m.ready {
+		m.viewport = viewport.New(msg.Width, msg.Height-verticalMarginHeight)
+		m.viewport.YPosition = 0 // Viewport starts at the top
+		m.ready = true
+	} else {
+		m.viewport.Width = msg.Width
+		m.viewport.Height = msg.Height - verticalMarginHeight
+	}
+}
+
+// refreshViewport rebuilds the viewport content from clihistory.
+func (m *model) refreshViewport() {
+	m.viewport.SetContent(m.scrollSearch.highlight(m.scrollback(), m.theme))
+}
+
+// followOutput scrolls to the newest output, unless the visitor has pinned
+// the scrollback to read something further up.
+func (m *model) followOutput() {
+	if !m.pinned {
+		m.viewport.GotoBottom()
+	}
+}
+
+// scrollIndicator shows how far through the scrollback the view is, and
+// whether it's pinned, or "" when everything fits on the screen.
+func (m model) scrollIndicator() string {
+	if m.viewport.TotalLineCount() <= m.viewport.Height && !m.pinned {
+		return ""
+	}
+	text := fmt.Sprintf("%3.f%%", m.viewport.ScrollPercent()*100)
+	if m.pinned {
+		text = m.tr("scroll pinned") + " · " + text
+	}
+	return m.theme.muted.Render(text)
+}
+
+// displayDirectory shows the current directory relative to the portfolio root as ~.
+func (m model) displayDirectory() string {
+	if m.directory == "." {
+		return "~"
+	}
+	return "~/" + m.directory
+}
+
 // validatePath checks if the given path exists and is a directory.
 // It returns true if the path is valid, false otherwise.
 func validatePath(path string) bool {
@@ -700,12 +725,42 @@ func validatePath(path string) bool {
 	return info.IsDir()
 }
 
+// openPager shows content in the full-screen file viewer, with title in
+// its header.
+func (m *model) openPager(title, content string) {
+	m.pagerTitle = title
+	m.fileContent = content
+	m.fileViewMode = true
+	m.pagerSearch = search{}
+	// Initialize with proper size that will be updated by WindowSizeMsg
+	// Get current terminal size for file viewport
+	if m.ready {
+		headerHeight := lipgloss.Height(m.fileHeaderView())
+		footerHeight := lipgloss.Height(m.fileFooterView())
+		exitInstructionHeight := 1
+		verticalMarginHeight := headerHeight + footerHeight + exitInstructionHeight
+		m.fileViewport = viewport.New(m.viewport.Width, m.viewport.Height+2-verticalMarginHeight) // +2 to account for prompt height difference
+	} else {
+		m.fileViewport = viewport.New(80, 24) // Fallback dimensions
+	}
+	m.fileViewport.SetContent(m.fileContent)
+	m.fileViewport.YPosition = 0
+}
+
 // File view header/footer for pager mode
 func (m model) fileHeaderView() string {
 	b := lipgloss.RoundedBorder()
 	b.Right = "├"
 	titleStyle := lipgloss.NewStyle().BorderStyle(b).Padding(0, 1)
-	title := titleStyle.Render("File Viewer")
+	text := m.pagerTitle
+	if text == "" {
+		text = "File Viewer"
+	}
+	// Leave room for the border and a bit of the line
+	if limit := m.fileViewport.Width - 10; limit > 0 && lipgloss.Width(text) > limit {
+		text = string([]rune(text)[:limit-1]) + "…"
+	}
+	title := titleStyle.Render(text)
 	line := strings.Repeat("─", max(0, m.fileViewport.Width-lipgloss.Width(title)))
 	return lipgloss.JoinHorizontal(lipgloss.Center, title, line)
 }
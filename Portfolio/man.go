@@ -0,0 +1,582 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// manPage documents a command. help lists every page's usage and summary by
+// category, and man shows the whole page. Pages are kept apart from the
+// commands table, which help itself is part of.
+type manPage struct {
+	name        string
+	category    string // the help section it's listed in
+	usage       string
+	summary     string // one line for help
+	description string
+	options     [][2]string // flag or argument, then what it does
+	examples    [][2]string // command line, then what it does
+	hidden      bool        // left out of help, like operator commands
+}
+
+// helpCategories are the sections of help, in order.
+var helpCategories = []string{"navigation", "system", "portfolio", "utilities", "plugins"}
+
+// manPages are in the order help lists them.
+var manPages = []manPage{
+	{
+		name: "pwd", category: "navigation", usage: "pwd",
+		summary:     "Show current directory",
+		description: "Prints the directory you're in, relative to the root of the portfolio.",
+	},
+	{
+		name: "ls", category: "navigation", usage: "ls",
+		summary:     "List files and directories",
+		description: "Lists what's in the current directory, with folders and files in their own colours. Files you've created with > during this session are listed too. When piped, ls prints just the names, one per line.",
+		examples: [][2]string{
+			{"ls", "See what's here"},
+			{"ls | grep md", "List only markdown files"},
+		},
+	},
+	{
+		name: "tree", category: "navigation", usage: "tree [depth]",
+		summary:     "Show the directory tree (default depth 2)",
+		description: "Draws the directories and files below the current directory as a tree.",
+		options: [][2]string{
+			{"depth", "How many levels to show, 2 if left out"},
+		},
+		examples: [][2]string{
+			{"tree", "Show two levels"},
+			{"tree 4", "Show four levels"},
+		},
+	},
+	{
+		name: "cd", category: "navigation", usage: "cd [dir]",
+		summary:     "Change directory (use '..' to go up)",
+		description: "Moves into a directory below the current one, or up a level with '..'. On its own it goes back to ~. Hidden directories can't be entered.",
+		examples: [][2]string{
+			{"cd Projects", "Go into the Projects directory"},
+			{"cd ..", "Go back up"},
+			{"cd", "Go back to ~"},
+		},
+	},
+	{
+		name: "bookmark", category: "navigation", usage: "bookmark [add <name> | list | rm <name>]",
+		summary:     "Bookmark directories to jump back to",
+		description: "Saves the current directory under a name, so go can take you back to it from anywhere. Without arguments it lists your bookmarks. They last until you disconnect.",
+		examples: [][2]string{
+			{"bookmark add games", "Bookmark this directory as games"},
+			{"bookmark list", "List your bookmarks"},
+			{"bookmark rm games", "Remove the games bookmark"},
+		},
+	},
+	{
+		name: "go", category: "navigation", usage: "go <bookmark>",
+		summary:     "Jump to a bookmarked directory",
+		description: "Changes to the directory saved with bookmark add. Tab completes bookmark names.",
+		examples: [][2]string{
+			{"go games", "Jump to the directory bookmarked as games"},
+		},
+	},
+	{
+		name: "cat", category: "navigation", usage: "cat <file>",
+		summary:     "View file contents in pager mode",
+		description: "Opens a file in the pager. Scroll with the arrow keys or Page Up and Page Down, search with /, and press q or esc to close it. Without a file, cat passes piped input through unchanged.",
+		examples: [][2]string{
+			{"cat README.md", "Read the README"},
+			{"cat About/bio.txt | grep Go", "Find the lines of the bio that mention Go"},
+		},
+	},
+	{
+		name: "head", category: "navigation", usage: "head [-n count] <file>",
+		summary:     "Show the first n lines of a file (default 10)",
+		description: "Prints the start of a file, or of piped input when no file is given. The count can also come after the file, as in head README.md 5.",
+		options: [][2]string{
+			{"-n count", "How many lines to print, 10 if left out"},
+		},
+		examples: [][2]string{
+			{"head -n 5 README.md", "Show the first five lines of the README"},
+			{"history | head", "Show the oldest commands in your history"},
+		},
+	},
+	{
+		name: "tail", category: "navigation", usage: "tail [-n count] <file>",
+		summary:     "Show the last n lines of a file (default 10)",
+		description: "Prints the end of a file, or of piped input when no file is given. The count can also come after the file, as in tail README.md 3.",
+		options: [][2]string{
+			{"-n count", "How many lines to print, 10 if left out"},
+		},
+		examples: [][2]string{
+			{"tail -n 3 README.md", "Show the last three lines of the README"},
+		},
+	},
+	{
+		name: "wc", category: "navigation", usage: "wc <file>",
+		summary:     "Count lines, words and characters in a file",
+		description: "Counts the lines, words and characters of a file, or of piped input when no file is given.",
+		examples: [][2]string{
+			{"wc About/bio.txt", "See how long the bio is"},
+			{"ls | wc", "Count the entries in this directory"},
+		},
+	},
+	{
+		name: "img", category: "navigation", usage: "img <file>",
+		summary:     "View a PNG, JPEG or GIF image in the terminal",
+		description: "Draws an image with coloured half blocks, scaled to fit the terminal. Images taller than the screen open in the pager. It needs a terminal with true colour support to look right.",
+	},
+	{
+		name: "grep", category: "navigation", usage: "grep [-i] <pattern> [path]",
+		summary:     "Search file contents recursively",
+		description: "Searches every file below path, or the current directory, for lines matching a regular expression, and shows each match with its file and line number. When it's given piped input, grep filters that instead.",
+		options: [][2]string{
+			{"-i", "Ignore case"},
+			{"pattern", "A regular expression to look for"},
+			{"path", "A file or directory to search instead of the current directory"},
+		},
+		examples: [][2]string{
+			{"grep -i godot", "Find every mention of 'godot'"},
+			{"grep func Projects", "Search only the Projects directory"},
+			{"ls | grep md", "Filter the output of another command"},
+		},
+	},
+	{
+		name: "whoami", category: "system", usage: "whoami",
+		summary:     "Show current user",
+		description: "Prints who you're logged in as. Everyone is a guest here.",
+	},
+	{
+		name: "date", category: "system", usage: "date [zone] [+format]",
+		summary:     "Show the date and time, anywhere in the world",
+		description: "Prints the time on the server, and your own time too if your SSH client sends TZ (ssh -o SendEnv=TZ). Give a time zone to see the time there, or a format to print just the parts you want.",
+		options: [][2]string{
+			{"zone", "A time zone like Australia/Sydney or UTC"},
+			{"+format", "A format like the Unix date command's: %Y %m %d %H %M %S %a %b %Z, %F for the date, %T for the time"},
+		},
+		examples: [][2]string{
+			{"date Australia/Sydney", "The time in Sydney"},
+			{"date +%H:%M", "Just the hour and minute"},
+			{"date UTC \"+%F %T\"", "The date and time in UTC"},
+		},
+	},
+	{
+		name: "version", category: "system", usage: "version",
+		summary:     "Show CLI version and build info",
+		description: "Prints the portfolio's version and the Go version and platform it was built for.",
+	},
+	{
+		name: "neofetch", category: "system", usage: "neofetch",
+		summary:     "Display system information with ASCII art",
+		description: "Shows a summary of the system next to some ASCII art, or the avatar from the server's config, including your best typetest score this session.",
+	},
+	{
+		name: "uptime", category: "system", usage: "uptime",
+		summary:     "Show how long the server and your session have been up",
+		description: "Shows how long the server has been running, how long you've been connected and how many commands you've run, and how many visitors are online right now.",
+	},
+	{
+		name: "top", category: "system", usage: "top",
+		summary:     "Watch the server's live runtime stats",
+		description: "Opens a monitor of the running server, refreshed every second: open sessions, goroutines, heap use and garbage collection pauses, with graphs of the last minute. Press q to close it.",
+	},
+	{
+		name: "skills", category: "portfolio", usage: "skills",
+		summary:     "Show my technical skills",
+		description: "Lists the languages and areas I work with.",
+	},
+	{
+		name: "resume", category: "portfolio", usage: "resume [download]",
+		summary:     "Read my resume ('resume download' for a PDF link)",
+		description: "Opens my resume in the pager, laid out for your terminal.",
+		options: [][2]string{
+			{"download", "Print a link to the PDF, with a QR code to scan with your phone"},
+		},
+		examples: [][2]string{
+			{"resume", "Read the resume"},
+			{"resume download", "Get the PDF"},
+		},
+	},
+	{
+		name: "download", category: "portfolio", usage: "download <file>",
+		summary:     "Get a one-time link to download a file or directory",
+		description: "Makes a link to download a file over HTTP, shown with a QR code to scan with your phone. Directories are downloaded as a zip archive. Each link works once, for ten minutes. Links are only available when the server was started with --download-addr.",
+		examples: [][2]string{
+			{"download About/resume.json", "Download the resume data"},
+			{"download Projects", "Download every project as a zip"},
+		},
+	},
+	{
+		name: "contact", category: "portfolio", usage: "contact [send [message]]",
+		summary:     "Show contact information or send me a message",
+		description: "Lists where you can find me and how to get in touch. contact send opens a form for your email address and a message, which is emailed to me so I can reply. Tab switches between the fields and ctrl+d sends. A few messages can be sent an hour.",
+		examples: [][2]string{
+			{"contact", "Show my contact details"},
+			{"contact send", "Write me a message"},
+			{"contact send Loved the snake game!", "Start the message for you"},
+		},
+	},
+	{
+		name: "github", category: "portfolio", usage: "github [user]",
+		summary:     "Show GitHub repos, stars and recent activity",
+		description: "Fetches a GitHub profile and shows its most starred repositories and a graph of public activity over the last few weeks. Profiles are cached for a few minutes.",
+		options: [][2]string{
+			{"user", "The GitHub user to look up, me if left out"},
+		},
+		examples: [][2]string{
+			{"github", "See my profile"},
+			{"github torvalds", "See someone else's"},
+		},
+	},
+	{
+		name: "qr", category: "portfolio", usage: "qr [--level L|M|Q|H] [--compact] <text>",
+		summary:     "Generate QR code for text",
+		description: "Draws a QR code for some text or a link, ready to scan with a phone. Piped input works too. Codes too wide for the terminal are drawn compact automatically.",
+		options: [][2]string{
+			{"--level L|M|Q|H", "Error correction, from L (the smallest code) to H (still scans when partly covered). L if left out"},
+			{"--compact", "Draw with half blocks, a quarter of the size"},
+		},
+		examples: [][2]string{
+			{"qr https://itsfred.dev", "Make a QR code for a link"},
+			{"qr --compact --level H https://itsfred.dev", "A small code that's hard to damage"},
+		},
+	},
+	{
+		name: "coinflip", category: "portfolio", usage: "coinflip",
+		summary:     "Flip a coin (heads or tails)",
+		description: "Flips a coin.",
+	},
+	{
+		name: "play", category: "portfolio", usage: "play [snake]",
+		summary:     "Play snake (q to return to the shell)",
+		description: "Starts a game of snake. Steer with the arrow keys, WASD or HJKL, pause with p or space, restart with r and press q or esc to return to the shell. Your best score is kept for the session.",
+	},
+	{
+		name: "typetest", category: "portfolio", usage: "typetest",
+		summary:     "Test your typing speed",
+		description: "Shows a paragraph to type as fast and accurately as you can, then your words per minute and accuracy. Press r to try again, or q, esc or enter to return to the shell.",
+	},
+	{
+		name: "motd", category: "portfolio", usage: "motd",
+		summary:     "Show the latest announcement",
+		description: "Shows the message of the day, the announcement under the banner when you connect, like news of a project that was just added.",
+	},
+	{
+		name: "quiz", category: "portfolio", usage: "quiz",
+		summary:     "Answer a short multiple-choice trivia quiz",
+		description: "Asks five random questions about Go, terminals, games and computing. Pick an answer with the arrow keys and enter, or press its number. At the end you see your score and which questions you got right; press r to play again, or q to return to the shell. Your best score is kept for the session.",
+	},
+	{
+		name: "matrix", category: "portfolio", usage: "matrix",
+		summary:     "Watch green glyphs fall down the screen",
+		description: "Fills the terminal with the digital rain from The Matrix. It keeps going until you press any key, and adapts if you resize the window.",
+	},
+	{
+		name: "fireworks", category: "portfolio", usage: "fireworks",
+		summary:     "Set off a fireworks show in your terminal",
+		description: "Launches rockets that burst into sparks in the colours of your theme. Press any key to return to the shell.",
+	},
+	{
+		name: "achievements", category: "portfolio", usage: "achievements",
+		summary:     "See the badges you've unlocked",
+		description: "Lists every achievement and which ones you've unlocked, like finding an easter egg, reading the whole resume or running every command. You're told as soon as you unlock one. If you connect with an SSH key, your achievements are kept for your next visit.",
+	},
+	{
+		name: "chat", category: "portfolio", usage: "chat",
+		summary:     "Talk to everyone else connected right now",
+		description: "Joins a chat room shared by everyone visiting right now, over SSH or in the browser. Pick a nickname, type messages and press enter to send them, and press esc to leave.",
+	},
+	{
+		name: "compose", category: "utilities", usage: "compose [command]",
+		summary:     "Write text with line breaks for a command",
+		description: "Opens an editor for text that spans several lines, which the prompt can't take. Press ctrl+d when you're done and the text is piped into the command, or printed if there isn't one; esc cancels. Quote a command with a redirection so it isn't applied to compose itself. Ending any command line with a backslash opens the editor for it too.",
+		examples: [][2]string{
+			{"compose cowsay", "Have the cow say a few lines"},
+			{"compose \"echo > notes.txt\"", "Write a file of several lines"},
+			{"contact send \\", "Write me a longer message"},
+		},
+	},
+	{
+		name: "echo", category: "utilities", usage: "echo [text]",
+		summary:     "Echo back the provided text",
+		description: "Prints its arguments, or piped text if there are none. Handy for writing files with > and >>.",
+		examples: [][2]string{
+			{"echo Hello!", "Display 'Hello!'"},
+			{"echo remember the milk > todo.txt", "Write a file for this session"},
+		},
+	},
+	{
+		name: "joke", category: "utilities", usage: "joke [category]",
+		summary:     "Tell a random joke",
+		description: "Fetches a random joke from JokeAPI, leaving out anything not safe for work. If JokeAPI can't be reached, one comes from a list built into the portfolio instead. Which it was is shown under the joke.",
+		options: [][2]string{
+			{"category", "programming, misc, pun, spooky or christmas. Any category if left out"},
+		},
+		examples: [][2]string{
+			{"joke", "Tell any joke"},
+			{"joke programming", "Tell a programming joke"},
+		},
+	},
+	{
+		name: "wiki", category: "utilities", usage: "wiki <term>",
+		summary:     "Search Wikipedia for a term",
+		description: "Prints a short summary of the Wikipedia article for a term.",
+		examples: [][2]string{
+			{"wiki golang", "Read about Go"},
+			{"wiki golang > golang.txt", "Save the summary to a file"},
+		},
+	},
+	{
+		name: "weather", category: "utilities", usage: "weather <city>",
+		summary:     "Show the current weather for a city",
+		description: "Looks up a city and shows its current temperature, conditions and wind, from open-meteo.com.",
+		examples: [][2]string{
+			{"weather London", "See the weather in London"},
+			{"weather New York", "City names can have spaces"},
+		},
+	},
+	{
+		name: "price", category: "utilities", usage: "price <symbol>",
+		summary:     "Show a stock or crypto price with a 24h sparkline",
+		description: "Looks up the latest price of a stock, index or cryptocurrency on Yahoo Finance, with the change since the last close and a sparkline of the last day. Common coins like btc and eth are shown in US dollars. Prices are cached for a minute.",
+		options: [][2]string{
+			{"symbol", "A ticker like AAPL or ^GSPC, or a coin like btc"},
+		},
+		examples: [][2]string{
+			{"price aapl", "Apple's share price"},
+			{"price btc", "The price of Bitcoin"},
+		},
+	},
+	{
+		name: "timer", category: "utilities", usage: "timer [minutes | stop]",
+		summary:     "Count down from a number of minutes",
+		description: "Starts a countdown, shown in big digits. Give a number of minutes, or a duration like 90s or 1h30m. Press b to keep it running in the background while you use other commands: the time left stays in front of the prompt, and the terminal bell rings when it's up. Run timer again to bring the big digits back, or timer stop to cancel it. Only one timer runs at a time.",
+		examples: [][2]string{
+			{"timer 5", "Set a five minute timer"},
+			{"timer 90s", "Set a timer for a minute and a half"},
+		},
+	},
+	{
+		name: "pomodoro", category: "utilities", usage: "pomodoro [stop]",
+		summary:     "Focus for 25 minutes, then take a 5 minute break",
+		description: "Starts a pomodoro: a 25 minute focus timer, followed straight away by a 5 minute break. It works like timer, so it can run in the background with the time left in the prompt, and pomodoro stop cancels it.",
+	},
+	{
+		name: "copy", category: "utilities", usage: "copy <text|file>",
+		summary:     "Copy text or a file to your clipboard",
+		description: "Puts text, a file's contents or piped input on your own computer's clipboard, even over SSH, using the OSC 52 escape sequence. Most modern terminals support it; if yours doesn't, short text is shown so you can copy it by hand. email, github and itch copy my contact details.",
+		options: [][2]string{
+			{"text|file", "A file to copy, or else the text itself"},
+		},
+		examples: [][2]string{
+			{"copy email", "Copy my email address"},
+			{"copy About/bio.txt", "Copy a whole file"},
+			{"contact | copy", "Copy another command's output"},
+		},
+	},
+	{
+		name: "yoda", category: "utilities", usage: "yoda [--seed n] <text>",
+		summary:     "Say it like Yoda",
+		description: "Rearranges each sentence the way Yoda would say it, putting the subject and verb last. Contractions like I'm and don't are understood, and sentences it can't make sense of are left alone. Yoda sometimes adds a noise of his own at the end. Piped input works too.",
+		options: [][2]string{
+			{"--seed n", "Pick the same ending every time for the same n, so the output can be reproduced"},
+		},
+		examples: [][2]string{
+			{"yoda I am learning Go", "Learning Go, I am."},
+			{"yoda --seed 1 I don't like sand", "Like sand, I do not. Hmm."},
+			{"echo hello there | yoda", "Translate another command's output"},
+		},
+	},
+	{
+		name: "fortune", category: "utilities", usage: "fortune",
+		summary:     "Print a random quote",
+		description: "Prints a random quote or saying, like the classic Unix fortune. They're kept in Extra/fortunes.txt.",
+		examples: [][2]string{
+			{"fortune | cowsay", "Have a cow say it"},
+		},
+	},
+	{
+		name: "cowsay", category: "utilities", usage: "cowsay <text>",
+		summary:     "Have a cow say something",
+		description: "Draws a cow with a speech bubble, wrapped to fit your terminal. Without text, the cow says whatever is piped in.",
+		examples: [][2]string{
+			{"cowsay moo", "Moo"},
+			{"fortune | cowsay", "A cow with a fortune"},
+		},
+	},
+	{
+		name: "history", category: "utilities", usage: "history",
+		summary:     "Show command history (re-run with !N)",
+		description: "Lists the commands you've run, numbered. Run one again with !N, or the last one with !!. The up and down arrows step through the history too.",
+		examples: [][2]string{
+			{"!3", "Run the third command again"},
+			{"!!", "Run the last command again"},
+		},
+	},
+	{
+		name: "alias", category: "utilities", usage: "alias [name=\"cmd\"]",
+		summary:     "List aliases or add one for this session",
+		description: "Without arguments, lists every alias. With a name, shows what it runs. With name=\"command\", adds an alias for this session. An alias can run a pipeline but not redirect output, and can't replace a built-in command.",
+		examples: [][2]string{
+			{"alias", "List aliases"},
+			{"alias md=\"ls | grep md\"", "Add an alias"},
+		},
+	},
+	{
+		name: "unalias", category: "utilities", usage: "unalias <name>",
+		summary:     "Remove an alias",
+		description: "Removes an alias for the rest of the session.",
+	},
+	{
+		name: "demo", category: "utilities", usage: "demo [file]",
+		summary:     "Watch a scripted tour of the portfolio",
+		description: "Types and runs a series of commands to show off the portfolio. Press any key to take over. A script has one command per line, lines starting with # are comments, and '@pause 3s' waits before the next line.",
+		options: [][2]string{
+			{"file", "A script to play instead of the built-in tour"},
+		},
+	},
+	{
+		name: "theme", category: "utilities", usage: "theme [name]",
+		summary:     "List colour themes or switch to one",
+		description: "Without arguments, lists the themes with a sample of their colours. With a name, switches to that theme for the rest of the session.",
+		options: [][2]string{
+			{"name", "dark, light, solarized or dracula"},
+			{"auto", "Pick dark or light to match your terminal"},
+		},
+	},
+	{
+		name: "lang", category: "utilities", usage: "lang [code]",
+		summary:     "List languages or switch to one",
+		description: "Without arguments, lists the languages the portfolio speaks. With a code, switches to that language for the rest of the session. The language starts out matching the LANG your SSH client sends.",
+		examples: [][2]string{
+			{"lang es", "Switch to Spanish"},
+		},
+	},
+	{
+		name: "clear", category: "utilities", usage: "clear",
+		summary:     "Clear the terminal output",
+		description: "Clears the scrollback, leaving just the banner.",
+	},
+	{
+		name: "man", category: "utilities", usage: "man <command>",
+		summary:     "Show the manual for a command",
+		description: "Opens the manual for a command in the pager. 'help <command>' does the same.",
+		examples: [][2]string{
+			{"man grep", "Read about grep"},
+		},
+	},
+	{
+		name: "help", category: "utilities", usage: "help [command]",
+		summary:     "Show this help message",
+		description: "Lists every command. With a command, shows its manual like man.",
+	},
+	{
+		name: "exit", category: "utilities", usage: "exit",
+		summary:     "Exit the CLI",
+		description: "Ends the session.",
+	},
+	{
+		name: "stats", usage: "stats", hidden: true,
+		summary:     "Show visitor analytics",
+		description: "Shows how many people have visited and what they ran. Operators connecting with an authorised SSH key see the report straight away, everyone else is asked for the stats password.",
+	},
+}
+
+// findManPage returns the manual for a command.
+func findManPage(name string) (manPage, bool) {
+	for _, p := range manPages {
+		if p.name == name {
+			return p, true
+		}
+	}
+	return manPage{}, false
+}
+
+// summary returns p's one-line description in the session's language.
+func (m model) summary(p manPage) string {
+	if s, ok := messages[m.lang]["summary "+p.name]; ok {
+		return s
+	}
+	return p.summary
+}
+
+// helpText lists the commands by category, generated from manPages.
+func (m model) helpText() string {
+	var b strings.Builder
+	title := m.tr("help title")
+	b.WriteString(title + "\n" + strings.Repeat("=", len([]rune(title))) + "\n")
+	for _, category := range helpCategories {
+		var pages []manPage
+		width := 0
+		for _, p := range manPages {
+			if p.category == category && !p.hidden {
+				pages = append(pages, p)
+				width = max(width, len(p.usage))
+			}
+		}
+		if len(pages) == 0 {
+			continue
+		}
+		b.WriteString("\n" + m.tr("category "+category) + "\n")
+		for _, p := range pages {
+			fmt.Fprintf(&b, "  %-*s - %s\n", width, p.usage, m.summary(p))
+		}
+	}
+	b.WriteString("\n" + m.tr("help tips"))
+	return b.String()
+}
+
+// render lays out a manual page in sections, like man.
+func (p manPage) render(t *theme, width int) string {
+	width = max(40, min(80, width-4))
+	body := t.style().Width(width).PaddingLeft(4)
+	section := func(title string) string { return "\n" + t.accent.Render(title) + "\n" }
+
+	var b strings.Builder
+	b.WriteString(section("NAME"))
+	b.WriteString(body.Render(p.name+" - "+p.summary) + "\n")
+	b.WriteString(section("SYNOPSIS"))
+	b.WriteString(body.Render(p.usage) + "\n")
+	b.WriteString(section("DESCRIPTION"))
+	b.WriteString(body.Render(p.description) + "\n")
+	if len(p.options) > 0 {
+		b.WriteString(section("OPTIONS"))
+		for _, o := range p.options {
+			b.WriteString(body.Render(t.file.Render(o[0])) + "\n")
+			b.WriteString(body.Copy().PaddingLeft(8).Render(o[1]) + "\n")
+		}
+	}
+	if len(p.examples) > 0 {
+		b.WriteString(section("EXAMPLES"))
+		for _, e := range p.examples {
+			b.WriteString(body.Render(t.prompt.Render("$ ")+e[0]) + "\n")
+			b.WriteString(body.Copy().PaddingLeft(8).Render(t.muted.Render(e[1])) + "\n")
+		}
+	}
+	return b.String()
+}
+
+func manCommand(m *model, in commandInput) (string, tea.Cmd) {
+	name := in.args
+	if name == "" {
+		return "What manual page do you want? Try 'man man', or 'help' for a list of commands.", nil
+	}
+	var prefix string
+	if value, ok := m.aliases[name]; ok {
+		prefix = fmt.Sprintf("%s is an alias for %q\n", name, value)
+		name, _ = splitCommand(value)
+	}
+	p, ok := findManPage(name)
+	if !ok {
+		if prefix != "" {
+			return strings.TrimSuffix(prefix, "\n"), nil
+		}
+		return "No manual entry for " + in.args, nil
+	}
+	page := prefix + p.render(m.theme, m.viewport.Width)
+	if !in.toTerminal {
+		return page, nil
+	}
+	m.openPager("man "+p.name, page)
+	return "", nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"math/rand"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const matrixInterval = 80 * time.Millisecond
+
+// matrixGlyphs are half-width katakana and digits, which are one column wide
+// like everything else on the canvas.
+var matrixGlyphs = []rune("ｦｱｳｴｵｶｷｹｺｻｼｽｾｿﾀﾂﾃﾅﾆﾇﾈﾊﾋﾎﾏﾐﾑﾒﾓﾔﾕﾗﾘﾜ0123456789Z:.=*+-<>")
+
+// The rain is green whatever the theme, it wouldn't be the matrix otherwise.
+var (
+	matrixHead  = lipgloss.Color("#d8ffd8")
+	matrixBody  = lipgloss.Color("#00ff41")
+	matrixFaded = lipgloss.Color("#008f11")
+)
+
+// matrixDrop is the falling trail in one column.
+type matrixDrop struct {
+	y      float64 // row of the head, negative while it's above the screen
+	speed  float64 // rows per frame
+	length int
+}
+
+// matrixRain is the falling green glyphs from The Matrix.
+type matrixRain struct {
+	width, height int
+	drops         []matrixDrop // one per column
+	glyphs        []rune       // what each cell shows when a trail passes over it
+}
+
+func matrixCommand(m *model, in commandInput) (string, tea.Cmd) {
+	return "", m.playAnimation(&matrixRain{}, matrixInterval)
+}
+
+func (r *matrixRain) resize(width, height int) {
+	r.width, r.height = width, height
+	r.glyphs = make([]rune, width*height)
+	for i := range r.glyphs {
+		r.glyphs[i] = matrixGlyphs[rand.Intn(len(matrixGlyphs))]
+	}
+	// Keep the drops that are still on screen so resizing doesn't restart the rain
+	drops := make([]matrixDrop, width)
+	copy(drops, r.drops)
+	for x := range drops {
+		if drops[x].speed == 0 || int(drops[x].y)-drops[x].length >= height {
+			drops[x] = r.newDrop()
+			// Start some drops part way down so the screen doesn't fill row by row
+			drops[x].y = float64(rand.Intn(height*2) - height)
+		}
+	}
+	r.drops = drops
+}
+
+func (r *matrixRain) newDrop() matrixDrop {
+	return matrixDrop{
+		y:      -float64(rand.Intn(r.height + 1)),
+		speed:  0.3 + rand.Float64()*0.7,
+		length: 4 + rand.Intn(max(1, r.height/2)),
+	}
+}
+
+func (r *matrixRain) step() bool {
+	for x := range r.drops {
+		d := &r.drops[x]
+		d.y += d.speed
+		if int(d.y)-d.length >= r.height {
+			*d = r.newDrop()
+		}
+	}
+	// A few glyphs change every frame
+	for i := 0; i < len(r.glyphs)/50+1; i++ {
+		r.glyphs[rand.Intn(len(r.glyphs))] = matrixGlyphs[rand.Intn(len(matrixGlyphs))]
+	}
+	return true
+}
+
+func (r *matrixRain) draw(c *canvas) {
+	for x, d := range r.drops {
+		head := int(d.y)
+		for i := 0; i < d.length; i++ {
+			y := head - i
+			if y < 0 || y >= r.height {
+				continue
+			}
+			color := matrixFaded
+			switch {
+			case i == 0:
+				color = matrixHead
+			case i < d.length/2:
+				color = matrixBody
+			}
+			c.set(x, y, r.glyphs[y*r.width+x], color)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/log"
+)
+
+// motdReloadInterval is how often the message of the day file is checked for
+// changes, so announcements reach the live server without a restart.
+const motdReloadInterval = 30 * time.Second
+
+// motd is the current message of the day, shown under the banner of every
+// session. It's empty when there is no message file.
+var motd struct {
+	sync.Mutex
+	text string
+}
+
+// motdPath returns the message of the day file location, which can be
+// overridden with the PORTFOLIO_MOTD environment variable.
+func motdPath() string {
+	if p := os.Getenv("PORTFOLIO_MOTD"); p != "" {
+		return p
+	}
+	return filepath.Join(dataDir(), "motd")
+}
+
+func currentMOTD() string {
+	motd.Lock()
+	defer motd.Unlock()
+	return motd.text
+}
+
+// loadMOTD reads the message file at path. Lines starting with # are
+// comments, so old announcements can be kept around switched off.
+func loadMOTD(path string) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		log.Error("Could not read message of the day", "path", path, "error", err)
+		return
+	}
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, strings.TrimRight(line, " \t\r"))
+		}
+	}
+	text := strings.TrimSpace(strings.Join(lines, "\n"))
+
+	motd.Lock()
+	changed := motd.text != text
+	motd.text = text
+	motd.Unlock()
+	if changed {
+		log.Info("Loaded message of the day", "path", path, "empty", text == "")
+	}
+}
+
+// watchMOTD loads the message file and reloads it whenever it changes.
+func watchMOTD(path string) {
+	loadMOTD(path)
+	var modified time.Time
+	if info, err := os.Stat(path); err == nil {
+		modified = info.ModTime()
+	}
+	go func() {
+		for range time.Tick(motdReloadInterval) {
+			var mod time.Time
+			if info, err := os.Stat(path); err == nil {
+				mod = info.ModTime()
+			}
+			// A deleted file has a zero time, which clears the message
+			if !mod.Equal(modified) {
+				modified = mod
+				loadMOTD(path)
+			}
+		}
+	}()
+}
+
+// motdView draws the message of the day in a box, or nothing if there isn't one.
+func motdView(t *theme, text string, width int) string {
+	if text == "" {
+		return ""
+	}
+	box := t.style().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.colors.accent).
+		Padding(0, 1)
+	if width > 0 {
+		// The border adds a column on each side
+		box = box.Width(min(70, width-2))
+	}
+	return box.Render("📣 " + text)
+}
+
+func motdCommand(m *model, in commandInput) (string, tea.Cmd) {
+	text := currentMOTD()
+	if text == "" {
+		return "No announcements right now.", nil
+	}
+	if !in.toTerminal {
+		return text, nil
+	}
+	return motdView(m.theme, text, m.viewport.Width), nil
+}
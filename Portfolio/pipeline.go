@@ -0,0 +1,167 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pipeline is a parsed command line: stages joined by | and an optional
+// redirection of the final output into a session file.
+type pipeline struct {
+	stages     []string // the text of each stage, quotes intact
+	redirect   string   // file name after > or >>
+	redirected bool     // true if the line contained an unquoted >
+	append     bool     // true for >>
+}
+
+// parsePipeline splits input into stages on | and finds a redirection,
+// which has to come last.
+func parsePipeline(input string) (pipeline, error) {
+	var p pipeline
+	tokens, err := lex(input)
+	if err != nil {
+		return p, err
+	}
+	stageStart := 0
+	endStage := func(end int) error {
+		stage := strings.TrimSpace(input[stageStart:end])
+		if stage == "" {
+			return errEmptyStage
+		}
+		p.stages = append(p.stages, stage)
+		return nil
+	}
+	for i, tok := range tokens {
+		switch tok.kind {
+		case tokenPipe:
+			if err := endStage(tok.start); err != nil {
+				return p, err
+			}
+			stageStart = tok.end
+		case tokenRedirect:
+			if err := endStage(tok.start); err != nil {
+				return p, err
+			}
+			p.redirected, p.append = true, tok.value == ">>"
+			target := tokens[i+1:]
+			switch {
+			case len(target) == 0 || target[0].kind != tokenWord:
+				return p, errMissingRedirect
+			case len(target) > 1:
+				return p, errRedirectTarget
+			}
+			p.redirect = target[0].value
+			return p, nil
+		}
+	}
+	return p, endStage(len(input))
+}
+
+// execute runs a command line and returns the output to show in the scrollback.
+func (m *model) execute(input string) (string, tea.Cmd) {
+	return m.executePiped(input, "", false)
+}
+
+// executePiped runs a command line with stdin piped into its first command,
+// as if it followed another command in a pipeline.
+func (m *model) executePiped(input, stdin string, piped bool) (string, tea.Cmd) {
+	if strings.TrimSpace(input) == "" {
+		return "", nil
+	}
+
+	p, err := parsePipeline(input)
+	if err != nil {
+		return m.syntaxError(err), nil
+	}
+
+	var (
+		output = stdin
+		cmds   []tea.Cmd
+	)
+	// Aliases can expand to a pipeline of their own, so splice their stages in
+	var stages []string
+	for _, stage := range p.stages {
+		expanded, err := parsePipeline(m.expandAlias(stage))
+		if err != nil {
+			return m.syntaxError(err), nil
+		}
+		stages = append(stages, expanded.stages...)
+	}
+
+	for i, stage := range stages {
+		words, err := splitArgs(stage)
+		if err != nil {
+			return m.syntaxError(err), nil
+		}
+		if len(words) == 0 {
+			return m.syntaxError(errEmptyStage), nil
+		}
+		name, argv := words[0], words[1:]
+		args := strings.Join(argv, " ")
+		command, ok := commands[name]
+		egg := false
+		if !ok {
+			command, ok = findEasterEgg(name, args)
+			egg = ok
+		}
+		if !ok {
+			if suggestion := m.suggestCommand(name); suggestion != "" {
+				return m.tr("did you mean", name, suggestion), nil
+			}
+			return m.tr("unknown command", name), nil
+		}
+		// With nothing piped in, a command missing its arguments gets its usage
+		stagePiped := piped || i > 0
+		if !stagePiped && len(argv) == 0 && !egg {
+			if hint := m.usageHint(name); hint != "" {
+				return hint, nil
+			}
+		}
+		// Arguments can contain anything a visitor types, so they're only
+		// logged when debugging
+		m.logger.Info("Running command", "command", name, "piped", stagePiped)
+		m.logger.Debug("Command arguments", "command", name, "args", args)
+		if egg {
+			m.progress.unlock("egg-hunter")
+		} else {
+			m.progress.commandRun(name, stagePiped)
+		}
+		if visitorStats != nil && m.statsSession != 0 {
+			visitorStats.recordCommand(m.statsSession, name)
+		}
+
+		last := i == len(stages)-1
+		in := commandInput{
+			args:       args,
+			argv:       argv,
+			stdin:      output,
+			piped:      stagePiped,
+			toTerminal: last && p.redirect == "" && !m.headless,
+		}
+		out, cmd := command(m, in)
+		output = out
+		cmds = append(cmds, cmd)
+	}
+
+	if p.redirected {
+		if !strings.HasSuffix(output, "\n") {
+			output += "\n"
+		}
+		if err := m.writeFile(p.redirect, output, p.append); err != nil {
+			return err.Error(), tea.Batch(cmds...)
+		}
+		return "", tea.Batch(cmds...)
+	}
+	return output, tea.Batch(cmds...)
+}
+
+// syntaxError explains why a command line couldn't be parsed.
+func (m *model) syntaxError(err error) string {
+	var se syntaxError
+	if errors.As(err, &se) {
+		return m.tr(string(se))
+	}
+	return err.Error()
+}
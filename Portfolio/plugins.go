@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+)
+
+// A plugin is an executable in the plugins directory that adds a command
+// without rebuilding the portfolio. Plugins can be written in any language:
+// each time one is needed it's started, sent one JSON request on stdin, and
+// expected to write one JSON response to stdout and exit.
+//
+// At startup every plugin is asked to describe its command:
+//
+//	→ {"type": "describe"}
+//	← {"name": "hello", "usage": "hello [name]", "summary": "Say hello", "description": "..."}
+//
+// and whenever a visitor runs it:
+//
+//	→ {"type": "run", "args": ["world"], "stdin": "", "piped": false, "terminal": true, "directory": "~", "lang": "en", "width": 80}
+//	← {"output": "Hello, world!"} or {"error": "what went wrong"}
+
+const (
+	pluginDescribeTimeout = 2 * time.Second
+	pluginRunTimeout      = 10 * time.Second
+	// pluginMaxOutput caps what's read from a plugin, stdout and stderr each.
+	pluginMaxOutput = 1 << 20
+)
+
+type plugin struct {
+	path string
+	page manPage
+}
+
+// plugins are the plugins found at startup, sorted by file name.
+var plugins []plugin
+
+type pluginRequest struct {
+	Type      string   `json:"type"`
+	Args      []string `json:"args,omitempty"`
+	Stdin     string   `json:"stdin,omitempty"`
+	Piped     bool     `json:"piped,omitempty"`
+	Terminal  bool     `json:"terminal,omitempty"`
+	Directory string   `json:"directory,omitempty"`
+	Lang      string   `json:"lang,omitempty"`
+	Width     int      `json:"width,omitempty"`
+}
+
+type pluginResponse struct {
+	// Sent in reply to describe
+	Name        string `json:"name"`
+	Usage       string `json:"usage"`
+	Summary     string `json:"summary"`
+	Description string `json:"description"`
+
+	// Sent in reply to run
+	Output string `json:"output"`
+	Error  string `json:"error"`
+}
+
+// cappedBuffer keeps the first max bytes written to it and drops the rest,
+// so a runaway plugin can't fill the server's memory.
+type cappedBuffer struct {
+	bytes.Buffer
+	max int
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.Len(); room > 0 {
+		b.Buffer.Write(p[:min(len(p), room)])
+	}
+	return len(p), nil
+}
+
+// call starts the plugin, sends it req and decodes its response.
+func (p plugin) call(req pluginRequest, timeout time.Duration) (pluginResponse, error) {
+	var resp pluginResponse
+	input, err := json.Marshal(req)
+	if err != nil {
+		return resp, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, p.path)
+	cmd.Stdin = bytes.NewReader(input)
+	stdout, stderr := &cappedBuffer{max: pluginMaxOutput}, &cappedBuffer{max: pluginMaxOutput}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	// Don't wait on children that outlive a plugin killed for taking too long
+	cmd.WaitDelay = time.Second
+	runErr := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return resp, fmt.Errorf("timed out after %s", timeout)
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		if runErr != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return resp, fmt.Errorf("%v: %s", runErr, msg)
+			}
+			return resp, runErr
+		}
+		return resp, fmt.Errorf("invalid response: %v", err)
+	}
+	return resp, nil
+}
+
+// loadPlugins asks every executable in dir to describe itself and adds the
+// commands they provide. A missing directory just means no plugins.
+func loadPlugins(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Warn("Could not read plugins", "dir", dir, "error", err)
+		}
+		return
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || info.IsDir() || info.Mode()&0o111 == 0 || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		path, err := filepath.Abs(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		p := plugin{path: path}
+		resp, err := p.call(pluginRequest{Type: "describe"}, pluginDescribeTimeout)
+		if err != nil {
+			log.Warn("Skipping plugin", "path", path, "error", err)
+			continue
+		}
+
+		name := resp.Name
+		if name == "" {
+			name = strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		}
+		if _, taken := commands[name]; taken || name == "" || quoteArg(name) != name {
+			log.Warn("Skipping plugin, its command name is taken or invalid", "path", path, "command", name)
+			continue
+		}
+		p.page = manPage{
+			name:        name,
+			category:    "plugins",
+			usage:       firstNonEmpty(resp.Usage, name),
+			summary:     firstNonEmpty(resp.Summary, "A command added by a plugin"),
+			description: firstNonEmpty(resp.Description, resp.Summary, "A command added by a plugin."),
+		}
+		commands[name] = p.run
+		manPages = append(manPages, p.page)
+		plugins = append(plugins, p)
+		log.Info("Loaded plugin", "command", name, "path", path)
+	}
+}
+
+// firstNonEmpty returns the first of values that isn't empty.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// pluginNames returns the commands added by plugins, for autocomplete.
+func pluginNames() []string {
+	var names []string
+	for _, p := range plugins {
+		names = append(names, p.page.name)
+	}
+	return names
+}
+
+func (p plugin) run(m *model, in commandInput) (string, tea.Cmd) {
+	req := pluginRequest{
+		Type:      "run",
+		Args:      in.argv,
+		Stdin:     in.stdin,
+		Piped:     in.piped,
+		Terminal:  in.toTerminal,
+		Directory: m.displayDirectory(),
+		Lang:      m.lang,
+		Width:     m.viewport.Width,
+	}
+	name, logger := p.page.name, m.logger
+	call := func() string {
+		resp, err := p.call(req, pluginRunTimeout)
+		switch {
+		case err != nil:
+			logger.Warn("Plugin failed", "command", name, "error", err)
+			return name + ": " + err.Error()
+		case resp.Error != "":
+			return name + ": " + resp.Error
+		}
+		return strings.TrimSuffix(resp.Output, "\n")
+	}
+	// Like the web commands, keep the shell responsive while it runs
+	if !in.toTerminal {
+		return call(), nil
+	}
+	return "", m.startApp(newLoader(m.theme, "Running "+name, call))
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// Yahoo's chart API covers stocks and crypto and doesn't need a key
+	priceAPI       = "https://query1.finance.yahoo.com/v8/finance/chart/"
+	priceCacheTTL  = time.Minute
+	sparklineWidth = 40
+)
+
+// priceSymbolPattern matches ticker symbols like AAPL, BRK-B or ^GSPC.
+var priceSymbolPattern = regexp.MustCompile(`^[A-Za-z0-9.^=-]{1,15}$`)
+
+// cryptoSymbols are looked up as their price in US dollars, so "price btc"
+// works without knowing Yahoo calls it BTC-USD.
+var cryptoSymbols = map[string]bool{
+	"BTC": true, "ETH": true, "SOL": true, "DOGE": true, "ADA": true,
+	"XRP": true, "LTC": true, "DOT": true, "BNB": true, "AVAX": true,
+}
+
+// sparkBlocks are the bar heights of a sparkline, lowest first.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+type quote struct {
+	symbol        string
+	name          string
+	currency      string
+	price         float64
+	previousClose float64
+	history       []float64 // prices over the last day, oldest first
+}
+
+// priceCache keeps quotes for priceCacheTTL, so a busy server doesn't ask
+// Yahoo for the same symbol again and again.
+var priceCache = struct {
+	sync.Mutex
+	entries map[string]priceCacheEntry
+}{entries: map[string]priceCacheEntry{}}
+
+type priceCacheEntry struct {
+	fetched time.Time
+	quote   *quote
+}
+
+// fetchQuote returns the latest quote for symbol, from the cache if it's fresh.
+func fetchQuote(symbol string) (*quote, error) {
+	priceCache.Lock()
+	entry, ok := priceCache.entries[symbol]
+	priceCache.Unlock()
+	if ok && time.Since(entry.fetched) < priceCacheTTL {
+		return entry.quote, nil
+	}
+
+	var data struct {
+		Chart struct {
+			Result []struct {
+				Meta struct {
+					Symbol             string  `json:"symbol"`
+					ShortName          string  `json:"shortName"`
+					Currency           string  `json:"currency"`
+					RegularMarketPrice float64 `json:"regularMarketPrice"`
+					ChartPreviousClose float64 `json:"chartPreviousClose"`
+				} `json:"meta"`
+				Indicators struct {
+					Quote []struct {
+						Close []*float64 `json:"close"`
+					} `json:"quote"`
+				} `json:"indicators"`
+			} `json:"result"`
+		} `json:"chart"`
+	}
+	req, err := http.NewRequest("GET", priceAPI+symbol+"?range=1d&interval=5m", nil)
+	if err != nil {
+		return nil, err
+	}
+	// Yahoo turns away requests without a user agent
+	req.Header.Set("User-Agent", "fred-cli")
+	if err := doJSON(req, &data); err != nil {
+		return nil, err
+	}
+	if len(data.Chart.Result) == 0 {
+		return nil, &statusError{code: http.StatusNotFound, status: "404 Not Found"}
+	}
+
+	result := data.Chart.Result[0]
+	q := &quote{
+		symbol:        result.Meta.Symbol,
+		name:          result.Meta.ShortName,
+		currency:      result.Meta.Currency,
+		price:         result.Meta.RegularMarketPrice,
+		previousClose: result.Meta.ChartPreviousClose,
+	}
+	if len(result.Indicators.Quote) > 0 {
+		for _, c := range result.Indicators.Quote[0].Close {
+			// Intervals without trades are null
+			if c != nil {
+				q.history = append(q.history, *c)
+			}
+		}
+	}
+
+	priceCache.Lock()
+	priceCache.entries[symbol] = priceCacheEntry{fetched: time.Now(), quote: q}
+	priceCache.Unlock()
+	return q, nil
+}
+
+// sparkline draws values as a row of block characters, width wide, each
+// block the average of the values that fall into it.
+func sparkline(values []float64, width int) string {
+	if len(values) == 0 {
+		return ""
+	}
+	width = min(width, len(values))
+	buckets := make([]float64, width)
+	for i := range buckets {
+		from, to := i*len(values)/width, (i+1)*len(values)/width
+		sum := 0.0
+		for _, v := range values[from:to] {
+			sum += v
+		}
+		buckets[i] = sum / float64(to-from)
+	}
+
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, v := range buckets {
+		low, high = math.Min(low, v), math.Max(high, v)
+	}
+	var b strings.Builder
+	for _, v := range buckets {
+		level := len(sparkBlocks) / 2
+		if high > low {
+			level = int((v - low) / (high - low) * float64(len(sparkBlocks)-1))
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
+
+func (q *quote) render(t *theme) string {
+	var b strings.Builder
+	title := q.symbol
+	if q.name != "" {
+		title += " · " + q.name
+	}
+	b.WriteString(t.header.Render(title) + "\n")
+
+	change := q.price - q.previousClose
+	style, arrow := t.success, "▲"
+	if change < 0 {
+		style, arrow = t.danger, "▼"
+	}
+	fmt.Fprintf(&b, "%s %s  ", formatPrice(q.price), q.currency)
+	if q.previousClose != 0 {
+		b.WriteString(style.Render(fmt.Sprintf("%s %s (%+.2f%%)", arrow, formatPrice(math.Abs(change)), change/q.previousClose*100)))
+	}
+	b.WriteString("\n\n")
+	if len(q.history) > 1 {
+		b.WriteString(style.Render(sparkline(q.history, sparklineWidth)) + "\n")
+		b.WriteString(t.muted.Render(fmt.Sprintf("low %s · high %s", formatPrice(minOf(q.history)), formatPrice(maxOf(q.history)))) + "\n")
+	}
+	b.WriteString(t.subtle.Render("Prices from Yahoo Finance, may be delayed"))
+	return b.String()
+}
+
+// formatPrice shows enough decimals for both stocks and cheap coins.
+func formatPrice(p float64) string {
+	if p < 1 {
+		return fmt.Sprintf("%.4f", p)
+	}
+	return fmt.Sprintf("%.2f", p)
+}
+
+func minOf(values []float64) float64 {
+	m := math.Inf(1)
+	for _, v := range values {
+		m = math.Min(m, v)
+	}
+	return m
+}
+
+func maxOf(values []float64) float64 {
+	m := math.Inf(-1)
+	for _, v := range values {
+		m = math.Max(m, v)
+	}
+	return m
+}
+
+// priceResult returns the rendered quote for symbol, or a readable error in
+// the language of tr.
+func priceResult(symbol string, t *theme, tr func(string, ...any) string) string {
+	q, err := fetchQuote(symbol)
+	var statusErr *statusError
+	switch {
+	case errors.As(err, &statusErr) && statusErr.code == http.StatusNotFound:
+		return tr("price no symbol", symbol)
+	case err != nil:
+		return tr("price error", describeFetchError(err))
+	}
+	return q.render(t)
+}
+
+func priceCommand(m *model, in commandInput) (string, tea.Cmd) {
+	if !priceSymbolPattern.MatchString(in.args) {
+		return m.tr("price usage"), nil
+	}
+	symbol := strings.ToUpper(in.args)
+	if cryptoSymbols[symbol] {
+		symbol += "-USD"
+	}
+	if !in.toTerminal {
+		return priceResult(symbol, m.theme, m.tr), nil
+	}
+	t, tr := m.theme, m.tr
+	return "", m.startApp(newLoader(t, tr("price loading", symbol), func() string {
+		return priceResult(symbol, t, tr)
+	}))
+}
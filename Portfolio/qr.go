@@ -0,0 +1,61 @@
+package main
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mdp/qrterminal/v3"
+	"rsc.io/qr"
+)
+
+// qrCode draws text as a QR code. Full size codes use two columns per
+// module; compact ones pack two rows of modules into each line with half
+// blocks, which is a quarter of the area. A full size code wider than width
+// is drawn compact instead, unless width is 0. level is the error-correction
+// level, L, M, Q or H, from the smallest code to the one that survives the
+// most damage.
+func qrCode(text, level string, compact bool, width int) string {
+	l := qr.L
+	switch level {
+	case "M":
+		l = qr.M
+	case "Q":
+		l = qr.Q
+	case "H":
+		l = qr.H
+	}
+	var b strings.Builder
+	if !compact {
+		qrterminal.Generate(text, l, &b)
+		code := strings.TrimRight(b.String(), "\n")
+		if first, _, _ := strings.Cut(code, "\n"); width == 0 || lipgloss.Width(first) <= width {
+			return code
+		}
+		b.Reset()
+	}
+	qrterminal.GenerateHalfBlock(text, l, &b)
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func qrCommand(m *model, in commandInput) (string, tea.Cmd) {
+	f, rest, err := parseFlags(in.argv, []string{"compact"}, []string{"level"})
+	if err != nil {
+		return "qr: " + err.Error() + "\n" + m.tr("qr usage"), nil
+	}
+	text := strings.Join(rest, " ")
+	if text == "" {
+		text = strings.TrimSpace(in.stdin)
+	}
+	if text == "" {
+		return m.tr("qr usage"), nil
+	}
+	level := "L"
+	if f.has("level") {
+		level = strings.ToUpper(f["level"])
+		if !strings.Contains("LMQH", level) || len(level) != 1 {
+			return "qr: the level must be L, M, Q or H\n" + m.tr("qr usage"), nil
+		}
+	}
+	return m.tr("qr title", text) + "\n\n" + qrCode(text, level, f.has("compact"), m.viewport.Width), nil
+}
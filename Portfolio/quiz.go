@@ -0,0 +1,216 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"math/rand"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The questions are separated by blank lines. Each starts with the question
+// on one line, followed by its choices, with "* " before the right answer
+// and "- " before the others.
+//
+//go:embed Extra/quiz.txt
+var quizFile string
+
+// quizLength is how many questions one round of the quiz asks.
+const quizLength = 5
+
+type question struct {
+	text    string
+	choices []string
+	answer  int // index into choices
+}
+
+// questions parses the embedded quiz file.
+func questions() []question {
+	var list []question
+	for _, block := range strings.Split(strings.TrimSpace(quizFile), "\n\n") {
+		lines := strings.Split(strings.TrimSpace(block), "\n")
+		q := question{text: lines[0], answer: -1}
+		for _, line := range lines[1:] {
+			if choice, ok := strings.CutPrefix(line, "* "); ok {
+				q.answer = len(q.choices)
+				q.choices = append(q.choices, choice)
+			} else {
+				q.choices = append(q.choices, strings.TrimPrefix(line, "- "))
+			}
+		}
+		if q.answer >= 0 {
+			list = append(list, q)
+		}
+	}
+	return list
+}
+
+// newRound picks quizLength random questions with their choices shuffled.
+func newRound() []question {
+	all := questions()
+	rand.Shuffle(len(all), func(i, j int) { all[i], all[j] = all[j], all[i] })
+	round := all[:min(quizLength, len(all))]
+	for i := range round {
+		q := &round[i]
+		q.choices = append([]string(nil), q.choices...)
+		rand.Shuffle(len(q.choices), func(a, b int) {
+			q.choices[a], q.choices[b] = q.choices[b], q.choices[a]
+			switch q.answer {
+			case a:
+				q.answer = b
+			case b:
+				q.answer = a
+			}
+		})
+	}
+	return round
+}
+
+type quizModel struct {
+	theme     *theme
+	questions []question
+	current   int   // question being asked, len(questions) once finished
+	selected  int   // highlighted choice
+	answers   []int // the choice picked for each question so far
+	answered  bool  // true while showing whether the last answer was right
+	width     int
+}
+
+func quizCommand(m *model, in commandInput) (string, tea.Cmd) {
+	return "", m.startApp(newQuiz(m.width, m.theme))
+}
+
+func newQuiz(width int, th *theme) *quizModel {
+	q := &quizModel{theme: th, width: width}
+	q.reset()
+	return q
+}
+
+func (q *quizModel) reset() {
+	q.questions = newRound()
+	q.current, q.selected = 0, 0
+	q.answers = nil
+	q.answered = false
+}
+
+func (q *quizModel) finished() bool {
+	return q.current == len(q.questions)
+}
+
+func (q *quizModel) score() int {
+	n := 0
+	for i, a := range q.answers {
+		if a == q.questions[i].answer {
+			n++
+		}
+	}
+	return n
+}
+
+func (q *quizModel) Init() tea.Cmd {
+	return nil
+}
+
+func (q *quizModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		q.width = msg.Width
+	case tea.KeyMsg:
+		key := msg.String()
+		if q.finished() {
+			switch key {
+			case "r":
+				q.reset()
+			case "q", "esc", "enter":
+				result := fmt.Sprintf("Quiz: %d out of %d right", q.score(), len(q.questions))
+				return q, exitApp("quiz", q.score(), result)
+			}
+			return q, nil
+		}
+		if key == "esc" || key == "ctrl+c" || key == "q" {
+			return q, exitApp("", 0, "Quiz cancelled.")
+		}
+		if q.answered {
+			// Enter moves on from the answer to the next question
+			if key == "enter" || key == " " {
+				q.current++
+				q.selected = 0
+				q.answered = false
+			}
+			return q, nil
+		}
+
+		choices := len(q.questions[q.current].choices)
+		switch key {
+		case "up", "k":
+			q.selected = (q.selected - 1 + choices) % choices
+		case "down", "j":
+			q.selected = (q.selected + 1) % choices
+		case "enter", " ":
+			q.answer()
+		default:
+			// Number keys pick a choice straight away
+			if len(key) == 1 && key[0] >= '1' && int(key[0]-'1') < choices {
+				q.selected = int(key[0] - '1')
+				q.answer()
+			}
+		}
+	}
+	return q, nil
+}
+
+func (q *quizModel) answer() {
+	q.answers = append(q.answers, q.selected)
+	q.answered = true
+}
+
+func (q *quizModel) View() string {
+	th := q.theme
+	title := th.header.Render("🧠 Quiz")
+	width := max(20, min(70, q.width-4))
+
+	if q.finished() {
+		var b strings.Builder
+		fmt.Fprintf(&b, "You got %d out of %d right!\n\n", q.score(), len(q.questions))
+		for i, question := range q.questions {
+			mark, style := "✓", th.success
+			if q.answers[i] != question.answer {
+				mark, style = "✗", th.danger
+			}
+			b.WriteString(style.Render(mark) + " " + th.style().Width(width-8).Render(question.text) + "\n")
+		}
+		b.WriteString("\n" + th.muted.Render("r to play again • q to return to the shell"))
+		box := th.style().Border(lipgloss.RoundedBorder()).BorderForeground(th.colors.accent).Padding(1, 3)
+		return lipgloss.JoinVertical(lipgloss.Left, title, "", box.Render(b.String()))
+	}
+
+	question := q.questions[q.current]
+	progress := th.muted.Render(fmt.Sprintf("Question %d of %d · %d right so far", q.current+1, len(q.questions), q.score()))
+	var b strings.Builder
+	for i, choice := range question.choices {
+		line := fmt.Sprintf("%d. %s", i+1, choice)
+		switch {
+		case q.answered && i == question.answer:
+			b.WriteString(th.success.Render("✓ "+line) + "\n")
+		case q.answered && i == q.selected:
+			b.WriteString(th.danger.Render("✗ "+line) + "\n")
+		case !q.answered && i == q.selected:
+			b.WriteString(th.selected.Render("› "+line) + "\n")
+		default:
+			b.WriteString("  " + line + "\n")
+		}
+	}
+
+	help := th.muted.Render("↑/↓ to choose, enter or 1-4 to answer • esc to quit")
+	if q.answered {
+		verdict := th.success.Render("Correct!")
+		if q.selected != question.answer {
+			verdict = th.danger.Render("Not quite, the answer was " + question.choices[question.answer] + ".")
+		}
+		help = verdict + "\n\n" + th.muted.Render("enter for the next question")
+	}
+	text := th.style().Bold(true).Width(width).Render(question.text)
+	return lipgloss.JoinVertical(lipgloss.Left, title, "", progress, "", text, "", b.String(), help)
+}
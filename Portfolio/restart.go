@@ -0,0 +1,169 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// Deploying a new version shouldn't kick everyone off. On SIGUSR2 the server
+// starts its replacement, hands it the listening sockets and stops accepting
+// connections, then waits for its own visitors to leave before exiting. The
+// new process finds the sockets through PORTFOLIO_LISTENERS, a comma
+// separated list of listener names, passed as file descriptors 3 onwards.
+
+// drainTimeout is how long a replaced server waits for its visitors to leave
+// before disconnecting them. It can be set with PORTFOLIO_DRAIN_TIMEOUT.
+var drainTimeout = time.Hour
+
+func init() {
+	if v := os.Getenv("PORTFOLIO_DRAIN_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			log.Warn("Ignoring invalid PORTFOLIO_DRAIN_TIMEOUT", "value", v)
+			return
+		}
+		drainTimeout = d
+	}
+}
+
+var listeners struct {
+	sync.Mutex
+	inherited map[string]net.Listener // handed over by the previous process, not yet used
+	open      map[string]*net.TCPListener
+}
+
+// inheritListeners picks up the sockets from the process this one replaced.
+func inheritListeners() {
+	listeners.Lock()
+	defer listeners.Unlock()
+	listeners.inherited = map[string]net.Listener{}
+	listeners.open = map[string]*net.TCPListener{}
+	names := os.Getenv("PORTFOLIO_LISTENERS")
+	if names == "" {
+		return
+	}
+	// Children of this process shouldn't think they were handed sockets too
+	os.Unsetenv("PORTFOLIO_LISTENERS")
+	for i, name := range strings.Split(names, ",") {
+		f := os.NewFile(uintptr(3+i), name)
+		ln, err := net.FileListener(f)
+		f.Close()
+		if err != nil {
+			log.Error("Could not use inherited listener", "name", name, "error", err)
+			continue
+		}
+		listeners.inherited[name] = ln
+	}
+	log.Info("Took over listeners from the previous server", "listeners", names)
+}
+
+// listen returns the named listener handed over by the previous process if
+// there is one, or starts listening on addr.
+func listen(name, addr string) (net.Listener, error) {
+	listeners.Lock()
+	defer listeners.Unlock()
+	ln, ok := listeners.inherited[name]
+	if ok {
+		delete(listeners.inherited, name)
+	} else {
+		var err error
+		if ln, err = net.Listen("tcp", addr); err != nil {
+			return nil, err
+		}
+	}
+	if tcp, ok := ln.(*net.TCPListener); ok {
+		listeners.open[name] = tcp
+	}
+	return ln, nil
+}
+
+// closedListener reports whether err is from a listener being closed, which
+// is how servers stop accepting when they're replaced.
+func closedListener(err error) bool {
+	return errors.Is(err, net.ErrClosed)
+}
+
+// handOver starts a new copy of the server with the same arguments and gives
+// it the listening sockets. Once it's running, this process stops accepting
+// connections; the sockets stay open in the new one.
+func handOver() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	listeners.Lock()
+	defer listeners.Unlock()
+	var (
+		names []string
+		files []*os.File
+	)
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+	for name, ln := range listeners.open {
+		f, err := ln.File()
+		if err != nil {
+			return fmt.Errorf("listener %s: %w", name, err)
+		}
+		names = append(names, name)
+		files = append(files, f)
+	}
+
+	cmd := exec.Command(exe, os.Args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.Env = append(os.Environ(), "PORTFOLIO_LISTENERS="+strings.Join(names, ","))
+	cmd.ExtraFiles = files
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	log.Info("Started new server", "pid", cmd.Process.Pid, "listeners", strings.Join(names, ","))
+	for name, ln := range listeners.open {
+		ln.Close()
+		delete(listeners.open, name)
+	}
+	return nil
+}
+
+// drain waits for every session to end, up to drainTimeout.
+func drain() {
+	deadline := time.Now().Add(drainTimeout)
+	for n := sessions.active(); n > 0; n = sessions.active() {
+		if time.Now().After(deadline) {
+			log.Warn("Gave up waiting for visitors to leave", "sessions", n)
+			return
+		}
+		log.Info("Waiting for visitors to leave", "sessions", n)
+		time.Sleep(10 * time.Second)
+	}
+}
+
+// writePIDFile records this process's id, so a supervisor can follow the
+// server across restarts. It's set with PORTFOLIO_PID_FILE.
+func writePIDFile() {
+	path := os.Getenv("PORTFOLIO_PID_FILE")
+	if path == "" {
+		return
+	}
+	if err := os.WriteFile(path, []byte(strconv.Itoa(os.Getpid())+"\n"), 0o644); err != nil {
+		log.Error("Could not write PID file", "path", path, "error", err)
+	}
+}
+
+// reload rereads the config and message of the day on SIGHUP. Sessions that
+// are already open keep their aliases and theme; new ones get the changes.
+func reload() {
+	setConfig(loadConfig(configPath()))
+	loadMOTD(motdPath())
+	log.Info("Reloaded configuration", "config", configPath())
+}
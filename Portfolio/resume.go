@@ -0,0 +1,96 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The resume is embedded so it's always available, even when the server is
+// started outside the portfolio directory. Visitors can also cat the JSON.
+//
+//go:embed About/resume.json
+var resumeJSON []byte
+
+type resumeEntry struct {
+	Title   string   `json:"title"`
+	Place   string   `json:"place"`
+	Dates   string   `json:"dates"`
+	Details []string `json:"details"`
+}
+
+type resumeSection struct {
+	Title   string        `json:"title"`
+	Entries []resumeEntry `json:"entries"`
+}
+
+type resume struct {
+	Name     string          `json:"name"`
+	Headline string          `json:"headline"`
+	PDF      string          `json:"pdf"`
+	Sections []resumeSection `json:"sections"`
+}
+
+func loadResume() (resume, error) {
+	var r resume
+	err := json.Unmarshal(resumeJSON, &r)
+	return r, err
+}
+
+// render lays the resume out for a terminal of the given width.
+func (r resume) render(width int, t *theme) string {
+	width = max(40, min(80, width-4))
+	sectionStyle := t.success.Bold(true).
+		Border(lipgloss.NormalBorder(), false, false, true, false).
+		BorderForeground(t.colors.subtle)
+	var b strings.Builder
+	b.WriteString(t.accent.Render(r.Name) + "\n")
+	b.WriteString(t.muted.Render(r.Headline) + "\n")
+
+	for _, section := range r.Sections {
+		b.WriteString("\n" + sectionStyle.Width(width).Render(section.Title) + "\n")
+		for _, entry := range section.Entries {
+			if entry.Title != "" {
+				heading := t.file.Bold(true).Render(entry.Title)
+				if entry.Place != "" {
+					heading += t.muted.Render(" · " + entry.Place)
+				}
+				// Right-align the dates like a printed resume
+				gap := max(1, width-lipgloss.Width(heading)-lipgloss.Width(entry.Dates))
+				b.WriteString(heading + strings.Repeat(" ", gap) + t.muted.Render(entry.Dates) + "\n")
+			}
+			for _, detail := range entry.Details {
+				b.WriteString(t.style().Width(width).Render("  • "+detail) + "\n")
+			}
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString(t.muted.Render("Run 'resume download' for a PDF copy."))
+	return b.String()
+}
+
+func resumeCommand(m *model, in commandInput) (string, tea.Cmd) {
+	r, err := loadResume()
+	if err != nil {
+		return m.tr("resume error", err), nil
+	}
+
+	switch in.args {
+	case "":
+		if !in.toTerminal {
+			return r.render(80, m.theme), nil
+		}
+		m.openPager("resume", r.render(m.viewport.Width, m.theme))
+		return "", nil
+	case "download":
+		if !in.toTerminal {
+			return r.PDF, nil
+		}
+		return m.tr("resume download", r.PDF, qrCode(r.PDF, "L", false, m.viewport.Width)), nil
+	default:
+		return m.tr("resume usage"), nil
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+// entryKind says how a scrollback entry is drawn.
+type entryKind int
+
+const (
+	entryOutput  entryKind = iota // a command's output
+	entryBanner                   // the FRED CLI banner
+	entryMessage                  // a message from the shell, text is its message id
+)
+
+// entry is one item in the scrollback. Entries are rendered when drawn, so
+// they re-wrap when the terminal is resized, the banner picks up a new message
+// of the day, and the banner and shell messages follow theme and language
+// changes. Command output keeps the colours it was
+// produced with.
+type entry struct {
+	kind    entryKind
+	command string // the command line that produced the output, if any
+	text    string
+	at      time.Time
+
+	// The last rendering, reused until the width, theme or language changes
+	rendered string
+	key      renderKey
+}
+
+type renderKey struct {
+	width int
+	theme *theme
+	lang  string
+	motd  string // redraws the banner when the message of the day changes
+}
+
+func outputEntry(command, text string) entry {
+	return entry{kind: entryOutput, command: command, text: text, at: time.Now()}
+}
+
+func messageEntry(id string) entry {
+	return entry{kind: entryMessage, text: id, at: time.Now()}
+}
+
+func bannerEntry() entry {
+	return entry{kind: entryBanner, at: time.Now()}
+}
+
+// print adds output to the scrollback.
+func (m *model) print(text string) {
+	m.clihistory = append(m.clihistory, outputEntry("", text))
+}
+
+// render draws e for the current terminal width, theme and language.
+func (m model) render(e entry) string {
+	var text string
+	switch e.kind {
+	case entryBanner:
+		// The banner is drawn to size, wrapping would break it apart
+		banner := headerView(m.theme)
+		if box := motdView(m.theme, currentMOTD(), m.viewport.Width); box != "" {
+			banner += "\n" + box
+		}
+		return banner
+	case entryMessage:
+		text = m.tr(e.text)
+	default:
+		text = e.text
+		if text == "" {
+			// Commands without output (like cd) echo what was typed
+			text = e.command
+		}
+	}
+	if m.viewport.Width <= 0 {
+		return text
+	}
+	wrapped := m.theme.style().Width(m.viewport.Width).Render(text)
+	// Width pads every line out, which would only get in the way of searches
+	lines := strings.Split(wrapped, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// scrollback renders every entry into the main viewport's content.
+func (m *model) scrollback() string {
+	key := renderKey{width: m.viewport.Width, theme: m.theme, lang: m.lang, motd: currentMOTD()}
+	var contentBuilder strings.Builder
+	for i := range m.clihistory {
+		e := &m.clihistory[i]
+		if e.key != key || e.rendered == "" {
+			e.rendered, e.key = m.render(*e), key
+		}
+		contentBuilder.WriteString(e.rendered)
+		contentBuilder.WriteString("\n")
+	}
+	return contentBuilder.String()
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ansiEscape matches the colour, style and hyperlink sequences in rendered
+// output, so searches see the text a visitor sees.
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;?]*[ -/]*[@-~]|\x1b\\][^\x07\x1b]*(?:\x07|\x1b\\\\)")
+
+// search is a less-style search over a viewport's content. Pressing / starts
+// typing a pattern, enter finds it, n and N move between matching lines and
+// esc stops searching.
+type search struct {
+	typing  bool   // reading the pattern
+	query   string // the pattern as typed
+	pattern *regexp.Regexp
+	matches []int // indexes of the lines that match
+	current int   // index into matches of the line jumped to
+}
+
+// active reports whether the search is reading a pattern or showing matches.
+func (s search) active() bool {
+	return s.typing || s.pattern != nil
+}
+
+// handleKey updates the search for a key press, scrolling vp to the current
+// match. content is what vp is showing, before any highlighting. It returns
+// false if the key isn't one the search uses.
+func (s *search) handleKey(msg tea.KeyMsg, vp *viewport.Model, content string) bool {
+	if s.typing {
+		switch msg.Type {
+		case tea.KeyEnter:
+			s.find(content, vp)
+		case tea.KeyEsc, tea.KeyCtrlC:
+			*s = search{}
+		case tea.KeyBackspace:
+			if s.query == "" {
+				*s = search{}
+			} else {
+				r := []rune(s.query)
+				s.query = string(r[:len(r)-1])
+			}
+		case tea.KeyRunes, tea.KeySpace:
+			s.query += string(msg.Runes)
+		}
+		return true
+	}
+
+	switch msg.String() {
+	case "/":
+		*s = search{typing: true}
+		return true
+	case "n":
+		if s.pattern == nil {
+			return false
+		}
+		s.jump(vp, 1)
+		return true
+	case "N":
+		if s.pattern == nil {
+			return false
+		}
+		s.jump(vp, -1)
+		return true
+	case "esc":
+		if s.pattern == nil {
+			return false
+		}
+		*s = search{}
+		return true
+	}
+	return false
+}
+
+// find compiles the typed query and jumps to the first match at or below the
+// top of vp. The query is matched literally, ignoring case.
+func (s *search) find(content string, vp *viewport.Model) {
+	s.typing = false
+	if s.query == "" {
+		*s = search{}
+		return
+	}
+	s.pattern = regexp.MustCompile("(?i)" + regexp.QuoteMeta(s.query))
+	s.matches = nil
+	s.current = 0
+	for i, line := range strings.Split(content, "\n") {
+		if s.pattern.MatchString(ansiEscape.ReplaceAllString(line, "")) {
+			s.matches = append(s.matches, i)
+		}
+	}
+	if len(s.matches) == 0 {
+		return
+	}
+	for i, line := range s.matches {
+		if line >= vp.YOffset {
+			s.current = i
+			break
+		}
+	}
+	vp.SetYOffset(s.matches[s.current])
+}
+
+// jump moves to the next match, or the previous one when delta is negative,
+// wrapping around at either end.
+func (s *search) jump(vp *viewport.Model, delta int) {
+	if len(s.matches) == 0 {
+		return
+	}
+	s.current = (s.current + delta + len(s.matches)) % len(s.matches)
+	vp.SetYOffset(s.matches[s.current])
+}
+
+// highlight returns content with the matches marked. Matching lines lose
+// their own colours so the highlight stands out.
+func (s search) highlight(content string, t *theme) string {
+	if s.pattern == nil || len(s.matches) == 0 {
+		return content
+	}
+	lines := strings.Split(content, "\n")
+	for i, n := range s.matches {
+		if n >= len(lines) {
+			break
+		}
+		style := t.accent
+		if i == s.current {
+			style = t.selected
+		}
+		lines[n] = s.pattern.ReplaceAllStringFunc(ansiEscape.ReplaceAllString(lines[n], ""), func(match string) string {
+			return style.Render(match)
+		})
+	}
+	return strings.Join(lines, "\n")
+}
+
+// searchStatus describes s for a footer: the pattern being typed, or where
+// the current match is.
+func (m model) searchStatus(s search) string {
+	switch {
+	case s.typing:
+		return "/" + s.query
+	case len(s.matches) == 0:
+		return m.tr("search none", s.query)
+	default:
+		return m.tr("search matches", s.current+1, len(s.matches))
+	}
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	snakeMaxWidth  = 30 // board size in cells, each cell is two columns wide
+	snakeMaxHeight = 20
+	snakeTick      = 120 * time.Millisecond
+)
+
+type point struct{ x, y int }
+
+// snakeTickMsg advances the game. The id ties it to one game so ticks from
+// a game that was quit don't speed up the next one.
+type snakeTickMsg struct{ id int64 }
+
+// snakeGames counts started games to give each one a unique tick id.
+// It's shared by every SSH session, hence atomic.
+var snakeGames atomic.Int64
+
+type snakeModel struct {
+	theme         *theme
+	id            int64
+	width, height int
+	body          []point // head first
+	dir, nextDir  point
+	food          point
+	score         int
+	dead, paused  bool
+}
+
+func playCommand(m *model, in commandInput) (string, tea.Cmd) {
+	switch in.args {
+	case "", "snake":
+		return "", m.startApp(newSnake(m.width, m.height, m.theme))
+	default:
+		return m.tr("play usage"), nil
+	}
+}
+
+// newSnake sizes the board to fit a terminal of the given size.
+func newSnake(termWidth, termHeight int, t *theme) *snakeModel {
+	s := &snakeModel{
+		theme: t,
+		id:    snakeGames.Add(1),
+		// Two columns per cell plus the border, and room for the score lines
+		width:  max(8, min(snakeMaxWidth, (termWidth-2)/2)),
+		height: max(6, min(snakeMaxHeight, termHeight-5)),
+	}
+	s.reset()
+	return s
+}
+
+func (s *snakeModel) reset() {
+	mid := point{s.width / 2, s.height / 2}
+	s.body = []point{mid, {mid.x - 1, mid.y}, {mid.x - 2, mid.y}}
+	s.dir, s.nextDir = point{1, 0}, point{1, 0}
+	s.score = 0
+	s.dead, s.paused = false, false
+	s.placeFood()
+}
+
+func (s *snakeModel) placeFood() {
+	for {
+		p := point{rand.Intn(s.width), rand.Intn(s.height)}
+		if !s.occupies(p) {
+			s.food = p
+			return
+		}
+	}
+}
+
+func (s *snakeModel) occupies(p point) bool {
+	for _, b := range s.body {
+		if b == p {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *snakeModel) tick() tea.Cmd {
+	id := s.id
+	return tea.Tick(snakeTick, func(time.Time) tea.Msg { return snakeTickMsg{id: id} })
+}
+
+func (s *snakeModel) Init() tea.Cmd {
+	return s.tick()
+}
+
+func (s *snakeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return s, exitApp("snake", s.score, "")
+		case "up", "w", "k":
+			s.turn(point{0, -1})
+		case "down", "s", "j":
+			s.turn(point{0, 1})
+		case "left", "a", "h":
+			s.turn(point{-1, 0})
+		case "right", "d", "l":
+			s.turn(point{1, 0})
+		case "p", " ":
+			if !s.dead {
+				s.paused = !s.paused
+			}
+		case "r":
+			if s.dead {
+				s.reset()
+			}
+		}
+	case snakeTickMsg:
+		if msg.id != s.id {
+			return s, nil
+		}
+		if !s.dead && !s.paused {
+			s.step()
+		}
+		return s, s.tick()
+	}
+	return s, nil
+}
+
+// turn changes direction on the next step, ignoring a reversal into the body.
+func (s *snakeModel) turn(d point) {
+	if d.x != -s.dir.x || d.y != -s.dir.y {
+		s.nextDir = d
+	}
+}
+
+func (s *snakeModel) step() {
+	s.dir = s.nextDir
+	head := point{s.body[0].x + s.dir.x, s.body[0].y + s.dir.y}
+	if head.x < 0 || head.y < 0 || head.x >= s.width || head.y >= s.height || s.occupies(head) {
+		s.dead = true
+		return
+	}
+	s.body = append([]point{head}, s.body...)
+	if head == s.food {
+		s.score++
+		s.placeFood()
+		return
+	}
+	s.body = s.body[:len(s.body)-1]
+}
+
+func (s *snakeModel) View() string {
+	var board strings.Builder
+	for y := 0; y < s.height; y++ {
+		for x := 0; x < s.width; x++ {
+			p := point{x, y}
+			switch {
+			case p == s.body[0]:
+				board.WriteString(s.theme.success.Render("██"))
+			case s.occupies(p):
+				board.WriteString(s.theme.success.Faint(true).Render("▓▓"))
+			case p == s.food:
+				board.WriteString(s.theme.danger.Render("●") + " ")
+			default:
+				board.WriteString("  ")
+			}
+		}
+		if y < s.height-1 {
+			board.WriteString("\n")
+		}
+	}
+
+	status := fmt.Sprintf("Score: %d", s.score)
+	switch {
+	case s.dead:
+		status += "  •  Game over! r to restart, q to quit"
+	case s.paused:
+		status += "  •  Paused, p to resume"
+	}
+	help := s.theme.muted.Render("arrows/wasd move • p pause • q quit")
+
+	boardStyle := s.theme.style().Border(lipgloss.RoundedBorder()).BorderForeground(s.theme.colors.accent)
+	return lipgloss.JoinVertical(lipgloss.Left, s.theme.header.Render("🐍 Snake"), boardStyle.Render(board.String()), status, help)
+}
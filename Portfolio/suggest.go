@@ -0,0 +1,70 @@
+package main
+
+import "strings"
+
+// levenshtein returns how many single character insertions, deletions and
+// substitutions it takes to turn a into b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// suggestCommand returns the command or alias closest to a mistyped name, or
+// "" if nothing is close enough to be what the visitor meant.
+func (m model) suggestCommand(name string) string {
+	name = strings.ToLower(name)
+	best, bestDistance := "", 3 // two typos at most
+	for _, candidate := range append(m.aliasNames(), m.commandautocomplete...) {
+		d := levenshtein(name, candidate)
+		// Short names are a typo or two away from too much to guess
+		if d < bestDistance && d < len([]rune(name)) {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+// usageHint returns the usage of a command run without the arguments it
+// needs, or "" if it can run without them.
+func (m model) usageHint(name string) string {
+	p, ok := findManPage(name)
+	if !ok || !p.needsArgs() {
+		return ""
+	}
+	return m.tr("usage", p.usage, name)
+}
+
+// needsArgs reports whether p's usage has an argument outside square
+// brackets, like cat <file>, so running the command bare is a mistake.
+func (p manPage) needsArgs() bool {
+	depth := 0
+	for _, r := range p.usage {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '<':
+			if depth == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/log"
+	"github.com/muesli/termenv"
+)
+
+// The telnet listener serves a plain line-by-line version of the shell, for
+// retro terminals and machines with no SSH client. Commands run through
+// Execute, so there's no alt screen, mouse or colour, and full-screen
+// commands like the games are turned away.
+
+// Telnet commands, see RFC 854.
+const (
+	telnetSE   = 240
+	telnetSB   = 250
+	telnetWILL = 251
+	telnetDONT = 254
+	telnetIAC  = 255
+)
+
+// telnetWidth is the line width output is wrapped to. Line mode clients
+// don't tell us their size, and 80 columns suits most of them.
+const telnetWidth = 80
+
+// telnetReader strips telnet option negotiation from what a client sends,
+// leaving only the text typed.
+type telnetReader struct {
+	r *bufio.Reader
+}
+
+func (t telnetReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if n > 0 && t.r.Buffered() == 0 {
+			break
+		}
+		b, err := t.r.ReadByte()
+		if err != nil {
+			return n, err
+		}
+		if b != telnetIAC {
+			p[n] = b
+			n++
+			continue
+		}
+		cmd, err := t.r.ReadByte()
+		if err != nil {
+			return n, err
+		}
+		switch {
+		case cmd == telnetIAC:
+			// An escaped 255 byte
+			p[n] = b
+			n++
+		case cmd >= telnetWILL && cmd <= telnetDONT:
+			// Option negotiation, whose option byte is skipped too. The
+			// client gets no answer, which it takes as a refusal.
+			if _, err := t.r.ReadByte(); err != nil {
+				return n, err
+			}
+		case cmd == telnetSB:
+			// A subnegotiation runs until IAC SE
+			for prev := byte(0); ; {
+				c, err := t.r.ReadByte()
+				if err != nil {
+					return n, err
+				}
+				if prev == telnetIAC && c == telnetSE {
+					break
+				}
+				prev = c
+			}
+		}
+	}
+	return n, nil
+}
+
+// startTelnet serves the line-mode shell on addr in the background.
+func startTelnet(addr string) {
+	ln, err := listen("telnet", addr)
+	if err != nil {
+		log.Error("Could not start telnet listener", "error", err)
+		return
+	}
+	log.Info("Starting telnet listener", "address", addr)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				if closedListener(err) {
+					return
+				}
+				log.Warn("Could not accept telnet connection", "error", err)
+				continue
+			}
+			go serveTelnet(conn)
+		}
+	}()
+}
+
+// serveTelnet runs a session for one telnet client until it disconnects.
+func serveTelnet(conn net.Conn) {
+	defer conn.Close()
+	ip, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		ip = conn.RemoteAddr().String()
+	}
+	// Telnet visitors count towards the same caps as everyone else
+	if reason, ok := sessions.acquire(ip); !ok {
+		log.Warn("Rejecting telnet session", "ip", ip, "reason", reason)
+		io.WriteString(conn, "Too many visitors right now, please try again later.\r\n")
+		return
+	}
+	defer sessions.release(ip)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := newVisitorModel(visitor{
+		transport: "telnet",
+		renderer:  lipgloss.NewRenderer(conn, termenv.WithProfile(termenv.Ascii)),
+		ip:        ip,
+		width:     telnetWidth,
+		height:    24,
+		done:      ctx.Done(),
+	})
+	m.width, m.viewport.Width = telnetWidth, telnetWidth
+
+	write := func(text string) {
+		// Plain text only, with the CR LF line endings telnet expects
+		text = ansiEscape.ReplaceAllString(text, "")
+		io.WriteString(conn, strings.ReplaceAll(text, "\n", "\r\n"))
+	}
+	welcome := m.tr("telnet welcome")
+	if motd := currentMOTD(); motd != "" {
+		welcome += "\n\n" + motd
+	}
+	write(welcome + "\n\n")
+
+	lines := bufio.NewScanner(telnetReader{bufio.NewReader(conn)})
+	for {
+		write("guest@fred:" + m.displayDirectory() + "$ ")
+		if m.idleTimeout > 0 {
+			conn.SetReadDeadline(time.Now().Add(m.idleTimeout))
+		}
+		if !lines.Scan() {
+			return
+		}
+		// Clients end lines with CR LF or CR NUL
+		line := strings.TrimSpace(strings.Trim(lines.Text(), "\r\x00"))
+		switch line {
+		case "":
+			continue
+		case "exit", "quit", "logout":
+			write("Goodbye!\n")
+			return
+		}
+		m.history = trimHistory(append(m.history, line))
+		m.commandsRun++
+		var output string
+		output, m = Execute(m, line)
+		if output != "" {
+			write(output + "\n")
+		}
+	}
+}
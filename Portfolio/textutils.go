@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const defaultLineCount = 10
+
+// textSource reads the text a head/tail/wc invocation operates on: a file
+// named in args, or piped input. The remaining arguments are returned.
+func (m *model) textSource(in commandInput, args []string) (text, name string, rest []string, err error) {
+	if len(args) > 0 {
+		if _, convErr := strconv.Atoi(args[0]); convErr != nil || !in.piped {
+			text, err = m.readFile(args[0])
+			return text, args[0], args[1:], err
+		}
+	}
+	return in.stdin, "", args, nil
+}
+
+// lineCount parses the line count of head and tail, given with -n or as
+// the argument after the file.
+func lineCount(f flags, rest []string) (int, error) {
+	count, ok := f["n"]
+	switch {
+	case ok:
+	case len(rest) > 0:
+		count = rest[0]
+	default:
+		return defaultLineCount, nil
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil || n < 0 {
+		return 0, messageError{id: "line count", args: []any{count}}
+	}
+	return n, nil
+}
+
+// lineSource parses the arguments of head and tail and reads their text.
+func (m *model) lineSource(in commandInput) ([]string, int, error) {
+	f, args, err := parseFlags(in.argv, nil, []string{"n"})
+	if err != nil {
+		return nil, 0, err
+	}
+	text, _, rest, err := m.textSource(in, args)
+	if err != nil {
+		return nil, 0, err
+	}
+	n, err := lineCount(f, rest)
+	if err != nil {
+		return nil, 0, err
+	}
+	return splitLines(text), n, nil
+}
+
+// splitLines splits text into lines, ignoring a trailing newline.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+func headCommand(m *model, in commandInput) (string, tea.Cmd) {
+	if in.args == "" && !in.piped {
+		return m.tr("head usage"), nil
+	}
+	lines, n, err := m.lineSource(in)
+	if err != nil {
+		return "head: " + m.errorText(err), nil
+	}
+	if n < len(lines) {
+		lines = lines[:n]
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func tailCommand(m *model, in commandInput) (string, tea.Cmd) {
+	if in.args == "" && !in.piped {
+		return m.tr("tail usage"), nil
+	}
+	lines, n, err := m.lineSource(in)
+	if err != nil {
+		return "tail: " + m.errorText(err), nil
+	}
+	if n < len(lines) {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func wcCommand(m *model, in commandInput) (string, tea.Cmd) {
+	if in.args == "" && !in.piped {
+		return m.tr("wc usage"), nil
+	}
+	text, name, _, err := m.textSource(in, in.argv)
+	if err != nil {
+		return err.Error(), nil
+	}
+	// Piped output has no trailing newline, so count lines rather than
+	// newlines
+	lines := len(splitLines(text))
+	words := len(strings.Fields(text))
+	chars := utf8.RuneCountInString(text)
+	return strings.TrimSpace(fmt.Sprintf("%7d %7d %7d %s", lines, words, chars, name)), nil
+}
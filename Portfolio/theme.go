@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// palette is the set of colours a theme is built from.
+type palette struct {
+	accent   lipgloss.Color // headers, borders and highlights
+	folder   lipgloss.Color
+	file     lipgloss.Color
+	prompt   lipgloss.Color
+	muted    lipgloss.Color // help text and secondary information
+	subtle   lipgloss.Color // decoration like tree branches and timestamps
+	success  lipgloss.Color
+	danger   lipgloss.Color
+	warning  lipgloss.Color
+	info     lipgloss.Color
+	onAccent lipgloss.Color // text drawn on an accent background
+}
+
+var palettes = map[string]palette{
+	"dark": {
+		accent: "205", folder: "#90EE90", file: "#DDA0DD", prompt: "10",
+		muted: "245", subtle: "240",
+		success: "#90EE90", danger: "#FF6B6B", warning: "#FFD700", info: "#87CEFA",
+		onAccent: "0",
+	},
+	"light": {
+		accent: "#C2255C", folder: "#2B8A3E", file: "#862E9C", prompt: "#2B8A3E",
+		muted: "#6C757D", subtle: "#ADB5BD",
+		success: "#2B8A3E", danger: "#C92A2A", warning: "#E67700", info: "#1864AB",
+		onAccent: "#FFFFFF",
+	},
+	"solarized": {
+		accent: "#D33682", folder: "#859900", file: "#6C71C4", prompt: "#859900",
+		muted: "#839496", subtle: "#586E75",
+		success: "#859900", danger: "#DC322F", warning: "#B58900", info: "#268BD2",
+		onAccent: "#002B36",
+	},
+	"dracula": {
+		accent: "#FF79C6", folder: "#50FA7B", file: "#BD93F9", prompt: "#50FA7B",
+		muted: "#6272A4", subtle: "#44475A",
+		success: "#50FA7B", danger: "#FF5555", warning: "#F1FA8C", info: "#8BE9FD",
+		onAccent: "#282A36",
+	},
+}
+
+// themeNames lists the built-in palettes in the order theme shows them.
+var themeNames = []string{"dark", "light", "solarized", "dracula"}
+
+// theme holds the styles everything is drawn with. Each session has its own,
+// built with the session's renderer so colours match the visitor's terminal
+// rather than the server's.
+type theme struct {
+	name     string
+	colors   palette
+	renderer *lipgloss.Renderer
+
+	header   lipgloss.Style
+	accent   lipgloss.Style // bold accent, for titles and matches
+	folder   lipgloss.Style
+	file     lipgloss.Style
+	prompt   lipgloss.Style
+	muted    lipgloss.Style
+	subtle   lipgloss.Style
+	success  lipgloss.Style
+	danger   lipgloss.Style
+	warning  lipgloss.Style
+	info     lipgloss.Style
+	selected lipgloss.Style // the highlighted item in a menu
+}
+
+func newTheme(name string, r *lipgloss.Renderer) *theme {
+	p := palettes[name]
+	fg := func(c lipgloss.Color) lipgloss.Style { return r.NewStyle().Foreground(c) }
+	return &theme{
+		name:     name,
+		colors:   p,
+		renderer: r,
+		header:   fg(p.accent),
+		accent:   fg(p.accent).Bold(true),
+		folder:   fg(p.folder),
+		file:     fg(p.file),
+		prompt:   fg(p.prompt),
+		muted:    fg(p.muted),
+		subtle:   fg(p.subtle),
+		success:  fg(p.success),
+		danger:   fg(p.danger),
+		warning:  fg(p.warning),
+		info:     fg(p.info),
+		selected: r.NewStyle().Foreground(p.onAccent).Background(p.accent),
+	}
+}
+
+// style returns a blank style for layout, like setting a width.
+func (t *theme) style() lipgloss.Style {
+	return t.renderer.NewStyle()
+}
+
+// defaultTheme picks the theme for a new session: the one set in the config
+// file, otherwise whichever suits the terminal's background.
+func defaultTheme(r *lipgloss.Renderer) *theme {
+	name := currentConfig().theme
+	if _, ok := palettes[name]; ok {
+		return newTheme(name, r)
+	}
+	return detectTheme(r)
+}
+
+// detectTheme returns the dark or light theme to match the terminal.
+func detectTheme(r *lipgloss.Renderer) *theme {
+	if r.HasDarkBackground() {
+		return newTheme("dark", r)
+	}
+	return newTheme("light", r)
+}
+
+// swatch shows a palette's main colours.
+func (t *theme) swatch(p palette) string {
+	var b strings.Builder
+	for _, c := range []lipgloss.Color{p.accent, p.folder, p.file, p.info, p.warning, p.danger} {
+		b.WriteString(t.style().Foreground(c).Render("██"))
+	}
+	return b.String()
+}
+
+func themeCommand(m *model, in commandInput) (string, tea.Cmd) {
+	name := strings.ToLower(in.args)
+	if _, ok := palettes[name]; ok {
+		m.theme = newTheme(name, m.theme.renderer)
+		return "Switched to the " + name + " theme.", nil
+	}
+
+	switch name {
+	case "":
+		var b strings.Builder
+		b.WriteString("Themes:\n")
+		for _, name := range themeNames {
+			marker := "  "
+			if name == m.theme.name {
+				marker = m.theme.accent.Render("* ")
+			}
+			fmt.Fprintf(&b, "%s%-10s %s\n", marker, name, m.theme.swatch(palettes[name]))
+		}
+		b.WriteString("\nUse 'theme <name>' to switch, or 'theme auto' to match your terminal.")
+		return b.String(), nil
+	case "auto":
+		m.theme = detectTheme(m.theme.renderer)
+		return "Switched to the " + m.theme.name + " theme to match your terminal.", nil
+	default:
+		return fmt.Sprintf("theme: unknown theme %q, try one of: %s", in.args, strings.Join(themeNames, ", ")), nil
+	}
+}
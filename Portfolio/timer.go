@@ -0,0 +1,295 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	pomodoroFocus = 25 * time.Minute
+	pomodoroBreak = 5 * time.Minute
+	timerMax      = 24 * time.Hour
+)
+
+// timerTickMsg updates the running timer. The id ties it to one timer, so
+// ticks from a stopped or replaced timer are ignored.
+type timerTickMsg struct{ id int64 }
+
+// timerStopMsg stops the timer with the given id.
+type timerStopMsg struct{ id int64 }
+
+var timers atomic.Int64
+
+// countdown is a timer counting down to end. It keeps running while the
+// visitor uses other commands, with the time left shown in the prompt.
+type countdown struct {
+	id       int64
+	icon     string // shown in front of the time left in the prompt
+	label    string
+	done     string // printed when the time is up
+	duration time.Duration
+	end      time.Time
+	next     *countdown // started when this one is up, like a pomodoro's break
+}
+
+func newCountdown(icon, label, done string, d time.Duration) *countdown {
+	return &countdown{id: timers.Add(1), icon: icon, label: label, done: done, duration: d}
+}
+
+// start sets the countdown going from now.
+func (c *countdown) start() tea.Cmd {
+	c.end = time.Now().Add(c.duration)
+	return c.tick()
+}
+
+func (c *countdown) remaining() time.Duration {
+	if left := time.Until(c.end); left > 0 {
+		return left
+	}
+	return 0
+}
+
+// tick waits until the displayed time next changes, so it changes on the second.
+func (c *countdown) tick() tea.Cmd {
+	id := c.id
+	wait := c.remaining() % time.Second
+	if wait == 0 {
+		wait = time.Second
+	}
+	return tea.Tick(wait, func(time.Time) tea.Msg { return timerTickMsg{id: id} })
+}
+
+// clock formats a duration as m:ss, or h:mm:ss from an hour up. Partial
+// seconds round up, so a timer shows 0:00 only once it's actually done.
+func clock(d time.Duration) string {
+	s := int((d + time.Second - 1) / time.Second)
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
+
+// parseTimerDuration reads a number of minutes, or a duration like 90s or 1h30m.
+func parseTimerDuration(s string) (time.Duration, bool) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		minutes, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, false
+		}
+		d = time.Duration(minutes * float64(time.Minute))
+	}
+	return d, d >= time.Second && d <= timerMax
+}
+
+func timerCommand(m *model, in commandInput) (string, tea.Cmd) {
+	switch in.args {
+	case "":
+		if m.timer == nil {
+			return m.tr("timer usage"), nil
+		}
+		if !in.toTerminal {
+			return m.tr("timer left", m.timer.label, clock(m.timer.remaining())), nil
+		}
+		return "", m.startApp(&timerApp{theme: m.theme, tr: m.tr, timer: m.timer, width: m.width})
+	case "stop":
+		return m.stopTimer(), nil
+	}
+	d, ok := parseTimerDuration(in.args)
+	if !ok {
+		return m.tr("timer duration"), nil
+	}
+	label := m.tr("timer label", clock(d))
+	c := newCountdown("⏱", label, m.tr("timer done", label), d)
+	return m.startTimer(c, in.toTerminal)
+}
+
+func pomodoroCommand(m *model, in commandInput) (string, tea.Cmd) {
+	switch in.args {
+	case "":
+	case "stop":
+		return m.stopTimer(), nil
+	default:
+		return m.tr("pomodoro usage"), nil
+	}
+	c := newCountdown("🍅", m.tr("pomodoro focus"), m.tr("focus done"), pomodoroFocus)
+	c.next = newCountdown("☕", m.tr("pomodoro break"), m.tr("break done"), pomodoroBreak)
+	return m.startTimer(c, in.toTerminal)
+}
+
+// startTimer replaces any running timer with c, showing it full screen when
+// the output is going to the terminal.
+func (m *model) startTimer(c *countdown, toTerminal bool) (string, tea.Cmd) {
+	m.timer = c
+	tick := c.start()
+	if !toTerminal {
+		return m.tr("timer started", c.label, clock(c.duration)), tick
+	}
+	return "", tea.Batch(tick, m.startApp(&timerApp{theme: m.theme, tr: m.tr, timer: c, width: m.width}))
+}
+
+func (m *model) stopTimer() string {
+	if m.timer == nil {
+		return m.tr("timer none")
+	}
+	label := m.timer.label
+	m.timer = nil
+	return m.tr("timer stopped", label)
+}
+
+// updateTimer moves the timer on, ringing the bell once it's up.
+func (m model) updateTimer(msg timerTickMsg) (tea.Model, tea.Cmd) {
+	c := m.timer
+	if c == nil || c.id != msg.id {
+		return m, nil
+	}
+	if c.remaining() > 0 {
+		return m, c.tick()
+	}
+
+	m.timer = c.next
+	m.alarm = c.done
+	m.lastInput = time.Now()
+	m.print(c.done)
+	cmds := []tea.Cmd{ringBell(m.out)}
+	if m.timer != nil {
+		cmds = append(cmds, m.timer.start())
+	}
+	if app, ok := m.app.(*timerApp); ok {
+		app.timer, app.rang = m.timer, c
+	} else if m.app == nil {
+		m.refreshViewport()
+		m.followOutput()
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// ringBell sends the terminal bell, which many terminals show as a flash.
+func ringBell(out io.Writer) tea.Cmd {
+	if out == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		fmt.Fprint(out, "\a")
+		return nil
+	}
+}
+
+// timerPrompt is the time left, shown in front of the prompt.
+func (m model) timerPrompt() string {
+	if m.timer == nil {
+		return ""
+	}
+	return m.theme.warning.Render(m.timer.icon+" "+clock(m.timer.remaining())) + " "
+}
+
+// alarmFooter is the visual bell: it stays at the bottom of the screen after
+// a timer rings, until a key is pressed.
+func (m model) alarmFooter() string {
+	if m.alarm == "" {
+		return ""
+	}
+	style := m.theme.style().Bold(true).Foreground(m.theme.colors.onAccent).Background(m.theme.colors.warning)
+	return style.Render(" " + m.alarm + " ")
+}
+
+// bigDigits are the characters the timer draws large, five rows tall.
+var bigDigits = map[rune][]string{
+	'0': {"█████", "█   █", "█   █", "█   █", "█████"},
+	'1': {"  █  ", " ██  ", "  █  ", "  █  ", " ███ "},
+	'2': {"█████", "    █", "█████", "█    ", "█████"},
+	'3': {"█████", "    █", " ████", "    █", "█████"},
+	'4': {"█   █", "█   █", "█████", "    █", "    █"},
+	'5': {"█████", "█    ", "█████", "    █", "█████"},
+	'6': {"█████", "█    ", "█████", "█   █", "█████"},
+	'7': {"█████", "    █", "   █ ", "  █  ", "  █  "},
+	'8': {"█████", "█   █", "█████", "█   █", "█████"},
+	'9': {"█████", "█   █", "█████", "    █", "█████"},
+	':': {" ", "█", " ", "█", " "},
+}
+
+// bigText draws s with bigDigits, one space between characters.
+func bigText(s string) string {
+	rows := make([]string, 5)
+	for i, r := range s {
+		glyph := bigDigits[r]
+		for row := range rows {
+			if i > 0 {
+				rows[row] += " "
+			}
+			rows[row] += glyph[row]
+		}
+	}
+	return strings.Join(rows, "\n")
+}
+
+// timerApp shows the running timer full screen. Leaving it doesn't stop the
+// timer, which carries on in the prompt.
+type timerApp struct {
+	theme *theme
+	tr    func(id string, args ...any) string
+	timer *countdown // nil once the timer is up
+	rang  *countdown // the countdown that just finished, if any
+	width int
+}
+
+func (t *timerApp) Init() tea.Cmd {
+	return nil
+}
+
+func (t *timerApp) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		t.width = msg.Width
+	case tea.KeyMsg:
+		if t.timer == nil {
+			return t, exitApp("", 0, "")
+		}
+		switch msg.String() {
+		case "x":
+			id := t.timer.id
+			stop := func() tea.Msg { return timerStopMsg{id: id} }
+			return t, tea.Batch(stop, exitApp("", 0, t.tr("timer stopped", t.timer.label)))
+		case "q", "b", "esc", "ctrl+c":
+			return t, exitApp("", 0, "")
+		}
+	}
+	return t, nil
+}
+
+func (t *timerApp) View() string {
+	th := t.theme
+	var b strings.Builder
+	if t.timer == nil {
+		b.WriteString(th.warning.Render(bigText("0:00")) + "\n\n")
+		b.WriteString(th.accent.Render(t.rang.done) + "\n\n")
+		b.WriteString(th.muted.Render(t.tr("timer return")))
+		return b.String()
+	}
+
+	c := t.timer
+	if t.rang != nil {
+		// A pomodoro moved on to its break
+		b.WriteString(th.success.Render(t.rang.done) + "\n\n")
+	}
+	b.WriteString(th.header.Render(c.icon+" "+c.label) + "\n\n")
+	left := clock(c.remaining())
+	if big := bigText(left); t.width == 0 || len([]rune(strings.Split(big, "\n")[0])) <= t.width {
+		b.WriteString(th.accent.Render(big) + "\n\n")
+	} else {
+		b.WriteString(th.accent.Render(left) + "\n\n")
+	}
+
+	// How much of the time has passed
+	width := max(10, min(40, t.width-2))
+	done := width - int(float64(width)*float64(c.remaining())/float64(c.duration))
+	b.WriteString(th.accent.Render(strings.Repeat("█", done)) + th.subtle.Render(strings.Repeat("░", width-done)) + "\n\n")
+	b.WriteString(th.muted.Render(t.tr("timer keys")))
+	return b.String()
+}
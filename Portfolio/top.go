@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	topInterval = time.Second
+	// topHistory is how many samples the graphs show, a minute's worth
+	topHistory = 60
+)
+
+// topTickMsg refreshes top. Like snake, the id keeps ticks from a closed
+// monitor from speeding up the next one.
+type topTickMsg struct{ id int64 }
+
+var monitors atomic.Int64
+
+// topModel shows the Go runtime's stats for the running server, refreshed
+// every second.
+type topModel struct {
+	id         int64
+	theme      *theme
+	mem        runtime.MemStats
+	goroutines int
+	heap       []float64 // recent heap sizes, oldest first
+	routines   []float64 // recent goroutine counts, oldest first
+}
+
+func topCommand(m *model, in commandInput) (string, tea.Cmd) {
+	t := &topModel{id: monitors.Add(1), theme: m.theme}
+	t.sample()
+	return "", m.startApp(t)
+}
+
+func (t *topModel) tick() tea.Cmd {
+	id := t.id
+	return tea.Tick(topInterval, func(time.Time) tea.Msg { return topTickMsg{id: id} })
+}
+
+// sample reads the runtime's stats and adds them to the graphs.
+func (t *topModel) sample() {
+	runtime.ReadMemStats(&t.mem)
+	t.goroutines = runtime.NumGoroutine()
+	t.heap = append(t.heap, float64(t.mem.HeapAlloc))
+	t.routines = append(t.routines, float64(t.goroutines))
+	if len(t.heap) > topHistory {
+		t.heap, t.routines = t.heap[1:], t.routines[1:]
+	}
+}
+
+func (t *topModel) Init() tea.Cmd {
+	return t.tick()
+}
+
+func (t *topModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return t, exitApp("", 0, "")
+		}
+	case topTickMsg:
+		if msg.id != t.id {
+			return t, nil
+		}
+		t.sample()
+		return t, t.tick()
+	}
+	return t, nil
+}
+
+func (t *topModel) View() string {
+	th := t.theme
+	row := func(label, value string) string {
+		return th.muted.Render(fmt.Sprintf("%-14s", label)) + value + "\n"
+	}
+
+	var b strings.Builder
+	b.WriteString(th.header.Render("fred-cli top") + th.muted.Render(" · refreshing every second") + "\n\n")
+	b.WriteString(row("Uptime", time.Since(processStarted).Round(time.Second).String()))
+	b.WriteString(row("Sessions", fmt.Sprintf("%d of %d", sessions.active(), maxSessions)))
+	b.WriteString(row("Go", fmt.Sprintf("%s on %s/%s, %d CPUs", runtime.Version(), runtime.GOOS, runtime.GOARCH, runtime.NumCPU())))
+	b.WriteString("\n")
+
+	b.WriteString(row("Goroutines", fmt.Sprintf("%d", t.goroutines)))
+	b.WriteString(row("", th.info.Render(sparkline(t.routines, topHistory))))
+	b.WriteString(row("Heap in use", formatBytes(t.mem.HeapAlloc)+th.muted.Render(" of "+formatBytes(t.mem.HeapSys)+" reserved")))
+	b.WriteString(row("", th.success.Render(sparkline(t.heap, topHistory))))
+	b.WriteString(row("Total alloc", formatBytes(t.mem.TotalAlloc)))
+	b.WriteString(row("From the OS", formatBytes(t.mem.Sys)))
+	b.WriteString("\n")
+
+	b.WriteString(row("GC cycles", fmt.Sprintf("%d", t.mem.NumGC)))
+	if t.mem.NumGC > 0 {
+		// PauseNs is a ring buffer of the most recent pauses
+		last := time.Duration(t.mem.PauseNs[(t.mem.NumGC+255)%256])
+		b.WriteString(row("Last GC pause", last.String()))
+		b.WriteString(row("Total paused", time.Duration(t.mem.PauseTotalNs).String()))
+		b.WriteString(row("Last GC", time.Since(time.Unix(0, int64(t.mem.LastGC))).Round(time.Second).String()+" ago"))
+	}
+	b.WriteString("\n" + th.muted.Render("Press q to return to the shell"))
+	return b.String()
+}
+
+// formatBytes shows a size in the largest unit that keeps it above one.
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
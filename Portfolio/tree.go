@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	treeDefaultDepth = 2
+	treeMaxDepth     = 5   // deepest level a visitor can ask for
+	treeMaxEntries   = 200 // stop listing after this many entries
+)
+
+func treeCommand(m *model, in commandInput) (string, tea.Cmd) {
+	depth := treeDefaultDepth
+	if in.args != "" {
+		n, err := strconv.Atoi(in.args)
+		if err != nil || n < 1 {
+			return m.tr("tree usage"), nil
+		}
+		depth = min(n, treeMaxDepth)
+	}
+
+	t := treeWalker{m: m, maxDepth: depth, styled: in.toTerminal}
+	t.b.WriteString(m.theme.folder.Render("📁 "+m.displayDirectory()) + "\n")
+	t.walk(".", "", 1)
+
+	if t.truncated {
+		fmt.Fprintf(&t.b, "\n(output truncated after %d entries)", treeMaxEntries)
+	}
+	fmt.Fprintf(&t.b, "\n%d directories, %d files", t.dirs, t.files)
+	return t.b.String(), nil
+}
+
+// treeWalker accumulates the rendered tree and the counts shown below it.
+type treeWalker struct {
+	m         *model
+	b         strings.Builder
+	maxDepth  int
+	styled    bool
+	dirs      int
+	files     int
+	truncated bool
+}
+
+func (t *treeWalker) walk(dir, indent string, depth int) {
+	entries, err := t.m.readDir(dir)
+	if err != nil {
+		return
+	}
+	for i, entry := range entries {
+		if t.dirs+t.files >= treeMaxEntries {
+			t.truncated = true
+			return
+		}
+
+		branch, childIndent := "├── ", "│   "
+		if i == len(entries)-1 {
+			branch, childIndent = "└── ", "    "
+		}
+
+		name := "📄 " + entry.name
+		style := t.m.theme.file
+		if entry.isDir {
+			name = "📁 " + entry.name
+			style = t.m.theme.folder
+			t.dirs++
+		} else {
+			t.files++
+		}
+
+		if t.styled {
+			t.b.WriteString(t.m.theme.subtle.Render(indent+branch) + style.Render(name) + "\n")
+		} else {
+			t.b.WriteString(indent + branch + entry.name + "\n")
+		}
+
+		if entry.isDir && depth < t.maxDepth {
+			t.walk(path.Join(dir, entry.name), indent+childIndent, depth+1)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var typetestParagraphs = []string{
+	"The quick brown fox jumps over the lazy dog while the curious cat watches from the windowsill, wondering why anyone would jump over a dog at all.",
+	"Go was designed at Google to make building simple, reliable and efficient software easy. Its goroutines and channels make concurrent programs feel natural to write.",
+	"Every game starts as a tiny prototype. You move a square around the screen, add a little jump, and suddenly you have spent the whole weekend tuning gravity.",
+	"Terminals have been around for decades, yet they are still one of the fastest ways to get work done. A good command line tool feels like a superpower.",
+	"Godot lets you build a scene out of small reusable nodes. Once you get used to signals, wiring a user interface to your game logic becomes surprisingly pleasant.",
+	"Coffee in one hand and a keyboard under the other, the developer stared at the failing test and realised the bug had been a missing semicolon all along.",
+}
+
+type typetestModel struct {
+	theme    *theme
+	target   []rune
+	typed    []rune
+	start    time.Time
+	elapsed  time.Duration
+	finished bool
+	width    int
+}
+
+func typetestCommand(m *model, in commandInput) (string, tea.Cmd) {
+	return "", m.startApp(newTypetest(m.width, m.theme))
+}
+
+func newTypetest(width int, th *theme) *typetestModel {
+	t := &typetestModel{theme: th, width: width}
+	t.reset()
+	return t
+}
+
+func (t *typetestModel) reset() {
+	t.target = []rune(typetestParagraphs[rand.Intn(len(typetestParagraphs))])
+	t.typed = nil
+	t.start = time.Time{}
+	t.elapsed = 0
+	t.finished = false
+}
+
+// correct counts how many typed characters match the paragraph.
+func (t *typetestModel) correct() int {
+	n := 0
+	for i, r := range t.typed {
+		if r == t.target[i] {
+			n++
+		}
+	}
+	return n
+}
+
+// wpm uses the standard definition of a word as five characters, counting
+// only characters typed correctly.
+func (t *typetestModel) wpm() int {
+	minutes := t.elapsed.Minutes()
+	if minutes == 0 {
+		return 0
+	}
+	return int(float64(t.correct()) / 5 / minutes)
+}
+
+func (t *typetestModel) accuracy() int {
+	if len(t.typed) == 0 {
+		return 0
+	}
+	return t.correct() * 100 / len(t.typed)
+}
+
+func (t *typetestModel) Init() tea.Cmd {
+	return nil
+}
+
+func (t *typetestModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		t.width = msg.Width
+	case tea.KeyMsg:
+		if t.finished {
+			switch msg.String() {
+			case "r":
+				t.reset()
+			case "q", "esc", "enter":
+				result := fmt.Sprintf("Typing test: %d WPM with %d%% accuracy", t.wpm(), t.accuracy())
+				return t, exitApp("typetest", t.wpm(), result)
+			}
+			return t, nil
+		}
+
+		switch msg.Type {
+		case tea.KeyEsc, tea.KeyCtrlC:
+			return t, exitApp("", 0, "Typing test cancelled.")
+		case tea.KeyBackspace:
+			if len(t.typed) > 0 {
+				t.typed = t.typed[:len(t.typed)-1]
+			}
+		case tea.KeySpace:
+			t.typeRunes([]rune{' '})
+		case tea.KeyRunes:
+			t.typeRunes(msg.Runes)
+		}
+	}
+	return t, nil
+}
+
+func (t *typetestModel) typeRunes(runes []rune) {
+	// The clock starts on the first keystroke, not when the paragraph appears
+	if t.start.IsZero() {
+		t.start = time.Now()
+	}
+	for _, r := range runes {
+		if len(t.typed) < len(t.target) {
+			t.typed = append(t.typed, r)
+		}
+	}
+	if len(t.typed) == len(t.target) {
+		t.elapsed = time.Since(t.start)
+		t.finished = true
+	}
+}
+
+func (t *typetestModel) View() string {
+	title := t.theme.header.Render("⌨️  Typing Test")
+	if t.finished {
+		results := fmt.Sprintf("Speed:     %d WPM\nAccuracy:  %d%%\nTime:      %.1fs\n\n%s",
+			t.wpm(), t.accuracy(), t.elapsed.Seconds(),
+			t.theme.muted.Render("r to try another paragraph • q to return to the shell"))
+		box := t.theme.style().Border(lipgloss.RoundedBorder()).BorderForeground(t.theme.colors.accent).Padding(1, 3)
+		return lipgloss.JoinVertical(lipgloss.Left, title, "", box.Render(results))
+	}
+
+	var b strings.Builder
+	for i, r := range t.target {
+		switch {
+		case i < len(t.typed) && t.typed[i] == r:
+			b.WriteString(t.theme.success.Render(string(r)))
+		case i < len(t.typed):
+			b.WriteString(t.theme.danger.Underline(true).Render(string(r)))
+		case i == len(t.typed):
+			b.WriteString(t.theme.style().Reverse(true).Render(string(r)))
+		default:
+			b.WriteString(t.theme.muted.Render(string(r)))
+		}
+	}
+	paragraph := t.theme.style().Width(max(20, min(70, t.width-4))).Render(b.String())
+	help := t.theme.muted.Render("Start typing to begin the clock • esc to cancel")
+	return lipgloss.JoinVertical(lipgloss.Left, title, "", paragraph, "", help)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// processStarted is when the portfolio started, for its uptime.
+var processStarted = time.Now()
+
+// humanDuration spells out d in its two largest units, like "3 days, 4 hours".
+func humanDuration(d time.Duration) string {
+	units := []struct {
+		name string
+		size time.Duration
+	}{
+		{"day", 24 * time.Hour},
+		{"hour", time.Hour},
+		{"minute", time.Minute},
+		{"second", time.Second},
+	}
+	var parts []string
+	for _, u := range units {
+		n := int(d / u.size)
+		// Start at the first unit there is any of, but always say something
+		if n == 0 && len(parts) == 0 && u.size > time.Second {
+			continue
+		}
+		d -= time.Duration(n) * u.size
+		parts = append(parts, plural(n, u.name))
+		if len(parts) == 2 {
+			break
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// plural returns n and the noun, with an s unless n is one.
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+func uptimeCommand(m *model, in commandInput) (string, tea.Cmd) {
+	row := func(label, value string) string {
+		return m.theme.muted.Render(fmt.Sprintf("%-14s", label)) + value + "\n"
+	}
+	var b strings.Builder
+	b.WriteString(row("Server up", humanDuration(time.Since(processStarted))+", since "+processStarted.Format("Jan 2 15:04 MST")))
+	b.WriteString(row("Your session", humanDuration(time.Since(m.connected))+", "+plural(m.commandsRun, "command")+" run"))
+	// Only the SSH server counts visitors
+	if n := sessions.active(); n > 0 {
+		b.WriteString(row("Visitors", fmt.Sprintf("%d online, %d at most", n, maxSessions)))
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
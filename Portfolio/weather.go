@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Weather comes from open-meteo, which doesn't need an API key.
+const (
+	weatherGeocodeURL  = "https://geocoding-api.open-meteo.com/v1/search"
+	weatherForecastURL = "https://api.open-meteo.com/v1/forecast"
+)
+
+// weatherCondition is how a group of WMO weather codes is shown.
+type weatherCondition struct {
+	description string
+	icon        []string
+	color       func(palette) lipgloss.Color // picks the icon's colour from the theme
+}
+
+func sunColor(p palette) lipgloss.Color   { return p.warning }
+func cloudColor(p palette) lipgloss.Color { return p.muted }
+func rainColor(p palette) lipgloss.Color  { return p.info }
+
+var (
+	weatherClear = weatherCondition{"Clear sky", []string{
+		"    \\   /    ",
+		"     .-.     ",
+		"  ― (   ) ―  ",
+		"     `-'     ",
+		"    /   \\    ",
+	}, sunColor}
+	weatherPartlyCloudy = weatherCondition{"Partly cloudy", []string{
+		"   \\  /      ",
+		" _ /\"\".-.    ",
+		"   \\_(   ).  ",
+		"   /(___(__) ",
+		"             ",
+	}, sunColor}
+	weatherCloudy = weatherCondition{"Cloudy", []string{
+		"             ",
+		"     .--.    ",
+		"  .-(    ).  ",
+		" (___.__)__) ",
+		"             ",
+	}, cloudColor}
+	weatherFog = weatherCondition{"Fog", []string{
+		"             ",
+		" _ - _ - _ - ",
+		"  _ - _ - _  ",
+		" _ - _ - _ - ",
+		"             ",
+	}, cloudColor}
+	weatherRain = weatherCondition{"Rain", []string{
+		"     .-.     ",
+		"    (   ).   ",
+		"   (___(__)  ",
+		"    ' ' ' '  ",
+		"   ' ' ' '   ",
+	}, rainColor}
+	weatherSnow = weatherCondition{"Snow", []string{
+		"     .-.     ",
+		"    (   ).   ",
+		"   (___(__)  ",
+		"    *  *  *  ",
+		"   *  *  *   ",
+	}, cloudColor}
+	weatherThunder = weatherCondition{"Thunderstorm", []string{
+		"     .-.     ",
+		"    (   ).   ",
+		"   (___(__)  ",
+		"    ⚡' '⚡'  ",
+		"    ' ' ' '  ",
+	}, rainColor}
+)
+
+// conditionFor maps a WMO weather interpretation code to a condition.
+func conditionFor(code int) weatherCondition {
+	switch {
+	case code == 0:
+		return weatherClear
+	case code <= 2:
+		return weatherPartlyCloudy
+	case code == 3:
+		return weatherCloudy
+	case code == 45 || code == 48:
+		return weatherFog
+	case code >= 71 && code <= 77, code == 85, code == 86:
+		return weatherSnow
+	case code >= 95:
+		return weatherThunder
+	default:
+		// Drizzle (51-57), rain (61-67) and showers (80-82)
+		return weatherRain
+	}
+}
+
+// fetchWeather looks up city and renders its current conditions.
+func fetchWeather(city string, t *theme) (string, error) {
+	var places struct {
+		Results []struct {
+			Name      string  `json:"name"`
+			Country   string  `json:"country"`
+			Latitude  float64 `json:"latitude"`
+			Longitude float64 `json:"longitude"`
+		} `json:"results"`
+	}
+	if err := getJSON(weatherGeocodeURL+"?count=1&name="+url.QueryEscape(city), &places); err != nil {
+		return "", err
+	}
+	if len(places.Results) == 0 {
+		return "", fmt.Errorf("couldn't find a place called %q", city)
+	}
+	place := places.Results[0]
+
+	var forecast struct {
+		Current struct {
+			Temperature float64 `json:"temperature_2m"`
+			Humidity    float64 `json:"relative_humidity_2m"`
+			WindSpeed   float64 `json:"wind_speed_10m"`
+			WeatherCode int     `json:"weather_code"`
+		} `json:"current"`
+	}
+	query := fmt.Sprintf("?latitude=%.4f&longitude=%.4f&current=temperature_2m,relative_humidity_2m,wind_speed_10m,weather_code",
+		place.Latitude, place.Longitude)
+	if err := getJSON(weatherForecastURL+query, &forecast); err != nil {
+		return "", err
+	}
+
+	current := forecast.Current
+	condition := conditionFor(current.WeatherCode)
+	name := place.Name
+	if place.Country != "" {
+		name += ", " + place.Country
+	}
+	details := strings.Join([]string{
+		t.header.Render(name),
+		condition.description,
+		t.muted.Render("Temperature: ") + fmt.Sprintf("%.1f°C", current.Temperature),
+		t.muted.Render("Wind:        ") + fmt.Sprintf("%.1f km/h", current.WindSpeed),
+		t.muted.Render("Humidity:    ") + fmt.Sprintf("%.0f%%", current.Humidity),
+	}, "\n")
+	icon := t.style().Foreground(condition.color(t.colors)).Render(strings.Join(condition.icon, "\n"))
+	return lipgloss.JoinHorizontal(lipgloss.Top, icon, "  ", details), nil
+}
+
+// weatherResult returns the forecast for city, or a readable error in the
+// language of tr.
+func weatherResult(city string, t *theme, tr func(string, ...any) string) string {
+	out, err := fetchWeather(city, t)
+	if err != nil {
+		return tr("weather error", describeFetchError(err))
+	}
+	return out
+}
+
+func weatherCommand(m *model, in commandInput) (string, tea.Cmd) {
+	if in.args == "" {
+		return m.tr("weather usage"), nil
+	}
+	// A pipeline needs the output straight away, so there's nothing to animate
+	if !in.toTerminal {
+		return weatherResult(in.args, m.theme, m.tr), nil
+	}
+	city, t, tr := in.args, m.theme, m.tr
+	return "", m.startApp(newLoader(t, tr("weather loading", city), func() string {
+		return weatherResult(city, t, tr)
+	}))
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/log"
+	"github.com/muesli/termenv"
+	"golang.org/x/net/websocket"
+)
+
+// The web terminal lets visitors without an SSH client try the portfolio in
+// their browser. The page runs xterm.js and connects to /ws, where the same
+// Bubble Tea program as over SSH runs with the WebSocket as its terminal.
+//
+//go:embed Extra/terminal.html
+var terminalPage []byte
+
+// webMessage is sent by the page: a visitor's keystrokes, or their terminal's
+// new size. Output goes the other way as raw binary frames.
+type webMessage struct {
+	Type string `json:"type"` // "input" or "resize"
+	Data string `json:"data"`
+	Cols int    `json:"cols"`
+	Rows int    `json:"rows"`
+}
+
+// startWeb serves the web terminal on addr in the background.
+func startWeb(addr string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(terminalPage)
+	})
+	mux.Handle("/ws", websocket.Handler(serveTerminal))
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	log.Info("Starting web terminal", "address", addr)
+	ln, err := listen("web", addr)
+	if err != nil {
+		log.Error("Could not start web terminal", "error", err)
+		return
+	}
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) && !closedListener(err) {
+			log.Error("Could not start web terminal", "error", err)
+		}
+	}()
+}
+
+// serveTerminal runs a session for one browser tab until either side hangs up.
+func serveTerminal(ws *websocket.Conn) {
+	defer ws.Close()
+	ip, _, err := net.SplitHostPort(ws.Request().RemoteAddr)
+	if err != nil {
+		ip = ws.Request().RemoteAddr
+	}
+	// Browser visitors count towards the same caps as SSH ones
+	if reason, ok := sessions.acquire(ip); !ok {
+		log.Warn("Rejecting web session", "ip", ip, "reason", reason)
+		io.WriteString(ws, "Too many visitors right now, please try again later.\r\n")
+		return
+	}
+	defer sessions.release(ip)
+
+	q := ws.Request().URL.Query()
+	width, _ := strconv.Atoi(q.Get("cols"))
+	height, _ := strconv.Atoi(q.Get("rows"))
+	env := []string{"LANG=" + strings.ReplaceAll(q.Get("lang"), "-", "_"), "TZ=" + q.Get("tz")}
+
+	ws.PayloadType = websocket.BinaryFrame
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := lipgloss.NewRenderer(ws, termenv.WithProfile(termenv.TrueColor))
+	r.SetHasDarkBackground(true) // the page is dark
+	m := newVisitorModel(visitor{
+		transport:  "web",
+		renderer:   r,
+		env:        env,
+		ip:         ip,
+		width:      width,
+		height:     height,
+		out:        ws,
+		done:       ctx.Done(),
+		hyperlinks: true, // xterm.js understands OSC 8 links
+	})
+
+	input, keys := io.Pipe()
+	p := tea.NewProgram(m,
+		tea.WithInput(input),
+		tea.WithOutput(ws),
+		tea.WithContext(ctx),
+		tea.WithoutSignalHandler(),
+		tea.WithAltScreen(),
+		tea.WithMouseCellMotion(),
+	)
+	go func() {
+		// There's no PTY to ask for the size, so the page sends it
+		p.Send(tea.WindowSizeMsg{Width: width, Height: height})
+		for {
+			var msg webMessage
+			if err := websocket.JSON.Receive(ws, &msg); err != nil {
+				cancel()
+				keys.Close()
+				return
+			}
+			switch msg.Type {
+			case "input":
+				io.WriteString(keys, msg.Data)
+			case "resize":
+				if msg.Cols > 0 && msg.Rows > 0 {
+					p.Send(tea.WindowSizeMsg{Width: msg.Cols, Height: msg.Rows})
+				}
+			}
+		}
+	}()
+	if _, err := p.Run(); err != nil && !errors.Is(err, tea.ErrProgramKilled) {
+		m.logger.Error("Web session failed", "error", err)
+	}
+	// Nothing reads the keys once the session is over, so unblock the
+	// goroutine above if it's writing one, and hang up so it stops waiting
+	// for the next
+	keys.CloseWithError(io.ErrClosedPipe)
+	ws.Close()
+}
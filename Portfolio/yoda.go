@@ -0,0 +1,180 @@
+package main
+
+import (
+	"math/rand"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Yoda puts the object of a sentence first and its subject and verb last:
+// "I am learning Go" becomes "Learning Go, I am." The rules only cover
+// common sentence shapes; anything else is left as it was.
+
+// yodaSentence matches a sentence and the punctuation ending it.
+var yodaSentence = regexp.MustCompile(`[^.!?]+[.!?]*`)
+
+// yodaContractions are expanded before reordering, so "I'm" can be split
+// into its subject and verb.
+var yodaContractions = map[string]string{
+	"i'm": "I am", "you're": "you are", "we're": "we are", "they're": "they are",
+	"he's": "he is", "she's": "she is", "it's": "it is", "that's": "that is",
+	"i've": "I have", "you've": "you have", "we've": "we have", "they've": "they have",
+	"i'll": "I will", "you'll": "you will", "we'll": "we will", "they'll": "they will",
+	"he'll": "he will", "she'll": "she will", "it'll": "it will",
+	"i'd": "I would", "you'd": "you would", "we'd": "we would", "they'd": "they would",
+	"don't": "do not", "doesn't": "does not", "didn't": "did not",
+	"isn't": "is not", "aren't": "are not", "wasn't": "was not", "weren't": "were not",
+	"can't": "can not", "won't": "will not", "shouldn't": "should not",
+	"couldn't": "could not", "wouldn't": "would not", "mustn't": "must not",
+	"haven't": "have not", "hasn't": "has not", "hadn't": "had not",
+}
+
+// yodaPronouns can be the subject of a sentence on their own.
+var yodaPronouns = map[string]bool{
+	"i": true, "you": true, "we": true, "they": true, "he": true, "she": true, "it": true,
+}
+
+// yodaDeterminers can start a longer subject, like "the force".
+var yodaDeterminers = map[string]bool{
+	"the": true, "a": true, "an": true, "my": true, "your": true, "our": true,
+	"their": true, "his": true, "her": true, "its": true, "this": true, "that": true,
+}
+
+// yodaAuxiliaries are the verbs that go to the end with the subject.
+var yodaAuxiliaries = map[string]bool{
+	"am": true, "is": true, "are": true, "was": true, "were": true,
+	"will": true, "can": true, "must": true, "should": true, "could": true,
+	"would": true, "shall": true, "may": true, "might": true,
+	"have": true, "has": true, "had": true, "do": true, "does": true, "did": true,
+}
+
+// yodaEndings are added after the last sentence, picked at random.
+var yodaEndings = []string{"", " Hmm.", " Yes, hmmm.", " Mmm.", " Herh herh herh."}
+
+// yodaSpeak rearranges every sentence in text, then adds one of Yoda's noises
+// chosen by rng.
+func yodaSpeak(text string, rng *rand.Rand) string {
+	var sentences []string
+	for _, s := range yodaSentence.FindAllString(text, -1) {
+		if s = strings.TrimSpace(s); s != "" {
+			sentences = append(sentences, yodaReorder(s))
+		}
+	}
+	return strings.Join(sentences, " ") + yodaEndings[rng.Intn(len(yodaEndings))]
+}
+
+// yodaReorder rearranges one sentence, keeping the punctuation at its end.
+func yodaReorder(sentence string) string {
+	body := strings.TrimRight(sentence, ".!?")
+	end := sentence[len(body):]
+	if end == "" {
+		end = "."
+	}
+	words := yodaExpand(strings.Fields(body))
+	if len(words) < 2 {
+		return sentence
+	}
+
+	var front, back []string
+	if end == "?" {
+		// "Are you ready?" becomes "Ready, are you?"
+		if len(words) > 2 && yodaAuxiliaries[strings.ToLower(words[0])] && yodaPronouns[strings.ToLower(words[1])] {
+			front, back = words[2:], words[:2]
+		}
+	} else if k := yodaSubjectEnd(words); k > 0 && k < len(words)-1 {
+		verb := k + 1
+		if yodaAuxiliaries[strings.ToLower(words[k])] && strings.ToLower(words[verb]) == "not" && verb+1 < len(words) {
+			// "I am not ready" becomes "Ready, I am not."
+			verb++
+		}
+		if yodaAuxiliaries[strings.ToLower(words[k])] || k == 1 {
+			front, back = words[verb:], words[:verb]
+		}
+	}
+	if len(front) == 0 {
+		return sentence
+	}
+
+	// Only words that were capitalized for starting the sentence are lowered
+	if w := back[0]; w != "I" && (yodaPronouns[strings.ToLower(w)] || yodaDeterminers[strings.ToLower(w)] || yodaAuxiliaries[strings.ToLower(w)]) {
+		back[0] = strings.ToLower(w)
+	}
+	front = append([]string(nil), front...)
+	front[0] = capitalize(front[0])
+	front[len(front)-1] = strings.TrimRight(front[len(front)-1], ",;:")
+	return strings.Join(front, " ") + ", " + strings.Join(back, " ") + end
+}
+
+// yodaSubjectEnd returns the index of the verb following the sentence's
+// subject: a pronoun, or a determiner and up to two more words before an
+// auxiliary. It returns -1 if the sentence doesn't start with a subject.
+func yodaSubjectEnd(words []string) int {
+	first := strings.ToLower(words[0])
+	if yodaPronouns[first] {
+		return 1
+	}
+	if !yodaDeterminers[first] {
+		return -1
+	}
+	for k := 2; k < len(words) && k <= 3; k++ {
+		if yodaAuxiliaries[strings.ToLower(words[k])] {
+			return k
+		}
+	}
+	return -1
+}
+
+// yodaExpand splits contractions into their words, keeping a capital letter.
+func yodaExpand(words []string) []string {
+	var out []string
+	for _, w := range words {
+		key := strings.ToLower(strings.ReplaceAll(w, "’", "'"))
+		trail := strings.TrimLeft(key, "abcdefghijklmnopqrstuvwxyz'")
+		expanded, ok := yodaContractions[strings.TrimSuffix(key, trail)]
+		if !ok {
+			out = append(out, w)
+			continue
+		}
+		if unicode.IsUpper([]rune(w)[0]) {
+			expanded = capitalize(expanded)
+		}
+		out = append(out, strings.Fields(expanded+trail)...)
+	}
+	return out
+}
+
+func capitalize(s string) string {
+	r := []rune(s)
+	if len(r) == 0 {
+		return s
+	}
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+func yodaCommand(m *model, in commandInput) (string, tea.Cmd) {
+	f, rest, err := parseFlags(in.argv, nil, []string{"seed"})
+	if err != nil {
+		return "yoda: " + err.Error() + "\n" + m.tr("yoda usage"), nil
+	}
+	text := strings.Join(rest, " ")
+	if text == "" {
+		text = strings.TrimSpace(in.stdin)
+	}
+	if text == "" {
+		return m.tr("yoda usage"), nil
+	}
+	rng := rand.New(rand.NewSource(rand.Int63()))
+	if f.has("seed") {
+		seed, err := strconv.ParseInt(f["seed"], 10, 64)
+		if err != nil {
+			return m.tr("yoda seed") + "\n" + m.tr("yoda usage"), nil
+		}
+		rng = rand.New(rand.NewSource(seed))
+	}
+	return m.tr("yoda says", yodaSpeak(text, rng)), nil
+}
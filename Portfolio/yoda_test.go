@@ -0,0 +1,95 @@
+package main
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestYodaReorder(t *testing.T) {
+	tests := []struct {
+		name     string
+		sentence string
+		want     string
+	}{
+		// Subject and auxiliary go last
+		{"pronoun and auxiliary", "I am learning Go.", "Learning Go, I am."},
+		{"modal", "You will go far.", "Go far, you will."},
+		{"determiner subject", "The force is strong with you.", "Strong with you, the force is."},
+		{"longer subject", "My old ship is fast.", "Fast, my old ship is."},
+		{"pronoun and verb", "I love cake.", "Cake, I love."},
+		{"not stays with the verb", "I am not ready.", "Ready, I am not."},
+		{"question", "Are you ready?", "Ready, are you?"},
+
+		// Contractions are expanded first
+		{"contraction", "I'm learning Go!", "Learning Go, I am!"},
+		{"contraction subject", "You're a good friend.", "A good friend, you are."},
+		{"negative contraction", "I don't like sand.", "Like sand, I do not."},
+		{"curly apostrophe", "He’s tall.", "Tall, he is."},
+		{"would", "I'd rather wait.", "Rather wait, I would."},
+		{"contraction and not", "She isn't done", "Done, she is not."},
+
+		// Punctuation is kept
+		{"missing full stop", "I am learning Go", "Learning Go, I am."},
+		{"ellipsis", "We can't stop here...", "Stop here, we can not..."},
+		{"trailing comma dropped", "It's over, Anakin!", "Over, Anakin, it is!"},
+		{"inner comma kept", "I am ready, yes.", "Ready, yes, I am."},
+
+		// Anything else is left alone
+		{"no subject", "Go is fun.", "Go is fun."},
+		{"one word", "Hello.", "Hello."},
+		{"other question", "What is this?", "What is this?"},
+		{"no verb", "Hello there, friend.", "Hello there, friend."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := yodaReorder(tt.sentence); got != tt.want {
+				t.Errorf("yodaReorder(%q) = %q, want %q", tt.sentence, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestYodaSpeak(t *testing.T) {
+	tests := []struct {
+		text string
+		seed int64
+		want string
+	}{
+		{"I am your father", 1, "Your father, I am. Hmm."},
+		{"I am tired", 4, "Tired, I am. Herh herh herh."},
+		{"I am learning Go. You're a good friend!", 1, "Learning Go, I am. A good friend, you are! Hmm."},
+		{"  Go is fun.   I love cake?  ", 1, "Go is fun. I love cake? Hmm."},
+	}
+	for _, tt := range tests {
+		if got := yodaSpeak(tt.text, rand.New(rand.NewSource(tt.seed))); got != tt.want {
+			t.Errorf("yodaSpeak(%q, seed %d) = %q, want %q", tt.text, tt.seed, got, tt.want)
+		}
+	}
+}
+
+func TestYodaCommand(t *testing.T) {
+	const yodaUsage = "Usage: yoda [--seed n] <text>"
+	tests := []struct {
+		name  string
+		argv  []string
+		stdin string
+		want  string
+	}{
+		{"seed", []string{"--seed", "1", "I", "am", "your", "father"}, "", "Yoda says: Your father, I am. Hmm."},
+		{"seed after text", []string{"I", "am", "tired", "--seed", "4"}, "", "Yoda says: Tired, I am. Herh herh herh."},
+		{"piped", []string{"--seed", "4"}, "I am tired\n", "Yoda says: Tired, I am. Herh herh herh."},
+		{"bad seed", []string{"--seed", "x", "hi"}, "", "yoda: the seed must be a whole number\n" + yodaUsage},
+		{"bad flag", []string{"--bad", "hi"}, "", "yoda: unknown flag -bad\n" + yodaUsage},
+		{"no text", []string{"--seed", "3"}, "", yodaUsage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestState(t)
+			in := commandInput{args: strings.Join(tt.argv, " "), argv: tt.argv, stdin: tt.stdin, piped: tt.stdin != ""}
+			if got, _ := yodaCommand(&m, in); got != tt.want {
+				t.Errorf("yoda %s = %q, want %q", strings.Join(tt.argv, " "), got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// Summaries and articles are cached on disk, so pages read recently load
+// without asking Wikipedia again, and old copies can stand in when
+// Wikipedia is slow, rate limiting or unreachable.
+
+// cacheTTL is how long a cached page is used before fetching it again.
+var cacheTTL = 24 * time.Hour
+
+const (
+	// cacheMaxAge is how long a stale copy is kept to fall back on.
+	cacheMaxAge = 30 * 24 * time.Hour
+	// cacheMaxBytes caps the cache's size on disk. The least recently
+	// fetched pages are dropped to stay under it.
+	cacheMaxBytes = 256 << 20
+	// cachePruneEvery is how often a server prunes the cache.
+	cachePruneEvery = time.Hour
+)
+
+// cacheEntry is a cached summary or article, as plain text.
+type cacheEntry struct {
+	Title   string    `json:"title"`
+	Lang    string    `json:"lang"`
+	Text    string    `json:"text"`
+	Fetched time.Time `json:"fetched"`
+}
+
+// cachePath returns where part ("summary" or "content") of the lang
+// Wikipedia's title page is cached. Titles are matched ignoring case, so
+// typing a title finds it offline.
+func cachePath(lang, title, part string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(title)))
+	return filepath.Join(dataDir(), "cache", lang, hex.EncodeToString(sum[:])+"."+part+".json")
+}
+
+// cached returns part of a page from the cache if it's fresh, or from fetch.
+// If fetch fails, a stale copy is returned instead of the error. The time is
+// when the text was fetched if it came from the cache, and zero otherwise.
+func cached(lang, title, part string, fetch func() (string, error)) (string, time.Time, error) {
+	path := cachePath(lang, title, part)
+	var entry cacheEntry
+	data, err := os.ReadFile(path)
+	hit := err == nil && json.Unmarshal(data, &entry) == nil
+	if hit && time.Since(entry.Fetched) < cacheTTL {
+		return entry.Text, entry.Fetched, nil
+	}
+
+	text, err := fetch()
+	if err != nil {
+		// A page that turned out to be ambiguous is no use stale either
+		if hit && mayReferTo(err) == nil {
+			log.Warn("Serving cached page", "lang", lang, "title", title, "part", part, "error", err)
+			return entry.Text, entry.Fetched, nil
+		}
+		return "", time.Time{}, err
+	}
+
+	entry = cacheEntry{Title: title, Lang: lang, Text: text, Fetched: time.Now()}
+	if data, err = json.Marshal(entry); err == nil {
+		if err = os.MkdirAll(filepath.Dir(path), 0o700); err == nil {
+			err = os.WriteFile(path, data, 0o600)
+		}
+	}
+	if err != nil {
+		log.Error("Could not cache page", "path", path, "error", err)
+	}
+	return text, time.Time{}, nil
+}
+
+// pruneCache deletes pages under dir fetched more than maxAge ago, then the
+// least recently fetched until what's left takes up at most maxBytes. Pages
+// are rewritten when they're fetched, so their modification time is when.
+func pruneCache(dir string, maxAge time.Duration, maxBytes int64) error {
+	type cacheFile struct {
+		path    string
+		size    int64
+		fetched time.Time
+	}
+	var files []cacheFile
+	var total int64
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil // removed since the walk started
+		}
+		if time.Since(info.ModTime()) > maxAge {
+			return os.Remove(path)
+		}
+		files = append(files, cacheFile{path, info.Size(), info.ModTime()})
+		total += info.Size()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	slices.SortFunc(files, func(a, b cacheFile) int { return a.fetched.Compare(b.fetched) })
+	for _, f := range files {
+		if total <= maxBytes {
+			break
+		}
+		if err := os.Remove(f.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		total -= f.size
+	}
+	return nil
+}
+
+// pruneCacheNow prunes the cache, logging what goes wrong.
+func pruneCacheNow() {
+	if err := pruneCache(filepath.Join(dataDir(), "cache"), cacheMaxAge, cacheMaxBytes); err != nil {
+		log.Error("Could not prune page cache", "error", err)
+	}
+}
+
+// isCached reports whether there's a copy of the lang Wikipedia's title page,
+// however old.
+func isCached(lang, title string) bool {
+	_, err := os.Stat(cachePath(lang, title, "summary"))
+	return err == nil
+}
+
+// cacheAge describes how long ago a cached page was fetched.
+func cacheAge(fetched time.Time) string {
+	switch age := time.Since(fetched); {
+	case age < 2*time.Minute:
+		return "cached just now"
+	case age < 2*time.Hour:
+		return fmt.Sprintf("cached %d minutes ago", int(age.Minutes()))
+	case age < 48*time.Hour:
+		return fmt.Sprintf("cached %d hours ago", int(age.Hours()))
+	default:
+		return fmt.Sprintf("cached %d days ago", int(age.Hours()/24))
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// useTempDataDir points dataDir at a directory of the test's own.
+func useTempDataDir(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("AppData", dir)
+}
+
+// writeCacheEntry caches text as part of the en Wikipedia's title page,
+// fetched at fetched.
+func writeCacheEntry(t *testing.T, title, part, text string, fetched time.Time) {
+	t.Helper()
+	path := cachePath("en", title, part)
+	data, err := json.Marshal(cacheEntry{Title: title, Lang: "en", Text: text, Fetched: fetched})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCached(t *testing.T) {
+	errOffline := errors.New("offline")
+	errAmbiguous := &ambiguousError{title: "Go", titles: []string{"Go (game)", "Go (programming language)"}}
+	tests := []struct {
+		name       string
+		entryAge   time.Duration // 0 for no cached copy
+		fetchErr   error
+		want       string
+		wantErr    error
+		wantFetch  bool
+		wantCached bool // the text came from the cache
+	}{
+		{"not cached", 0, nil, "fetched", nil, true, false},
+		{"fresh", time.Hour, nil, "cached", nil, false, true},
+		{"stale", 25 * time.Hour, nil, "fetched", nil, true, false},
+		{"stale and offline", 25 * time.Hour, errOffline, "cached", nil, true, true},
+		{"not cached and offline", 0, errOffline, "", errOffline, true, false},
+		{"stale and ambiguous", 25 * time.Hour, errAmbiguous, "", errAmbiguous, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTempDataDir(t)
+			if tt.entryAge != 0 {
+				writeCacheEntry(t, "Go", "summary", "cached", time.Now().Add(-tt.entryAge))
+			}
+			fetched := false
+			text, at, err := cached("en", "go", "summary", func() (string, error) {
+				fetched = true
+				if tt.fetchErr != nil {
+					return "", tt.fetchErr
+				}
+				return "fetched", nil
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("cached() error = %v, want %v", err, tt.wantErr)
+			}
+			if text != tt.want {
+				t.Errorf("cached() = %q, want %q", text, tt.want)
+			}
+			if fetched != tt.wantFetch {
+				t.Errorf("cached() fetched = %v, want %v", fetched, tt.wantFetch)
+			}
+			if at.IsZero() == tt.wantCached {
+				t.Errorf("cached() time = %v, want it zero only when fetched", at)
+			}
+		})
+	}
+}
+
+func TestCachedStoresFetch(t *testing.T) {
+	useTempDataDir(t)
+	if isCached("en", "Go") {
+		t.Fatal("isCached() = true before anything was cached")
+	}
+	if _, _, err := cached("en", "Go", "summary", func() (string, error) { return "fetched", nil }); err != nil {
+		t.Fatal(err)
+	}
+	// Titles are matched ignoring case
+	if !isCached("en", "go") {
+		t.Error("isCached() = false after caching the page")
+	}
+	text, at, err := cached("en", "GO", "summary", func() (string, error) {
+		t.Error("fetched a page that was just cached")
+		return "", nil
+	})
+	if err != nil || text != "fetched" || at.IsZero() {
+		t.Errorf("cached() = %q, %v, %v, want the copy cached by the first fetch", text, at, err)
+	}
+}
+
+func TestPruneCache(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	files := []struct {
+		name string
+		size int
+		age  time.Duration
+		kept bool
+	}{
+		{"en/new.summary.json", 10, time.Hour, true},
+		{"en/newer.content.json", 30, time.Minute, true},
+		{"de/old.content.json", 30, 2 * time.Hour, false},          // over the size cap
+		{"de/expired.summary.json", 1, 40 * 24 * time.Hour, false}, // over the age limit
+	}
+	for _, f := range files {
+		path := filepath.Join(dir, f.name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, make([]byte, f.size), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, now, now.Add(-f.age)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := pruneCache(dir, cacheMaxAge, 50); err != nil {
+		t.Fatalf("pruneCache() error = %v", err)
+	}
+	for _, f := range files {
+		_, err := os.Stat(filepath.Join(dir, f.name))
+		if kept := err == nil; kept != f.kept {
+			t.Errorf("pruneCache() kept %s = %v, want %v", f.name, kept, f.kept)
+		}
+	}
+}
+
+func TestPruneCacheMissing(t *testing.T) {
+	if err := pruneCache(filepath.Join(t.TempDir(), "cache"), cacheMaxAge, cacheMaxBytes); err != nil {
+		t.Errorf("pruneCache() of a cache that doesn't exist yet = %v, want nil", err)
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Articles can be exported as plain text or markdown, with :save in the app
+// or --dump from scripts.
+
+type savedMsg struct {
+	title string
+	path  string
+	err   error
+}
+
+// exportArticle formats an article for a file: markdown, or text wrapped to
+// 80 columns.
+func exportArticle(title, summary, content, format string) string {
+	var b strings.Builder
+	markdown := format == "markdown"
+	if markdown {
+		fmt.Fprintf(&b, "# %s\n\n%s\n", title, strings.TrimSpace(summary))
+	} else {
+		fmt.Fprintf(&b, "%s\n%s\n\n%s\n", title, strings.Repeat("=", len([]rune(title))), formatText(summary, 80))
+	}
+
+	var body []string
+	flush := func() {
+		if text := strings.TrimSpace(strings.Join(body, "\n")); text != "" {
+			if markdown {
+				b.WriteString("\n" + text + "\n")
+			} else {
+				b.WriteString("\n" + formatText(text, 80) + "\n")
+			}
+		}
+		body = nil
+	}
+	for _, line := range strings.Split(content, "\n") {
+		match := headingPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			body = append(body, line)
+			continue
+		}
+		flush()
+		if markdown {
+			// == is the first heading level under the title
+			fmt.Fprintf(&b, "\n%s %s\n", strings.Repeat("#", len(match[1])), match[2])
+		} else {
+			fmt.Fprintf(&b, "\n%s\n%s\n", match[2], strings.Repeat("-", len([]rune(match[2]))))
+		}
+	}
+	flush()
+	return b.String()
+}
+
+// fetchExport fetches the lang Wikipedia's title article and formats it.
+func fetchExport(title, lang, format string) (string, error) {
+	var summary, content string
+	var err error
+	inLanguage(lang, func() {
+		if summary, _, err = rawSummary(title, lang); err != nil {
+			return
+		}
+		content, _, err = rawContent(title, lang)
+	})
+	if err != nil {
+		return "", err
+	}
+	return exportArticle(title, summary, content, format), nil
+}
+
+// save handles :save, writing the last article read to path. Markdown is
+// used for .md files and plain text for anything else.
+func (m model) save(path string) (tea.Model, tea.Cmd) {
+	switch {
+	case !m.local:
+		m.status = "Saving only works when running locally, try the --dump flag"
+		return m, nil
+	case path == "":
+		m.status = "Usage: :save <path>, e.g. :save article.md"
+		return m, nil
+	case m.summary == "":
+		m.status = "Nothing to save yet, search for an article first"
+		return m, nil
+	}
+	format := "text"
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".md" || ext == ".markdown" {
+		format = "markdown"
+	}
+	title, lang := m.title, m.titleLang
+	return m, func() tea.Msg {
+		article, err := fetchExport(title, lang, format)
+		if err == nil {
+			err = os.WriteFile(path, []byte(article), 0o644)
+		}
+		return savedMsg{title: title, path: path, err: err}
+	}
+}
+
+// dumpArticle prints an article for --dump, returning the exit code.
+func dumpArticle(title, lang, format string) int {
+	article, err := fetchExport(title, lang, format)
+	if err != nil {
+		if titles := mayReferTo(err); len(titles) > 0 {
+			fmt.Fprintf(os.Stderr, "%s may refer to:\n  %s\n", title, strings.Join(titles, "\n  "))
+			return 1
+		}
+		fmt.Fprintln(os.Stderr, "Could not fetch the article:", err)
+		return 1
+	}
+	fmt.Print(article)
+	return 0
+}
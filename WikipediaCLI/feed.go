@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	gowiki "github.com/trietmn/go-wiki"
+)
+
+// :random and :today give users something to read without a query. Random
+// articles come from go-wiki, and today's from Wikipedia's feed API.
+
+const feedURL = "https://%s.wikipedia.org/api/rest_v1/feed/featured/%s"
+
+var wikimediaClient = &http.Client{Timeout: 10 * time.Second}
+
+type feedMsg struct {
+	items []articleItem
+	err   error
+}
+
+// featuredFeed is the part of the feed API's response that's used.
+type featuredFeed struct {
+	TFA *struct {
+		Titles struct {
+			Normalized string `json:"normalized"`
+		} `json:"titles"`
+	} `json:"tfa"`
+	OnThisDay []struct {
+		Text  string `json:"text"`
+		Year  int    `json:"year"`
+		Pages []struct {
+			Titles struct {
+				Normalized string `json:"normalized"`
+			} `json:"titles"`
+		} `json:"pages"`
+	} `json:"onthisday"`
+}
+
+// wikimediaGet fetches url from one of Wikimedia's APIs, failing unless the
+// response is OK.
+func wikimediaGet(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Wikimedia asks API clients to say who they are
+	req.Header.Set("User-Agent", "WikipediaCLI (https://github.com/ItsHotdogFred/CLIportfolio)")
+	resp, err := wikimediaClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s returned %s", req.URL.Host, resp.Status)
+	}
+	return resp, nil
+}
+
+// randomCmd picks a random article, which is then opened like a search with
+// only one result.
+func randomCmd(lang string) tea.Cmd {
+	return func() tea.Msg {
+		var titles []string
+		var err error
+		inLanguage(lang, func() {
+			titles, err = withTimeout(func() ([]string, error) { return gowiki.GetRandom(1) })
+		})
+		return resultsMsg{titles: titles, err: err}
+	}
+}
+
+// todayCmd fetches today's featured article and the articles about what
+// happened on this day.
+func todayCmd(lang string) tea.Cmd {
+	return func() tea.Msg {
+		resp, err := wikimediaGet(fmt.Sprintf(feedURL, lang, time.Now().UTC().Format("2006/01/02")))
+		if err != nil {
+			return feedMsg{err: err}
+		}
+		defer resp.Body.Close()
+
+		var feed featuredFeed
+		if err := json.NewDecoder(resp.Body).Decode(&feed); err != nil {
+			return feedMsg{err: err}
+		}
+		var items []articleItem
+		if feed.TFA != nil && feed.TFA.Titles.Normalized != "" {
+			items = append(items, articleItem{title: feed.TFA.Titles.Normalized, lang: lang, note: glyphs.featuredIcon + "Today's featured article"})
+		}
+		for _, event := range feed.OnThisDay {
+			if len(event.Pages) == 0 {
+				continue
+			}
+			items = append(items, articleItem{
+				title: event.Pages[0].Titles.Normalized,
+				lang:  lang,
+				note:  fmt.Sprintf("%d: %s", event.Year, event.Text),
+			})
+		}
+		return feedMsg{items: items}
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Wikipedia can be slow or briefly unreachable. Every request to it gives up
+// after fetchTimeout, and requests that fail like that are tried again a few
+// times, backing off in between, before the user is told.
+
+const (
+	fetchTimeout = 15 * time.Second
+	// fetchTries is how many times a request is made before giving up on it.
+	fetchTries = 3
+	// fetchBackoff is the wait before the first retry, doubled for each one
+	// after it.
+	fetchBackoff = 2 * time.Second
+)
+
+// withTimeout runs fetch, giving up on it after fetchTimeout. go-wiki can't
+// be cancelled, so a fetch that's given up on finishes in the background and
+// its result is thrown away.
+func withTimeout[T any](fetch func() (T, error)) (T, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+
+	type result struct {
+		value T
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		value, err := fetch()
+		done <- result{value, err}
+	}()
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-ctx.Done():
+		var zero T
+		return zero, fmt.Errorf("no answer from Wikipedia within %s: %w", fetchTimeout, ctx.Err())
+	}
+}
+
+// transient reports whether err is worth trying again after, meaning the
+// request timed out or didn't get through, rather than Wikipedia refusing it.
+func transient(err error) bool {
+	// Timeouts from withTimeout are net.Errors too
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// backoff is how long to wait before retry number try, counting from 1.
+func backoff(try int) time.Duration {
+	return fetchBackoff << (try - 1)
+}
+
+// after runs cmd once d has passed.
+func after(d time.Duration, cmd tea.Cmd) tea.Cmd {
+	return tea.Tick(d, func(time.Time) tea.Msg { return cmd() })
+}
+
+// fetchNext moves on to cmd, the next step of what the spinner is showing,
+// described by activity.
+func (m *model) fetchNext(activity string, cmd tea.Cmd) tea.Cmd {
+	m.activity = activity
+	m.fetch = cmd
+	m.tries = 0
+	m.since = time.Now()
+	return cmd
+}
+
+// retryFetch tries the step the spinner is showing again after a while, if
+// it failed with a transient error and there are tries left.
+func (m *model) retryFetch(err error) (tea.Cmd, bool) {
+	if !transient(err) || m.tries+1 >= fetchTries {
+		return nil, false
+	}
+	m.tries++
+	return after(backoff(m.tries), m.fetch), true
+}
+
+// progress describes how the step the spinner is showing is going, for long
+// or retried requests.
+func (m model) progress() string {
+	var details []string
+	if m.tries > 0 {
+		details = append(details, fmt.Sprintf("no answer, trying again: %d of %d", m.tries+1, fetchTries))
+	}
+	if elapsed := time.Since(m.since); elapsed >= 3*time.Second {
+		details = append(details, elapsed.Truncate(time.Second).String())
+	}
+	if len(details) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%s)", strings.Join(details, " "+glyphs.sep+" "))
+}
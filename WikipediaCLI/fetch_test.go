@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"timed out", fmt.Errorf("no answer from Wikipedia: %w", context.DeadlineExceeded), true},
+		{"unreachable", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, true},
+		{"refused", errors.New("unable to fetch the results"), false},
+		{"ambiguous", &ambiguousError{title: "Go"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := transient(tt.err); got != tt.want {
+				t.Errorf("transient(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for try, want := range map[int]time.Duration{1: fetchBackoff, 2: 2 * fetchBackoff, 3: 4 * fetchBackoff} {
+		if got := backoff(try); got != want {
+			t.Errorf("backoff(%d) = %s, want %s", try, got, want)
+		}
+	}
+}
+
+func TestRetryFetch(t *testing.T) {
+	m := newTestModel(t)
+	m.fetchNext("Searching", func() tea.Msg { return nil })
+	timeout := fmt.Errorf("no answer: %w", context.DeadlineExceeded)
+
+	if _, retried := m.retryFetch(errors.New("unable to fetch the results")); retried {
+		t.Error("retryFetch() retried an error that isn't transient")
+	}
+	for try := 1; try < fetchTries; try++ {
+		if cmd, retried := m.retryFetch(timeout); !retried || cmd == nil {
+			t.Fatalf("retryFetch() didn't retry try %d of %d", try, fetchTries)
+		}
+		if m.tries != try {
+			t.Errorf("tries = %d after retry %d", m.tries, try)
+		}
+	}
+	if _, retried := m.retryFetch(timeout); retried {
+		t.Errorf("retryFetch() retried after %d tries", fetchTries)
+	}
+
+	// Moving on to the next step starts counting again
+	m.fetchNext("Loading", func() tea.Msg { return nil })
+	if m.tries != 0 || m.activity != "Loading" {
+		t.Errorf("after fetchNext, tries = %d and activity = %q, want 0 and Loading", m.tries, m.activity)
+	}
+}
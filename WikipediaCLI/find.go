@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Pressing / in an article finds text in it. Matches are highlighted, and n
+// and N step through them.
+
+var (
+	ansiPattern = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+	matchStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#000000")).
+			Background(lipgloss.Color("#ffaa00"))
+
+	currentMatchStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#000000")).
+				Background(lipgloss.Color("205")).
+				Bold(true)
+)
+
+// match is where the find term was found, as byte offsets into a line of the
+// page with its styling removed.
+type match struct {
+	line       int
+	start, end int
+}
+
+func newFindInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "/"
+	ti.Placeholder = "find in article"
+	ti.CharLimit = 100
+	ti.Width = 30
+	return ti
+}
+
+// findKey handles the find keys while an article is shown, reporting whether
+// key was one of them.
+func (m *model) findKey(key string) bool {
+	switch key {
+	case "/":
+		m.finding = true
+		m.findInput.Reset()
+		m.findInput.Focus()
+		return true
+	case "n", "N":
+		if len(m.matches) == 0 {
+			return false
+		}
+		step := 1
+		if key == "N" {
+			step = len(m.matches) - 1
+		}
+		m.matchIndex = (m.matchIndex + step) % len(m.matches)
+		m.refreshPage()
+		m.showMatch()
+		return true
+	case "esc":
+		// Clear the highlights before leaving the article
+		if m.findTerm == "" {
+			return false
+		}
+		m.find("")
+		return true
+	}
+	return false
+}
+
+// updateFind handles keys while the find term is being typed.
+func (m model) updateFind(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		m.finding = false
+		m.findInput.Blur()
+		m.find(strings.TrimSpace(m.findInput.Value()))
+		return m, nil
+	case "esc":
+		m.finding = false
+		m.findInput.Blur()
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.findInput, cmd = m.findInput.Update(msg)
+	return m, cmd
+}
+
+// find highlights term in the page and scrolls to the first match on or
+// after the top of the viewport.
+func (m *model) find(term string) {
+	m.findTerm = term
+	m.findMatches()
+	m.matchIndex = 0
+	for i, mt := range m.matches {
+		if mt.line >= m.viewport.YOffset {
+			m.matchIndex = i
+			break
+		}
+	}
+	m.refreshPage()
+	m.showMatch()
+}
+
+// findMatches looks for the find term in the page, ignoring case.
+func (m *model) findMatches() {
+	m.matches = nil
+	if m.findTerm == "" {
+		return
+	}
+	pattern := regexp.MustCompile("(?i)" + regexp.QuoteMeta(m.findTerm))
+	for i, line := range strings.Split(m.pageContent(), "\n") {
+		for _, loc := range pattern.FindAllStringIndex(ansiPattern.ReplaceAllString(line, ""), -1) {
+			m.matches = append(m.matches, match{line: i, start: loc[0], end: loc[1]})
+		}
+	}
+	m.matchIndex = min(m.matchIndex, max(0, len(m.matches)-1))
+}
+
+// refreshPage puts the page in the viewport with the matches highlighted.
+// Lines with a match lose their own styling so the highlight can go in.
+func (m *model) refreshPage() {
+	if len(m.matches) == 0 {
+		m.viewport.SetContent(m.pageContent())
+		return
+	}
+	lines := strings.Split(m.pageContent(), "\n")
+	for i := 0; i < len(m.matches); {
+		n := m.matches[i].line
+		plain := ansiPattern.ReplaceAllString(lines[n], "")
+		var b strings.Builder
+		last := 0
+		for ; i < len(m.matches) && m.matches[i].line == n; i++ {
+			mt := m.matches[i]
+			style := matchStyle
+			if i == m.matchIndex {
+				style = currentMatchStyle
+			}
+			b.WriteString(plain[last:mt.start])
+			b.WriteString(style.Render(plain[mt.start:mt.end]))
+			last = mt.end
+		}
+		b.WriteString(plain[last:])
+		lines[n] = b.String()
+	}
+	m.viewport.SetContent(strings.Join(lines, "\n"))
+}
+
+// showMatch scrolls the current match into view if it's off screen.
+func (m *model) showMatch() {
+	if len(m.matches) == 0 {
+		return
+	}
+	line := m.matches[m.matchIndex].line
+	if line < m.viewport.YOffset || line >= m.viewport.YOffset+m.viewport.Height {
+		m.viewport.SetYOffset(max(0, line-m.viewport.Height/2))
+	}
+}
+
+// findStatus describes the matches for the footer.
+func (m model) findStatus() string {
+	if len(m.matches) == 0 {
+		return fmt.Sprintf("no matches for '%s'", m.findTerm)
+	}
+	return fmt.Sprintf("%d/%d '%s' | n/N next/prev", m.matchIndex+1, len(m.matches), m.findTerm)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// glyphSet holds every decorative character the UI draws, so terminals
+// without Unicode can get plain ASCII instead.
+type glyphSet struct {
+	border   lipgloss.Border // boxes around the header, footer and contents
+	teeRight string          // where the header box meets its rule
+	teeLeft  string          // where the footer rule meets its box
+	rule     string
+	dot      string // between the hints under the search box
+	sep      string // between details, e.g. a date and a language
+	arrows   string
+	ellipsis string
+	spinner  spinner.Spinner
+
+	// halfBlock draws images, which are left out without it
+	halfBlock string
+
+	// Icons, each with the space after it
+	searchIcon   string
+	summaryIcon  string
+	contentIcon  string
+	featuredIcon string
+	relatedIcon  string
+}
+
+var (
+	unicodeGlyphs = glyphSet{
+		border:       lipgloss.RoundedBorder(),
+		teeRight:     "├",
+		teeLeft:      "┤",
+		rule:         "─",
+		dot:          "•",
+		sep:          "·",
+		arrows:       "↑↓",
+		ellipsis:     "…",
+		spinner:      spinner.Dot,
+		halfBlock:    "▀",
+		searchIcon:   "🔍 ",
+		summaryIcon:  "📋 ",
+		contentIcon:  "📖 ",
+		relatedIcon:  "🔗 ",
+		featuredIcon: "⭐ ",
+	}
+
+	asciiGlyphs = glyphSet{
+		border:       lipgloss.ASCIIBorder(),
+		teeRight:     "+",
+		teeLeft:      "+",
+		rule:         "-",
+		dot:          "|",
+		sep:          "-",
+		arrows:       "up/down",
+		ellipsis:     "...",
+		spinner:      spinner.Line,
+		featuredIcon: "* ",
+	}
+
+	// glyphs is the set in use, picked at startup by useGlyphs.
+	glyphs = unicodeGlyphs
+)
+
+// unicodeTerminal guesses from the locale whether the terminal can show
+// Unicode. Without a locale set, it's assumed it can.
+func unicodeTerminal() bool {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			v = strings.ToLower(v)
+			return strings.Contains(v, "utf-8") || strings.Contains(v, "utf8")
+		}
+	}
+	return true
+}
+
+// useGlyphs switches to g, rebuilding the styles drawn with it.
+func useGlyphs(g glyphSet) {
+	glyphs = g
+
+	b := g.border
+	b.Right = g.teeRight
+	titleStyle = lipgloss.NewStyle().BorderStyle(b).Padding(0, 1)
+
+	b = g.border
+	b.Left = g.teeLeft
+	infoStyle = titleStyle.BorderStyle(b)
+
+	tocStyle = tocStyle.BorderStyle(g.border)
+}
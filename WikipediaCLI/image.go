@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/url"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+)
+
+// The lead image of an article is drawn above its summary in coloured half
+// blocks. It's fetched alongside the article, and left out without a fuss if
+// there isn't one, it can't be downloaded or it won't fit.
+
+const pageSummaryURL = "https://%s.wikipedia.org/api/rest_v1/page/summary/%s"
+
+const (
+	// thumbnailMaxWidth caps how wide the lead image is drawn, in columns.
+	thumbnailMaxWidth = 48
+	// thumbnailMinWidth is the narrowest the lead image is worth drawing.
+	thumbnailMinWidth = 12
+	// thumbnailMaxBytes stops a huge image from being downloaded.
+	thumbnailMaxBytes = 5 << 20
+)
+
+type thumbnailMsg struct {
+	tab   int // id of the tab the image is for
+	image image.Image
+	err   error
+}
+
+// thumbnailCmd downloads the thumbnail of the lang Wikipedia's title article.
+// A nil image means the article doesn't have one.
+func thumbnailCmd(id int, title, lang string) tea.Cmd {
+	return func() tea.Msg {
+		resp, err := wikimediaGet(fmt.Sprintf(pageSummaryURL, lang, url.PathEscape(strings.ReplaceAll(title, " ", "_"))))
+		if err != nil {
+			return thumbnailMsg{tab: id, err: err}
+		}
+		defer resp.Body.Close()
+		var summary struct {
+			Thumbnail *struct {
+				Source string `json:"source"`
+			} `json:"thumbnail"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&summary); err != nil {
+			return thumbnailMsg{tab: id, err: err}
+		}
+		if summary.Thumbnail == nil {
+			return thumbnailMsg{tab: id}
+		}
+
+		resp, err = wikimediaGet(summary.Thumbnail.Source)
+		if err != nil {
+			return thumbnailMsg{tab: id, err: err}
+		}
+		defer resp.Body.Close()
+		img, _, err := image.Decode(io.LimitReader(resp.Body, thumbnailMaxBytes))
+		return thumbnailMsg{tab: id, image: img, err: err}
+	}
+}
+
+// setThumbnail stores the lead image once it has been downloaded.
+func (t *tab) setThumbnail(msg thumbnailMsg) {
+	if msg.err != nil {
+		log.Debug("Could not fetch the lead image", "title", t.title, "lang", t.titleLang, "error", msg.err)
+		return
+	}
+	t.thumbnail = msg.image
+}
+
+// renderThumbnail draws img to fit in width columns and half of height
+// lines, returning "" if that's too small for it to be made out.
+func renderThumbnail(img image.Image, width, height int) string {
+	if img == nil || glyphs.halfBlock == "" {
+		return ""
+	}
+	bounds := img.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return ""
+	}
+	// Two pixel rows per line, so this keeps it to half the height
+	width = min(width, thumbnailMaxWidth, height*bounds.Dx()/bounds.Dy())
+	if width < thumbnailMinWidth {
+		return ""
+	}
+	return renderHalfBlocks(img, width)
+}
+
+// imageKey handles the key hiding and showing images while an article is
+// shown, reporting whether key was it.
+func (m *model) imageKey(key string) bool {
+	if key != "i" || m.thumbnailArt == "" {
+		return false
+	}
+	m.hideImages = !m.hideImages
+	m.findMatches()
+	m.refreshPage()
+	return true
+}
+
+// renderHalfBlocks draws img as rows of half block characters. Each character
+// shows two pixels: the top one in the foreground colour and the bottom one
+// in the background colour, which roughly squares up terminal cells.
+func renderHalfBlocks(img image.Image, width int) string {
+	bounds := img.Bounds()
+	width = max(1, min(width, bounds.Dx()))
+	// Two pixel rows per line, keeping the aspect ratio
+	height := max(2, bounds.Dy()*width/bounds.Dx())
+	height += height % 2
+
+	var b strings.Builder
+	for y := 0; y < height; y += 2 {
+		for x := 0; x < width; x++ {
+			top := averageColor(img, x, y, width, height)
+			bottom := averageColor(img, x, y+1, width, height)
+			fmt.Fprintf(&b, "\x1b[38;2;%d;%d;%dm\x1b[48;2;%d;%d;%dm%s", top.R, top.G, top.B, bottom.R, bottom.G, bottom.B, glyphs.halfBlock)
+		}
+		b.WriteString("\x1b[0m\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// averageColor returns the mean colour of the source pixels that map onto
+// cell (x, y) of a width x height grid. Transparent pixels blend to black.
+func averageColor(img image.Image, x, y, width, height int) color.RGBA {
+	bounds := img.Bounds()
+	x0 := bounds.Min.X + x*bounds.Dx()/width
+	x1 := max(x0+1, bounds.Min.X+(x+1)*bounds.Dx()/width)
+	y0 := bounds.Min.Y + y*bounds.Dy()/height
+	y1 := max(y0+1, bounds.Min.Y+(y+1)*bounds.Dy()/height)
+
+	var r, g, bl, n uint32
+	for py := y0; py < y1 && py < bounds.Max.Y; py++ {
+		for px := x0; px < x1 && px < bounds.Max.X; px++ {
+			// RGBA is alpha-premultiplied, so transparency fades to black for free
+			pr, pg, pb, _ := img.At(px, py).RGBA()
+			r, g, bl = r+pr>>8, g+pg>>8, bl+pb>>8
+			n++
+		}
+	}
+	if n == 0 {
+		return color.RGBA{}
+	}
+	return color.RGBA{R: uint8(r / n), G: uint8(g / n), B: uint8(bl / n), A: 255}
+}
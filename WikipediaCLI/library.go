@@ -0,0 +1,176 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/ssh"
+)
+
+// Every article a user reads is added to their history, and they can
+// bookmark the ones they want to keep. Both are saved in a library file per
+// SSH key, or per IP address for users without one, and in a local file
+// when running without the server.
+
+// historyMaxSize is the number of articles kept in a user's history.
+const historyMaxSize = 200
+
+// libraryMu is held while a library file is read and rewritten, so two
+// sessions with the same key can't drop each other's changes.
+var libraryMu sync.Mutex
+
+// visit is an article in the history or bookmarks.
+type visit struct {
+	Title string    `json:"title"`
+	Lang  string    `json:"lang"`
+	Time  time.Time `json:"time"`
+}
+
+type library struct {
+	History   []visit `json:"history"`
+	Bookmarks []visit `json:"bookmarks"`
+}
+
+// dataDir is where the Wikipedia CLI keeps its own state.
+func dataDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "wikipedia-cli")
+}
+
+// localLibraryPath is the library file used when running without the server.
+func localLibraryPath() string {
+	return filepath.Join(dataDir(), "local.json")
+}
+
+// sessionReader names the user of a session by their public key, falling
+// back to their IP address for keyboard-interactive logins.
+func sessionReader(s ssh.Session) string {
+	if key := s.PublicKey(); key != nil {
+		sum := sha256.Sum256(key.Marshal())
+		return "key-" + hex.EncodeToString(sum[:])
+	}
+	host, _, err := net.SplitHostPort(s.RemoteAddr().String())
+	if err != nil {
+		host = s.RemoteAddr().String()
+	}
+	// IPv6 addresses contain colons which aren't valid in file names everywhere
+	return "ip-" + strings.ReplaceAll(host, ":", "_")
+}
+
+// sessionLibraryPath keys the library file on the user of the session.
+func sessionLibraryPath(s ssh.Session) string {
+	return filepath.Join(dataDir(), "users", sessionReader(s)+".json")
+}
+
+// loadLibrary reads a library file, returning an empty library if it doesn't
+// exist yet.
+func loadLibrary(path string) library {
+	var lib library
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Error("Could not read library", "path", path, "error", err)
+		}
+		return lib
+	}
+	if err := json.Unmarshal(data, &lib); err != nil {
+		log.Error("Could not read library", "path", path, "error", err)
+	}
+	return lib
+}
+
+// updateLibrary applies change to the library file at path and saves it.
+func updateLibrary(path string, change func(*library)) error {
+	libraryMu.Lock()
+	defer libraryMu.Unlock()
+
+	lib := loadLibrary(path)
+	change(&lib)
+	data, err := json.MarshalIndent(lib, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// without returns visits without any for the same article as v.
+func without(visits []visit, v visit) []visit {
+	var kept []visit
+	for _, other := range visits {
+		if other.Title != v.Title || other.Lang != v.Lang {
+			kept = append(kept, other)
+		}
+	}
+	return kept
+}
+
+// recordVisitCmd adds an article to the history, moving it to the end if it
+// was read before.
+func recordVisitCmd(path string, v visit) tea.Cmd {
+	return func() tea.Msg {
+		err := updateLibrary(path, func(lib *library) {
+			lib.History = append(without(lib.History, v), v)
+			if len(lib.History) > historyMaxSize {
+				lib.History = lib.History[len(lib.History)-historyMaxSize:]
+			}
+		})
+		if err != nil {
+			log.Error("Could not save history", "path", path, "error", err)
+		}
+		return nil
+	}
+}
+
+// bookmark saves the article that was last read.
+func (m model) bookmark() (tea.Model, tea.Cmd) {
+	if m.summary == "" {
+		m.status = "Nothing to bookmark yet, search for an article first"
+		return m, nil
+	}
+	v := visit{Title: m.title, Lang: m.titleLang, Time: time.Now()}
+	err := updateLibrary(m.library, func(lib *library) {
+		lib.Bookmarks = append(without(lib.Bookmarks, v), v)
+	})
+	if err != nil {
+		log.Error("Could not save bookmark", "path", m.library, "error", err)
+		m.status = "Could not save the bookmark"
+		return m, nil
+	}
+	m.notice = fmt.Sprintf("Bookmarked '%s', see :bookmarks", v.Title)
+	return m, nil
+}
+
+// showVisits lists visits, newest first, to pick one to read again.
+func (m model) showVisits(heading, empty string, visits []visit) (tea.Model, tea.Cmd) {
+	if len(visits) == 0 {
+		m.notice = empty
+		return m, nil
+	}
+	items := make([]articleItem, 0, len(visits))
+	for i := len(visits) - 1; i >= 0; i-- {
+		v := visits[i]
+		items = append(items, articleItem{
+			title: v.Title,
+			lang:  v.Lang,
+			note:  v.Time.Local().Format("Jan 2 15:04") + " " + glyphs.sep + " " + v.Lang,
+		})
+	}
+	m.showResults(heading, items)
+	return m, nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestWithout(t *testing.T) {
+	goEn := visit{Title: "Go", Lang: "en"}
+	goDe := visit{Title: "Go", Lang: "de"}
+	rust := visit{Title: "Rust", Lang: "en"}
+	tests := []struct {
+		name   string
+		visits []visit
+		v      visit
+		want   []visit
+	}{
+		{"empty", nil, goEn, nil},
+		{"absent", []visit{rust}, goEn, []visit{rust}},
+		{"present", []visit{goEn, rust}, goEn, []visit{rust}},
+		{"more than once", []visit{goEn, rust, goEn}, goEn, []visit{rust}},
+		{"other edition kept", []visit{goDe, goEn}, goEn, []visit{goDe}},
+		{"time ignored", []visit{{Title: "Go", Lang: "en", Time: time.Unix(1, 0)}}, goEn, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := without(tt.visits, tt.v); !slices.Equal(got, tt.want) {
+				t.Errorf("without(%v, %v) = %v, want %v", tt.visits, tt.v, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordVisit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "library.json")
+	record := func(title string) {
+		t.Helper()
+		recordVisitCmd(path, visit{Title: title, Lang: "en", Time: time.Now()})()
+	}
+	titles := func() []string {
+		var titles []string
+		for _, v := range loadLibrary(path).History {
+			titles = append(titles, v.Title)
+		}
+		return titles
+	}
+
+	record("Go")
+	record("Rust")
+	record("Go")
+	if got, want := titles(), []string{"Rust", "Go"}; !slices.Equal(got, want) {
+		t.Errorf("history after reading Go again = %q, want %q", got, want)
+	}
+
+	for i := range historyMaxSize {
+		record(fmt.Sprint("Article ", i))
+	}
+	got := titles()
+	if len(got) != historyMaxSize {
+		t.Fatalf("history has %d articles, want %d", len(got), historyMaxSize)
+	}
+	if first, last := got[0], got[len(got)-1]; first != "Article 0" || last != fmt.Sprint("Article ", historyMaxSize-1) {
+		t.Errorf("history runs from %q to %q, want the oldest articles dropped", first, last)
+	}
+}
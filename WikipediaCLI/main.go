@@ -3,14 +3,15 @@ package main
 import (
 	"context"
 	"errors"
+	"flag"
 	"fmt"
-	"net"
 	"os"
 	"os/signal"
 	"strings"
 	"syscall"
 	"time"
 
+	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
@@ -22,21 +23,16 @@ import (
 	"github.com/charmbracelet/wish/activeterm"
 	"github.com/charmbracelet/wish/bubbletea"
 	"github.com/charmbracelet/wish/logging"
+	"github.com/charmbracelet/x/ansi"
 	gowiki "github.com/trietmn/go-wiki"
+	"github.com/trietmn/go-wiki/page"
+	gossh "golang.org/x/crypto/ssh"
 )
 
 var (
-	titleStyle = func() lipgloss.Style {
-		b := lipgloss.RoundedBorder()
-		b.Right = "├"
-		return lipgloss.NewStyle().BorderStyle(b).Padding(0, 1)
-	}()
-
-	infoStyle = func() lipgloss.Style {
-		b := lipgloss.RoundedBorder()
-		b.Left = "┤"
-		return titleStyle.BorderStyle(b)
-	}()
+	// Set by useGlyphs
+	titleStyle lipgloss.Style
+	infoStyle  lipgloss.Style
 
 	summaryTitleStyle = lipgloss.NewStyle().
 				Foreground(lipgloss.Color("12")).
@@ -63,40 +59,177 @@ var (
 			Foreground(lipgloss.Color("#ff0000")).
 			Bold(true).
 			Margin(1, 0)
+
+	noticeStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#00cc66")).
+			Margin(1, 0)
 )
 
-type searchResultMsg struct {
+// A search looks up matching titles first, letting the user pick one if the
+// query could mean several pages. The summary of the page is fetched next
+// and the full article after it, so the summary can be read while the rest
+// of a long page is still loading.
+
+type resultsMsg struct {
+	query  string
+	titles []string
+	err    error
+}
+
+// summaryMsg and contentMsg carry plain text, which is wrapped to fit the
+// viewport each time its width changes.
+type summaryMsg struct {
+	query   string
+	lang    string
+	summary string
+	cached  time.Time // when the summary was fetched, if it came from the cache
+	err     error
+}
+
+type contentMsg struct {
+	tab     int // id of the tab the article is for
 	content string
+	cached  time.Time
 	err     error
 }
 
-func searchCmd(query string) tea.Cmd {
+// ambiguousError is returned for a title that's a disambiguation page.
+type ambiguousError struct {
+	title  string
+	titles []string // the pages it may refer to
+}
+
+func (e *ambiguousError) Error() string {
+	return fmt.Sprintf("'%s' may refer to %d pages", e.title, len(e.titles))
+}
+
+// mayReferTo returns the pages a title may refer to if err says it's
+// ambiguous, and nil otherwise.
+func mayReferTo(err error) []string {
+	var ambiguous *ambiguousError
+	if errors.As(err, &ambiguous) {
+		return ambiguous.titles
+	}
+	return nil
+}
+
+// defaultLang is the Wikipedia language edition sessions start with.
+var defaultLang = "en"
+
+func searchCmd(query, lang string) tea.Cmd {
 	return func() tea.Msg {
-		content, err := search(query)
-		return searchResultMsg{content: content, err: err}
+		var titles []string
+		var err error
+		inLanguage(lang, func() {
+			titles, err = withTimeout(func() ([]string, error) {
+				titles, suggestion, err := gowiki.Search(query, 10, true)
+				// Nothing matched, so try the spelling Wikipedia suggests
+				if err == nil && len(titles) == 0 && suggestion != "" {
+					titles, _, err = gowiki.Search(suggestion, 10, false)
+				}
+				return titles, err
+			})
+		})
+		return resultsMsg{query: query, titles: titles, err: err}
+	}
+}
+
+func summaryCmd(query, lang string) tea.Cmd {
+	return func() tea.Msg {
+		var summary string
+		var fetched time.Time
+		var err error
+		inLanguage(lang, func() { summary, fetched, err = rawSummary(query, lang) })
+		return summaryMsg{query: query, lang: lang, summary: summary, cached: fetched, err: err}
+	}
+}
+
+func contentCmd(id int, query, lang string) tea.Cmd {
+	return func() tea.Msg {
+		var content string
+		var fetched time.Time
+		var err error
+		inLanguage(lang, func() { content, fetched, err = rawContent(query, lang) })
+		return contentMsg{tab: id, content: content, cached: fetched, err: err}
 	}
 }
 
 type model struct {
+	tab                // the article being read
+	tabs         []tab // all the open articles, including a stale copy of tab
+	current      int   // index of tab in tabs
+	nextTab      int   // id of the last tab opened
 	textinput    textinput.Model
-	viewport     viewport.Model
 	spinner      spinner.Model
 	query        string
+	lang         string // Wikipedia language edition, e.g. en or de
 	searching    bool
-	content      string
+	activity     string // what's being searched for, shown by the spinner
+	attempt      attempt
+	fetch        tea.Cmd   // the step of the attempt the spinner is showing
+	tries        int       // times fetch has been retried
+	since        time.Time // when fetch started
+	failed       bool      // the attempt failed, and the error is shown
+	failures     int       // counts failures, to tell which one an errorTimeoutMsg is for
+	findInput    textinput.Model
+	finding      bool // typing a term to find in the article
+	hideImages   bool
+	readable     bool // articles are shown in reading mode
+	results      list.Model
+	choosing     bool   // picking a page from results
+	status       string // an error to show under the search box
+	notice       string // anything else to show there
+	library      string // file the user's history and bookmarks are saved in
+	local        bool   // running in the user's own terminal rather than over SSH
+	statsSession int64  // metrics row for this session, 0 if not recorded
+	width        int
+	height       int
 	ready        bool
 	showViewport bool
 }
 
-const (
-	host = ""
-	port = "234"
-)
+// articleItem is an article in the results list.
+type articleItem struct {
+	title string
+	lang  string
+	note  string // shown under the title, e.g. when it was read
+}
+
+func (a articleItem) FilterValue() string { return a.title }
+func (a articleItem) Title() string       { return a.title }
+func (a articleItem) Description() string { return a.note }
+
+// articleItems lists titles from the lang Wikipedia.
+func articleItems(titles []string, lang string) []articleItem {
+	items := make([]articleItem, len(titles))
+	for i, t := range titles {
+		items[i] = articleItem{title: t, lang: lang}
+	}
+	return items
+}
+
+func startServer(addr string) {
+	// Every visitor's reading is cached, so keep it from filling the disk
+	go func() {
+		for {
+			pruneCacheNow()
+			time.Sleep(cachePruneEvery)
+		}
+	}()
+
+	if mt, err := openMetrics(metricsPath()); err != nil {
+		log.Error("Could not open metrics database", "error", err)
+	} else {
+		readerStats = mt
+	}
 
-func startServer() {
 	s, err := wish.NewServer(
-		wish.WithAddress(net.JoinHostPort(host, port)),
+		wish.WithAddress(addr),
 		wish.WithHostKeyPath(".ssh/id_ed25519"),
+		// Anyone can log in. Keys are only asked for so history and
+		// bookmarks can follow users between sessions.
+		wish.WithPublicKeyAuth(func(ssh.Context, ssh.PublicKey) bool { return true }),
+		wish.WithKeyboardInteractiveAuth(func(ssh.Context, gossh.KeyboardInteractiveChallenge) bool { return true }),
 		wish.WithMiddleware(
 			bubbletea.Middleware(teaHandler),
 			activeterm.Middleware(), // Bubble Tea apps usually require a PTY.
@@ -109,7 +242,7 @@ func startServer() {
 
 	done := make(chan os.Signal, 1)
 	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
-	log.Info("Starting SSH server", "host", host, "port", port)
+	log.Info("Starting SSH server", "address", addr)
 	go func() {
 		if err = s.ListenAndServe(); err != nil && !errors.Is(err, ssh.ErrServerClosed) {
 			log.Error("Could not start server", "error", err)
@@ -127,33 +260,75 @@ func startServer() {
 }
 
 func teaHandler(s ssh.Session) (tea.Model, []tea.ProgramOption) {
-
-	// Use the Bubble Tea renderer for SSH sessions
-	// renderer := bubbletea.MakeRenderer(s) // Not used, can be added for advanced styling
-
-	// Pass the renderer to the model if you want to use it for styling (optional)
-	m := initialModel()
-	// Optionally, you could set m.ready = true and m.showViewport = false to always start in search mode
+	m := initialModel(sessionLibraryPath(s))
+	if readerStats != nil {
+		m.statsSession = readerStats.startSession(sessionReader(s))
+		id, started := m.statsSession, time.Now()
+		go func() {
+			<-s.Context().Done()
+			if id != 0 {
+				readerStats.endSession(id, started)
+			}
+		}()
+	}
 	return m, []tea.ProgramOption{tea.WithAltScreen(), tea.WithMouseCellMotion()}
 }
 
 func main() {
-	// Allow running as SSH server with a flag
-	startServer()
-
-	// p := tea.NewProgram(
-	// 	initialModel(),
-	// 	tea.WithAltScreen(),       // use the full size of the terminal
-	// 	tea.WithMouseCellMotion(), // turn on mouse support for scrolling
-	// )
-	// if _, err := p.Run(); err != nil {
-	// 	fmt.Printf("Alas, there's been an error: %v", err)
-	// }
-}
-
-func initialModel() model {
-	printWikiLogo()
-	fmt.Println("Welcome to the Wikipedia CLI!")
+	flag.StringVar(&defaultLang, "lang", defaultLang, "Wikipedia language edition to search, e.g. de or fr")
+	flag.DurationVar(&cacheTTL, "cache-ttl", cacheTTL, "How long to use cached pages before fetching them again, 0 to always fetch")
+	dump := flag.String("dump", "", "Print the article with this title to stdout and exit")
+	format := flag.String("format", "text", "Format for --dump: text or markdown")
+	serve := flag.Bool("serve", false, "Run as an SSH server instead of in this terminal")
+	ascii := flag.Bool("ascii", false, "Draw with ASCII only, for terminals without Unicode")
+	addr := flag.String("addr", ":234", "Address the SSH server listens on")
+	stats := flag.Bool("stats", false, "Print the most popular queries and daily active readers recorded in server mode and exit")
+	flag.IntVar(&collapseLevel, "collapse-level", collapseLevel, "In reading mode, collapse sections with deeper headings than this, e.g. 3 for ==== and deeper, 0 for none")
+	flag.Parse()
+	if !validLang(defaultLang) {
+		log.Fatal("Invalid language", "lang", defaultLang)
+	}
+	if *format != "text" && *format != "markdown" {
+		log.Fatal("Invalid format, use text or markdown", "format", *format)
+	}
+	if collapseLevel != 0 && (collapseLevel < 2 || collapseLevel > 6) {
+		log.Fatal("Invalid collapse level, use 2 to 6 or 0", "collapse-level", collapseLevel)
+	}
+
+	// Over SSH the server's locale says nothing about the user's terminal
+	if *ascii || (!*serve && !unicodeTerminal()) {
+		useGlyphs(asciiGlyphs)
+	} else {
+		useGlyphs(unicodeGlyphs)
+	}
+
+	if *dump != "" {
+		os.Exit(dumpArticle(*dump, defaultLang, *format))
+	}
+	if *stats {
+		os.Exit(printStats())
+	}
+
+	if *serve {
+		startServer(*addr)
+		return
+	}
+
+	go pruneCacheNow()
+	m := initialModel(localLibraryPath())
+	m.local = true
+	p := tea.NewProgram(
+		m,
+		tea.WithAltScreen(),       // use the full size of the terminal
+		tea.WithMouseCellMotion(), // turn on mouse support for scrolling
+	)
+	if _, err := p.Run(); err != nil {
+		fmt.Printf("Alas, there's been an error: %v", err)
+		os.Exit(1)
+	}
+}
+
+func initialModel(library string) model {
 	ti := textinput.New()
 	ti.Placeholder = "Enter your search query (e.g., 'Python programming')"
 	ti.Focus()
@@ -167,67 +342,77 @@ func initialModel() model {
 
 	// Initialize spinner
 	s := spinner.New()
-	s.Spinner = spinner.Dot
+	s.Spinner = glyphs.spinner
 	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
 
 	return model{
 		textinput:    ti,
 		spinner:      s,
+		lang:         defaultLang,
+		findInput:    newFindInput(),
+		library:      library,
 		searching:    false,
-		content:      "",
 		ready:        false,
 		showViewport: false,
 	}
 }
 
-func printWikiLogo() {
-	fmt.Print(`
- _    _ _ _    _                _ _       
-| |  | (_) |  (_)              | (_)      
-| |  | |_| | ___ _ __   ___  __| |_  __ _ 
+// wikiLogo is shown above the search box.
+const wikiLogo = ` _    _ _ _    _                _ _
+| |  | (_) |  (_)              | (_)
+| |  | |_| | ___ _ __   ___  __| |_  __ _
 | |/\| | | |/ / | '_ \ / _ \/ _` + "`" + `| |/ _` + "`" + `| |
 \  /\  / |   <| | |_) |  __/ (_| | | (_| |
  \/  \/|_|_|\_\_| .__/ \___|\__,_|_|\__,_|
-				| |                       
-				|_|     
-
-`)
-}
-
-func search(query string) (string, error) {
-	// Search for the Wikipedia page title
-	search_result, err := gowiki.Summary(query, 5, -1, false, true)
-	if err != nil {
-		return errorStyle.Render("Error fetching summary: " + err.Error()), err
+                | |
+                |_|`
+
+// getPage looks up the page titled query. go-wiki returns disambiguation
+// pages like any other, so those come back as an ambiguousError.
+func getPage(query string) (page.WikipediaPage, error) {
+	p, err := gowiki.GetPage(query, -1, false, true)
+	if err == nil && len(p.Disambiguation) > 0 {
+		return p, &ambiguousError{title: query, titles: p.Disambiguation}
 	}
+	return p, err
+}
 
-	// Get the page
-	page, err := gowiki.GetPage(query, -1, false, true)
-	if err != nil {
-		return errorStyle.Render("Error fetching page: " + err.Error()), err
-	}
+// rawSummary returns the summary of a page as plain text. Like the other
+// fetches, it's called from inside inLanguage.
+func rawSummary(query, lang string) (string, time.Time, error) {
+	return cached(lang, query, "summary", func() (string, error) {
+		return withTimeout(func() (string, error) {
+			// go-wiki caches the lookup, so Summary doesn't repeat it
+			if _, err := getPage(query); err != nil {
+				return "", err
+			}
+			return gowiki.Summary(query, 5, -1, false, true)
+		})
+	})
+}
 
-	// Get the content of the page
-	content, err := page.GetContent()
-	if err != nil {
-		return errorStyle.Render("Error fetching content: " + err.Error()), err
-	}
+// rawContent returns the full text of a page, with its MediaWiki headings.
+func rawContent(query, lang string) (string, time.Time, error) {
+	return cached(lang, query, "content", func() (string, error) {
+		return withTimeout(func() (string, error) {
+			// Get the page
+			page, err := getPage(query)
+			if err != nil {
+				return "", err
+			}
+			// Get the content of the page
+			return page.GetContent()
+		})
+	})
+}
 
-	// Format the output beautifully
+// renderSummary formats the summary of a page, wrapped to width.
+func renderSummary(summary string, width int) string {
 	var result strings.Builder
-
-	// Add summary section
-	result.WriteString(summaryTitleStyle.Render("📋 SUMMARY"))
+	result.WriteString(summaryTitleStyle.Render(glyphs.summaryIcon + "SUMMARY"))
 	result.WriteString("\n")
-	result.WriteString(summaryContentStyle.Render(formatText(search_result, 80)))
-	result.WriteString("\n\n")
-
-	// Add content section
-	result.WriteString(contentTitleStyle.Render("📖 FULL CONTENT"))
-	result.WriteString("\n")
-	result.WriteString(contentStyle.Render(formatText(content, 80)))
-
-	return result.String(), nil
+	result.WriteString(summaryContentStyle.Render(formatText(summary, width)))
+	return result.String()
 }
 
 // formatText wraps text to specified width and adds proper spacing
@@ -249,26 +434,8 @@ func formatText(text string, width int) string {
 			formatted.WriteString("\n\n")
 		}
 
-		// Wrap the paragraph
-		words := strings.Fields(paragraph)
-		var line strings.Builder
-
-		for _, word := range words {
-			if line.Len()+len(word)+1 > width {
-				formatted.WriteString(line.String())
-				formatted.WriteString("\n")
-				line.Reset()
-			}
-
-			if line.Len() > 0 {
-				line.WriteString(" ")
-			}
-			line.WriteString(word)
-		}
-
-		if line.Len() > 0 {
-			formatted.WriteString(line.String())
-		}
+		// Wrap the paragraph, breaking words too long for a line
+		formatted.WriteString(ansi.Wrap(strings.Join(strings.Fields(paragraph), " "), width, ""))
 	}
 
 	return formatted.String()
@@ -284,10 +451,31 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.finding {
+			return m.updateFind(msg)
+		}
+		if ok, cmd := m.tabKey(msg.String()); ok {
+			return m, cmd
+		}
+		if ok, cmd := m.retryKey(msg.String()); ok {
+			return m, cmd
+		}
+		if m.showViewport && !m.choosing && (m.tocKey(msg.String()) || m.findKey(msg.String()) ||
+			m.imageKey(msg.String()) || m.relatedKey(msg.String()) || m.readableKey(msg.String())) {
+			return m, nil
+		}
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
 		case "esc":
+			if m.choosing {
+				// Go back to the article the list was opened from, if any
+				m.choosing = false
+				if !m.showViewport {
+					m.textinput.Focus()
+				}
+				return m, nil
+			}
 			if m.showViewport {
 				// Exit viewport mode and return to search
 				m.showViewport = false
@@ -296,62 +484,175 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, tea.Quit
 		case "enter":
-			if m.showViewport {
-				return m, nil
+			if m.choosing {
+				item, ok := m.results.SelectedItem().(articleItem)
+				if !ok {
+					return m, nil
+				}
+				m.choosing = false
+				m.query = item.title
+				m.lang = item.lang
+				return m, m.start(fmt.Sprintf("Opening '%s'", m.query), m.query, summaryCmd(m.query, m.lang))
 			}
-			if m.searching {
+			if m.showViewport || m.searching {
 				return m, nil
-			} else {
-				m.searching = true
-				m.query = m.textinput.Value()
-				m.textinput.Reset() // Reset the input after search
-
-				// Start the search command and spinner
-				return m, tea.Batch(searchCmd(m.query), m.spinner.Tick)
 			}
+			m.status, m.notice = "", ""
+			if value := strings.TrimSpace(m.textinput.Value()); strings.HasPrefix(value, ":") {
+				m.textinput.Reset()
+				return m.runCommand(value)
+			}
+			m.query = m.textinput.Value()
+			m.textinput.Reset() // Reset the input after search
+
+			// Start the search command and spinner
+			return m, tea.Batch(
+				m.start(fmt.Sprintf("Searching Wikipedia for '%s'", m.query), m.query, searchCmd(m.query, m.lang)),
+				recordQueryCmd(m.statsSession, m.query, m.lang),
+			)
 		}
 
-	case searchResultMsg:
+	case resultsMsg:
+		switch {
+		case msg.err != nil && isCached(m.lang, msg.query):
+			// Wikipedia can't be searched, but the page can be read offline
+			return m, m.fetchNext(fmt.Sprintf("Opening the saved copy of '%s'", msg.query), summaryCmd(msg.query, m.lang))
+		case msg.err != nil:
+			if cmd, ok := m.retryFetch(msg.err); ok {
+				return m, cmd
+			}
+			return m, m.fail("Could not search Wikipedia: " + msg.err.Error())
+		case len(msg.titles) == 0:
+			m.searching = false
+			m.status = fmt.Sprintf("No Wikipedia pages match '%s'", msg.query)
+			m.textinput.Focus()
+			return m, nil
+		case len(msg.titles) == 1 || strings.EqualFold(msg.titles[0], msg.query):
+			// Only one page it can be, so go straight to it
+			m.query = msg.titles[0]
+			return m, m.fetchNext(fmt.Sprintf("Found '%s', fetching its summary", m.query), summaryCmd(m.query, m.lang))
+		}
 		m.searching = false
+		m.showResults(fmt.Sprintf("Results for '%s'", msg.query), articleItems(msg.titles, m.lang))
+		return m, nil
+
+	case savedMsg:
 		if msg.err != nil {
+			m.status = "Could not save the article: " + msg.err.Error()
+		} else {
+			m.notice = fmt.Sprintf("Saved '%s' to %s", msg.title, msg.path)
+		}
+		return m, nil
+
+	case feedMsg:
+		if cmd, ok := m.retryFetch(msg.err); ok {
+			return m, cmd
+		}
+		m.searching = false
+		switch {
+		case msg.err != nil:
+			return m, m.fail("Could not fetch today's articles: " + msg.err.Error())
+		case len(msg.items) == 0:
+			m.notice = fmt.Sprintf("Nothing featured today on the '%s' Wikipedia", m.lang)
 			m.textinput.Focus()
+		default:
+			m.showResults("Today on Wikipedia", msg.items)
+		}
+		return m, nil
+
+	case summaryMsg:
+		if cmd, ok := m.retryFetch(msg.err); ok {
+			return m, cmd
+		}
+		m.searching = false
+		if titles := mayReferTo(msg.err); len(titles) > 0 {
+			m.showResults(fmt.Sprintf("'%s' may refer to", msg.query), articleItems(titles, msg.lang))
 			return m, nil
 		}
+		if msg.err != nil {
+			return m, m.fail("Could not fetch the page: " + msg.err.Error())
+		}
 
-		// Set up viewport with content and default size
-		m.content = msg.content
-		m.viewport = viewport.New(80, 24) // Default terminal size
-		m.viewport.SetContent(m.content)
+		// Show the summary straight away in a new tab and fetch the rest
+		// behind it
+		m.openTab(tab{
+			title:      msg.query,
+			titleLang:  msg.lang,
+			viewport:   viewport.New(80, 24), // Default terminal size
+			rawSummary: msg.summary,
+			cached:     msg.cached,
+			loading:    true,
+		})
+		m.textinput.Blur()
 		m.showViewport = true
 		m.ready = true
 
+		// The spinner is still ticking from the search
+		v := visit{Title: msg.query, Lang: msg.lang, Time: time.Now()}
+		return m, tea.Batch(
+			contentCmd(m.id, msg.query, msg.lang),
+			thumbnailCmd(m.id, msg.query, msg.lang),
+			recordVisitCmd(m.library, v),
+			recordViewCmd(m.statsSession, msg.query, msg.lang),
+		)
+
+	case contentMsg:
+		// Pages for closed tabs are dropped
+		t := m.tabByID(msg.tab)
+		if t == nil {
+			return m, nil
+		}
+		if transient(msg.err) && t.contentTries+1 < fetchTries {
+			t.contentTries++
+			return m, after(backoff(t.contentTries), contentCmd(t.id, t.title, t.titleLang))
+		}
+		t.setContent(msg)
+		m.refreshTab(t)
+		if msg.err != nil {
+			return m, nil
+		}
+		return m, relatedCmd(t.id, t.title, t.titleLang)
+
+	case relatedMsg:
+		if t := m.tabByID(msg.tab); t != nil {
+			t.setRelated(msg)
+			m.refreshTab(t)
+		}
+		return m, nil
+
+	case thumbnailMsg:
+		if t := m.tabByID(msg.tab); t != nil {
+			t.setThumbnail(msg)
+			m.refreshTab(t)
+		}
+		return m, nil
+
+	case errorTimeoutMsg:
+		if m.failed && msg.failure == m.failures {
+			return m, m.dismissFailure()
+		}
 		return m, nil
 
 	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		if m.choosing {
+			m.results.SetSize(msg.Width, msg.Height)
+		}
 		if m.showViewport {
-			headerHeight := lipgloss.Height(m.headerView())
-			footerHeight := lipgloss.Height(m.footerView())
-			verticalMarginHeight := headerHeight + footerHeight
-
-			if !m.ready {
-				m.viewport = viewport.New(msg.Width, msg.Height-verticalMarginHeight)
-				m.viewport.YPosition = headerHeight
-				m.viewport.SetContent(m.content)
-				m.ready = true
-			} else {
-				m.viewport.Width = msg.Width
-				m.viewport.Height = msg.Height - verticalMarginHeight
-			}
+			m.resizeViewport()
 		}
 	}
 
-	// Update spinner when searching
-	if m.searching {
+	// Update spinner when searching or loading the article
+	if m.searching || m.loading {
 		m.spinner, cmd = m.spinner.Update(msg)
 		cmds = append(cmds, cmd)
 	}
 
-	if m.showViewport && m.ready {
+	if m.choosing {
+		m.results, cmd = m.results.Update(msg)
+		cmds = append(cmds, cmd)
+	} else if m.showViewport && m.ready {
 		// Handle keyboard and mouse events in the viewport
 		m.viewport, cmd = m.viewport.Update(msg)
 		cmds = append(cmds, cmd)
@@ -363,35 +664,156 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// validLang reports whether lang looks like a Wikipedia language code, like
+// de, pt-br or simple.
+func validLang(lang string) bool {
+	if lang == "" || len(lang) > 12 {
+		return false
+	}
+	for _, r := range lang {
+		if (r < 'a' || r > 'z') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// setLang handles :lang, switching the session to another language edition
+// and searching it for the last query.
+func (m model) setLang(lang string) (tea.Model, tea.Cmd) {
+	lang = strings.ToLower(lang)
+	if lang == "" {
+		m.status = "Usage: :lang <code>, e.g. :lang de"
+		return m, nil
+	}
+	if !validLang(lang) {
+		m.status = fmt.Sprintf("'%s' is not a Wikipedia language code, try en, de or fr", lang)
+		return m, nil
+	}
+	m.lang = lang
+	m.status = ""
+	if m.query == "" {
+		return m, nil
+	}
+	activity := fmt.Sprintf("Searching the '%s' Wikipedia for '%s'", m.lang, m.query)
+	return m, m.start(activity, ":lang "+lang, searchCmd(m.query, m.lang))
+}
+
+// runCommand runs a line typed in the search box starting with a colon.
+func (m model) runCommand(line string) (tea.Model, tea.Cmd) {
+	name, args, _ := strings.Cut(line, " ")
+	switch name {
+	case ":lang":
+		return m.setLang(strings.TrimSpace(args))
+	case ":history":
+		return m.showVisits("History", "No articles read yet", loadLibrary(m.library).History)
+	case ":bookmark":
+		return m.bookmark()
+	case ":bookmarks":
+		return m.showVisits("Bookmarks", "No bookmarks yet, save the last article with :bookmark", loadLibrary(m.library).Bookmarks)
+	case ":save":
+		return m.save(strings.TrimSpace(args))
+	case ":random":
+		return m, m.start("Finding a random article", line, randomCmd(m.lang))
+	case ":today":
+		return m, m.start("Fetching today's articles", line, todayCmd(m.lang))
+	}
+	m.status = fmt.Sprintf("Unknown command %s, try :lang, :random, :today, :history, :bookmark, :bookmarks or :save", name)
+	return m, nil
+}
+
+// showResults lets the user pick one of items, titled title.
+func (m *model) showResults(title string, items []articleItem) {
+	listItems := make([]list.Item, len(items))
+	described := false
+	for i, item := range items {
+		listItems[i] = item
+		described = described || item.note != ""
+	}
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.BorderStyle(glyphs.border)
+	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.BorderStyle(glyphs.border)
+	delegate.ShowDescription = described
+	if !described {
+		delegate.SetSpacing(0)
+	}
+
+	width, height := m.width, m.height
+	if width == 0 {
+		width, height = 80, 24 // Default terminal size
+	}
+	m.results = list.New(listItems, delegate, width, height)
+	m.results.Title = title
+	m.results.SetFilteringEnabled(false)
+	m.results.DisableQuitKeybindings() // esc and q are handled in Update
+	m.choosing = true
+}
+
 func (m model) View() string {
+	if m.choosing {
+		return m.results.View()
+	}
 	if m.showViewport {
 		if !m.ready {
 			return "\n  Loading Wikipedia content..."
 		}
-		return fmt.Sprintf("%s\n%s\n%s", m.headerView(), m.viewport.View(), m.footerView())
+		page := m.viewport.View()
+		if m.tocOpen {
+			page = lipgloss.JoinHorizontal(lipgloss.Top, m.tocView(), page)
+		}
+		return fmt.Sprintf("%s\n%s\n%s", m.headerView(), page, m.footerView())
 	}
 
 	// Style the search interface
 	searchTitle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("12")).
 		Bold(true).
-		Render("🔍 Wikipedia Search")
+		Render(glyphs.searchIcon + "Wikipedia Search")
 
 	var instructions string
 	if m.searching {
 		instructions = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#ffaa00")).
 			Bold(true).
-			Render(fmt.Sprintf("%s Searching Wikipedia for '%s'...", m.spinner.View(), m.query))
+			Render(fmt.Sprintf("%s %s...%s", m.spinner.View(), m.activity, m.progress()))
+	} else if m.failed {
+		instructions = errorStyle.
+			Border(glyphs.border).
+			Padding(0, 1).
+			Render(m.status) + "\n" + lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#888888")).
+			Italic(true).
+			Render("(r to retry "+glyphs.dot+" Esc to edit the search)")
 	} else {
 		instructions = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#888888")).
 			Italic(true).
-			Render("(Enter to search • Esc to quit)")
+			Render("(" + strings.Join([]string{
+				"Enter to search",
+				fmt.Sprintf(":lang to switch from '%s'", m.lang),
+				":random", ":today", ":history", ":bookmarks",
+				"Esc to quit",
+			}, " "+glyphs.dot+" ") + ")")
+		if len(m.tabs) > 0 {
+			instructions += "\n" + lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#888888")).
+				Italic(true).
+				Render("(Tab to go back to your articles, searches open in new tabs)")
+		}
+		if m.status != "" {
+			instructions = errorStyle.Render(m.status) + "\n" + instructions
+		} else if m.notice != "" {
+			instructions = noticeStyle.Render(m.notice) + "\n" + instructions
+		}
 	}
 
+	logo := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("12")).
+		Render(wikiLogo)
+
 	return fmt.Sprintf(
-		"%s\n\n%s\n\n%s",
+		"%s\n\n%s\n\n%s\n\n%s",
+		logo,
 		searchTitle,
 		m.textinput.View(),
 		instructions,
@@ -400,21 +822,148 @@ func (m model) View() string {
 
 func (m model) headerView() string {
 	var title string
-	if m.query != "" {
-		title = titleStyle.Render(fmt.Sprintf("Wikipedia: %s", m.query))
+	if m.title != "" {
+		heading := fmt.Sprintf("Wikipedia (%s): %s", m.titleLang, m.title)
+		if !m.cached.IsZero() {
+			heading += " " + glyphs.sep + " " + cacheAge(m.cached)
+		}
+		if m.readable {
+			heading += " " + glyphs.sep + " reading mode"
+		}
+		title = titleStyle.Render(heading)
 	} else {
-		title = titleStyle.Render("Wikipedia CLI")
+		title = titleStyle.Render(fmt.Sprintf("Wikipedia CLI (%s)", m.lang))
+	}
+	line := strings.Repeat(glyphs.rule, max(0, m.pageWidth()-lipgloss.Width(title)))
+	header := lipgloss.JoinHorizontal(lipgloss.Center, title, line)
+	if len(m.tabs) > 1 {
+		return m.tabsView() + "\n" + header
 	}
-	line := strings.Repeat("─", max(0, m.viewport.Width-lipgloss.Width(title)))
-	return lipgloss.JoinHorizontal(lipgloss.Center, title, line)
+	return header
 }
 
 func (m model) footerView() string {
-	info := infoStyle.Render(fmt.Sprintf("%3.f%% | ↑↓ scroll | ESC return to search | q quit", m.viewport.ScrollPercent()*100))
-	line := strings.Repeat("─", max(0, m.viewport.Width-lipgloss.Width(info)))
+	status := fmt.Sprintf("%3.f%%", m.viewport.ScrollPercent()*100)
+	if m.loading {
+		status = m.spinner.View() + " fetched summary, fetching full text"
+		if m.contentTries > 0 {
+			status += fmt.Sprintf(" (no answer, trying again: %d of %d)", m.contentTries+1, fetchTries)
+		}
+	}
+	// Articles opened from this one are fetched behind it
+	if m.searching {
+		status = m.spinner.View() + " " + m.activity + m.progress()
+	} else if m.failed {
+		status = m.status
+	}
+	if len(m.sections) > 0 {
+		status += " | t contents"
+	}
+	if m.thumbnailArt != "" {
+		status += " | i image"
+	}
+	if len(m.related) > 0 {
+		status += " | r related"
+	}
+	if m.rawContent != "" {
+		status += " | c clean"
+	}
+	if len(m.tabs) > 1 {
+		status += " | tab next tab | ctrl+w close"
+	}
+	if m.findTerm != "" {
+		status += " | " + m.findStatus()
+	}
+	info := infoStyle.Render(status + " | / find | " + glyphs.arrows + " scroll | ESC return to search | q quit")
+	if m.finding {
+		info = infoStyle.Render(m.findInput.View() + " | enter find | esc cancel")
+	}
+	line := strings.Repeat(glyphs.rule, max(0, m.pageWidth()-lipgloss.Width(info)))
 	return lipgloss.JoinHorizontal(lipgloss.Center, line, info)
 }
 
+// resizeViewport fits the viewport to the terminal, next to the table of
+// contents if it's open.
+func (m *model) resizeViewport() {
+	width, height := m.width, m.height
+	if width == 0 {
+		width, height = 80, 24 // Default terminal size
+	}
+	headerHeight := lipgloss.Height(m.headerView())
+	footerHeight := lipgloss.Height(m.footerView())
+	if m.tocOpen {
+		width -= tocWidth + 1 // and its border
+	}
+	m.viewport.Width = max(0, width)
+	m.viewport.Height = max(0, height-headerHeight-footerHeight)
+	m.viewport.YPosition = headerHeight
+
+	// Leave room for the padding of the text styles
+	if wrap := max(20, m.viewport.Width-3); wrap != m.wrapWidth {
+		m.wrapWidth = wrap
+		m.reflow()
+	}
+}
+
+// reflow wraps the page to wrapWidth, keeping the reader's place in it.
+func (m *model) reflow() {
+	if m.rawSummary == "" {
+		return
+	}
+	lines := strings.Count(m.pageContent(), "\n") + 1
+	offset := m.viewport.YOffset
+
+	m.thumbnailArt = renderThumbnail(m.thumbnail, m.wrapWidth, m.viewport.Height)
+	m.summary = renderSummary(m.rawSummary, m.wrapWidth)
+	m.relatedText = renderRelated(m.related)
+	switch {
+	case m.contentErr != nil:
+		m.content = errorStyle.Render("Error fetching content: " + m.contentErr.Error())
+		m.sections = nil
+	case m.rawContent != "" && m.readable:
+		m.content, m.sections = renderArticle(readable(m.rawContent), m.wrapWidth)
+	case m.rawContent != "":
+		m.content, m.sections = renderArticle(m.rawContent, m.wrapWidth)
+	default:
+		m.content, m.sections = "", nil
+	}
+	m.tocCursor = min(m.tocCursor, max(0, len(m.sections)-1))
+
+	m.findMatches()
+	m.refreshPage()
+	m.viewport.SetYOffset(offset * (strings.Count(m.pageContent(), "\n") + 1) / lines)
+}
+
+// pageWidth is the width of the viewport and the table of contents together.
+func (m model) pageWidth() int {
+	if m.tocOpen {
+		return m.viewport.Width + tocWidth + 1
+	}
+	return m.viewport.Width
+}
+
+// pageContent is what the viewport shows: the lead image unless images are
+// hidden, the summary, and the full article and related articles once
+// they've loaded.
+func (m model) pageContent() string {
+	page := m.pageTop()
+	if m.content != "" {
+		page += "\n\n" + m.content
+	}
+	if m.relatedText != "" {
+		page += "\n\n" + m.relatedText
+	}
+	return page
+}
+
+// pageTop is the part of the page above the full article.
+func (m model) pageTop() string {
+	if m.thumbnailArt != "" && !m.hideImages {
+		return m.thumbnailArt + "\n" + m.summary
+	}
+	return m.summary
+}
+
 func max(a, b int) int {
 	if a > b {
 		return a
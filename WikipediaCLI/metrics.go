@@ -0,0 +1,224 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	_ "modernc.org/sqlite"
+)
+
+// In server mode, sessions, searches and the articles read are recorded in
+// SQLite, and --stats prints what's popular. Readers are only recorded as a
+// keyed hash of their SSH key or address, so they can be counted without
+// being identified.
+
+const metricsSchema = `
+CREATE TABLE IF NOT EXISTS sessions (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	reader      TEXT NOT NULL,
+	started_at  INTEGER NOT NULL,
+	duration_ms INTEGER
+);
+CREATE TABLE IF NOT EXISTS queries (
+	session_id  INTEGER NOT NULL REFERENCES sessions(id),
+	query       TEXT NOT NULL,
+	lang        TEXT NOT NULL,
+	searched_at INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS views (
+	session_id INTEGER NOT NULL REFERENCES sessions(id),
+	title      TEXT NOT NULL,
+	lang       TEXT NOT NULL,
+	viewed_at  INTEGER NOT NULL
+);`
+
+// metrics stores what readers do in SQLite.
+type metrics struct {
+	db     *sql.DB
+	secret []byte // keys the hashes standing in for readers
+}
+
+// readerStats is the metrics store for the running server, nil when running
+// locally or the database couldn't be opened.
+var readerStats *metrics
+
+func metricsPath() string {
+	return filepath.Join(dataDir(), "metrics.db")
+}
+
+func openMetrics(path string) (*metrics, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	secret, err := metricsSecret(filepath.Join(filepath.Dir(path), "metrics.key"))
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite only allows one writer, so don't let concurrent sessions fight over it
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(metricsSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &metrics{db: db, secret: secret}, nil
+}
+
+// metricsSecret reads the key for hashing readers from path, creating it the
+// first time. It never leaves the server, so the hashes can't be matched to
+// keys or addresses without it.
+func metricsSecret(path string) ([]byte, error) {
+	secret, err := os.ReadFile(path)
+	if err == nil {
+		return secret, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+	secret = make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, secret); err != nil {
+		return nil, err
+	}
+	return secret, os.WriteFile(path, secret, 0o600)
+}
+
+// anonymize returns the hash recorded for reader.
+func (mt *metrics) anonymize(reader string) string {
+	mac := hmac.New(sha256.New, mt.secret)
+	mac.Write([]byte(reader))
+	return hex.EncodeToString(mac.Sum(nil))[:16]
+}
+
+// startSession records a new session for reader and returns its row id.
+func (mt *metrics) startSession(reader string) int64 {
+	res, err := mt.db.Exec("INSERT INTO sessions (reader, started_at) VALUES (?, ?)", mt.anonymize(reader), time.Now().Unix())
+	if err != nil {
+		log.Error("Could not record session", "error", err)
+		return 0
+	}
+	id, _ := res.LastInsertId()
+	return id
+}
+
+// endSession stores how long the session lasted.
+func (mt *metrics) endSession(id int64, started time.Time) {
+	if _, err := mt.db.Exec("UPDATE sessions SET duration_ms = ? WHERE id = ?", time.Since(started).Milliseconds(), id); err != nil {
+		log.Error("Could not record session end", "error", err)
+	}
+}
+
+// recordQueryCmd stores a search, lowercased so the same query is counted
+// together however it was typed.
+func recordQueryCmd(session int64, query, lang string) tea.Cmd {
+	if readerStats == nil || session == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		query = strings.ToLower(strings.Join(strings.Fields(query), " "))
+		if _, err := readerStats.db.Exec("INSERT INTO queries (session_id, query, lang, searched_at) VALUES (?, ?, ?, ?)", session, query, lang, time.Now().Unix()); err != nil {
+			log.Error("Could not record query", "error", err)
+		}
+		return nil
+	}
+}
+
+// recordViewCmd stores that an article was opened.
+func recordViewCmd(session int64, title, lang string) tea.Cmd {
+	if readerStats == nil || session == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		if _, err := readerStats.db.Exec("INSERT INTO views (session_id, title, lang, viewed_at) VALUES (?, ?, ?, ?)", session, title, lang, time.Now().Unix()); err != nil {
+			log.Error("Could not record view", "error", err)
+		}
+		return nil
+	}
+}
+
+// report renders the overview printed by --stats.
+func (mt *metrics) report() (string, error) {
+	var b strings.Builder
+
+	var sessions, readers, views int
+	var avgDuration sql.NullFloat64
+	row := mt.db.QueryRow("SELECT COUNT(*), COUNT(DISTINCT reader), AVG(duration_ms) FROM sessions")
+	if err := row.Scan(&sessions, &readers, &avgDuration); err != nil {
+		return "", err
+	}
+	if err := mt.db.QueryRow("SELECT COUNT(*) FROM views").Scan(&views); err != nil {
+		return "", err
+	}
+	b.WriteString("Sessions\n")
+	fmt.Fprintf(&b, "  Sessions:          %d\n", sessions)
+	fmt.Fprintf(&b, "  Readers:           %d\n", readers)
+	fmt.Fprintf(&b, "  Articles read:     %d\n", views)
+	fmt.Fprintf(&b, "  Average duration:  %s\n", (time.Duration(avgDuration.Float64) * time.Millisecond).Round(time.Second))
+
+	sections := []struct {
+		heading string
+		query   string
+	}{
+		{"Most popular queries", "SELECT query || ' (' || lang || ')', COUNT(*) AS n FROM queries GROUP BY query, lang ORDER BY n DESC LIMIT 10"},
+		{"Most read articles", "SELECT title || ' (' || lang || ')', COUNT(*) AS n FROM views GROUP BY title, lang ORDER BY n DESC LIMIT 10"},
+		{"Daily active readers (UTC)", "SELECT date(started_at, 'unixepoch') AS day, COUNT(DISTINCT reader) FROM sessions GROUP BY day ORDER BY day DESC LIMIT 14"},
+	}
+	for _, section := range sections {
+		b.WriteString("\n" + section.heading + "\n")
+		rows, err := mt.db.Query(section.query)
+		if err != nil {
+			return "", err
+		}
+		for rows.Next() {
+			var name string
+			var n int
+			if err := rows.Scan(&name, &n); err != nil {
+				rows.Close()
+				return "", err
+			}
+			fmt.Fprintf(&b, "  %-40s %d\n", name, n)
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return "", err
+		}
+		rows.Close()
+	}
+	return b.String(), nil
+}
+
+// printStats prints the report for --stats, returning the exit code.
+func printStats() int {
+	path := metricsPath()
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		fmt.Fprintln(os.Stderr, "Nothing recorded yet, metrics are only kept in server mode")
+		return 1
+	}
+	mt, err := openMetrics(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Could not open the metrics database:", err)
+		return 1
+	}
+	defer mt.db.Close()
+	report, err := mt.report()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Could not read the metrics:", err)
+		return 1
+	}
+	fmt.Print(report)
+	return 0
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestAnonymize(t *testing.T) {
+	mt := &metrics{secret: []byte("secret")}
+	reader := "ip-192.0.2.1"
+	got := mt.anonymize(reader)
+	if !regexp.MustCompile(`^[0-9a-f]{16}$`).MatchString(got) {
+		t.Errorf("anonymize(%q) = %q, want 16 hex digits", reader, got)
+	}
+	if again := mt.anonymize(reader); again != got {
+		t.Errorf("anonymize(%q) = %q then %q, want the same hash each time", reader, got, again)
+	}
+	if other := mt.anonymize("ip-192.0.2.2"); other == got {
+		t.Errorf("anonymize() = %q for two readers, want different hashes", got)
+	}
+	// Without the secret, the hash can't be matched to the reader
+	if other := (&metrics{secret: []byte("other")}).anonymize(reader); other == got {
+		t.Errorf("anonymize(%q) = %q with two secrets, want different hashes", reader, got)
+	}
+}
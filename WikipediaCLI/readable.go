@@ -0,0 +1,109 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Pressing c switches to reading mode, which cleans an article up for the
+// terminal: reference markers, leftovers of infoboxes and formulas, and the
+// sections at the end listing sources and links are all dropped. Deep
+// subsections can be collapsed to their headings too, with --collapse-level.
+
+// collapseLevel is the deepest heading level whose sections are shown in
+// reading mode, counting == as 2. Deeper sections keep just their heading.
+// 0 shows every section.
+var collapseLevel = 0
+
+// referencePattern matches markers like [12], [a], [note 3] and
+// [citation needed].
+var referencePattern = regexp.MustCompile(`\[(?:\d+|[a-z]|note \d+|[a-z][a-z ]* needed|who\?|when\?|by whom\?|according to whom\?)\]`)
+
+// cruftSections are the sections reading mode drops, by lowercased title.
+var cruftSections = map[string]bool{
+	"see also":        true,
+	"notes":           true,
+	"footnotes":       true,
+	"references":      true,
+	"citations":       true,
+	"sources":         true,
+	"bibliography":    true,
+	"further reading": true,
+	"external links":  true,
+}
+
+// readable cleans up the plain text of an article for reading mode.
+func readable(text string) string {
+	var out []string
+	dropping := 0 // level of the heading of a section being dropped
+	collapsing := false
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if match := headingPattern.FindStringSubmatch(trimmed); match != nil {
+			level := len(match[1])
+			if dropping > 0 && level > dropping {
+				continue // a subsection of a dropped section
+			}
+			dropping = 0
+			if cruftSections[strings.ToLower(match[2])] {
+				dropping = level
+				continue
+			}
+			collapsing = collapseLevel > 0 && level > collapseLevel
+			out = append(out, line)
+			continue
+		}
+		if dropping > 0 || collapsing {
+			continue
+		}
+
+		// Infobox and template rows that weren't rendered
+		if strings.HasPrefix(trimmed, "{{") || strings.HasPrefix(trimmed, "}}") || strings.HasPrefix(trimmed, "|") {
+			continue
+		}
+		line = referencePattern.ReplaceAllString(line, "")
+		line = stripFormulas(line)
+		// Keep at most one blank line in a row
+		if strings.TrimSpace(line) == "" && len(out) > 0 && strings.TrimSpace(out[len(out)-1]) == "" {
+			continue
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
+
+// stripFormulas removes the TeX that formulas leave behind in plain text,
+// like {\displaystyle x^{2}}, which can't be matched with a regexp as the
+// braces nest.
+func stripFormulas(line string) string {
+	for {
+		start := strings.Index(line, "{\\displaystyle")
+		if start < 0 {
+			return line
+		}
+		depth, end := 0, len(line)
+		for i := start; i < len(line); i++ {
+			if line[i] == '{' {
+				depth++
+			} else if line[i] == '}' {
+				depth--
+				if depth == 0 {
+					end = i + 1
+					break
+				}
+			}
+		}
+		line = line[:start] + line[end:]
+	}
+}
+
+// readableKey handles the key switching reading mode on and off while an
+// article is shown, reporting whether key was it.
+func (m *model) readableKey(key string) bool {
+	if key != "c" || m.rawContent == "" {
+		return false
+	}
+	m.readable = !m.readable
+	m.reflow()
+	return true
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/log"
+)
+
+// Once an article has loaded, pages like it are listed at the bottom, and r
+// picks one to open in a new tab. They come from Wikipedia's search, which
+// can find pages that are "more like" another.
+
+const searchAPIURL = "https://%s.wikipedia.org/w/api.php?%s"
+
+// relatedMaxCount is the number of related articles listed.
+const relatedMaxCount = 8
+
+var relatedNumberStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("#888888"))
+
+type relatedMsg struct {
+	tab    int // id of the tab the articles are for
+	titles []string
+	err    error
+}
+
+// relatedCmd finds articles like the lang Wikipedia's title article.
+func relatedCmd(id int, title, lang string) tea.Cmd {
+	return func() tea.Msg {
+		query := url.Values{
+			"action":      {"query"},
+			"list":        {"search"},
+			"srsearch":    {"morelike:" + title},
+			"srnamespace": {"0"},
+			"srlimit":     {fmt.Sprint(relatedMaxCount)},
+			"srprop":      {""},
+			"format":      {"json"},
+		}
+		resp, err := wikimediaGet(fmt.Sprintf(searchAPIURL, lang, query.Encode()))
+		if err != nil {
+			return relatedMsg{tab: id, err: err}
+		}
+		defer resp.Body.Close()
+		var results struct {
+			Query struct {
+				Search []struct {
+					Title string `json:"title"`
+				} `json:"search"`
+			} `json:"query"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+			return relatedMsg{tab: id, err: err}
+		}
+		titles := make([]string, 0, len(results.Query.Search))
+		for _, r := range results.Query.Search {
+			titles = append(titles, r.Title)
+		}
+		return relatedMsg{tab: id, titles: titles}
+	}
+}
+
+// setRelated stores the related articles once they've been found.
+func (t *tab) setRelated(msg relatedMsg) {
+	if msg.err != nil {
+		log.Debug("Could not find related articles", "title", t.title, "lang", t.titleLang, "error", msg.err)
+		return
+	}
+	t.related = msg.titles
+}
+
+// renderRelated lists the related articles, numbered, for the bottom of the
+// page. It's "" without any.
+func renderRelated(titles []string) string {
+	if len(titles) == 0 {
+		return ""
+	}
+	var out strings.Builder
+	out.WriteString(contentTitleStyle.Render(glyphs.relatedIcon + "RELATED"))
+	for i, title := range titles {
+		out.WriteString("\n")
+		out.WriteString(contentStyle.Render(relatedNumberStyle.Render(fmt.Sprintf("%d.", i+1)) + " " + title))
+	}
+	return out.String()
+}
+
+// relatedKey handles the key listing the related articles to open one,
+// reporting whether key was it.
+func (m *model) relatedKey(key string) bool {
+	if key != "r" || len(m.related) == 0 {
+		return false
+	}
+	m.showResults(fmt.Sprintf("Related to '%s'", m.title), articleItems(m.related, m.titleLang))
+	return true
+}
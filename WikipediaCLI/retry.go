@@ -0,0 +1,78 @@
+package main
+
+import (
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// When a search or anything else fetched from the search box fails, the
+// error takes over the search box until the user retries it, goes back to
+// editing what they typed, or it times out.
+
+// errorTimeout is how long a failure is shown before the search box comes
+// back by itself.
+const errorTimeout = 15 * time.Second
+
+// errorTimeoutMsg dismisses the failure it was sent for, if it's still shown.
+type errorTimeoutMsg struct {
+	failure int
+}
+
+// attempt is something fetched from the search box, kept so it can be
+// retried.
+type attempt struct {
+	activity string
+	input    string // what was typed for it, put back to be edited
+	cmd      tea.Cmd
+}
+
+// start runs cmd to do activity behind the spinner. input is what was typed
+// in the search box to start it.
+func (m *model) start(activity, input string, cmd tea.Cmd) tea.Cmd {
+	m.searching = true
+	m.attempt = attempt{activity: activity, input: input, cmd: cmd}
+	return tea.Batch(m.fetchNext(activity, cmd), m.spinner.Tick)
+}
+
+// fail shows message for the last thing started, offering to retry it.
+func (m *model) fail(message string) tea.Cmd {
+	m.searching = false
+	m.status = message
+	m.failed = true
+	m.failures++
+	m.textinput.Blur()
+	failure := m.failures
+	return tea.Tick(errorTimeout, func(time.Time) tea.Msg {
+		return errorTimeoutMsg{failure: failure}
+	})
+}
+
+// dismissFailure goes back to the search box with what was typed for the
+// attempt that failed.
+func (m *model) dismissFailure() tea.Cmd {
+	m.failed = false
+	m.status = ""
+	m.textinput.SetValue(m.attempt.input)
+	m.textinput.CursorEnd()
+	m.textinput.Focus()
+	return textinput.Blink
+}
+
+// retryKey handles the keys while a failure is shown, reporting whether key
+// was one of them.
+func (m *model) retryKey(key string) (bool, tea.Cmd) {
+	if !m.failed || m.showViewport || m.choosing {
+		return false, nil
+	}
+	switch key {
+	case "r", "enter":
+		m.failed = false
+		m.status = ""
+		return true, m.start(m.attempt.activity, m.attempt.input, m.attempt.cmd)
+	case "esc":
+		return true, m.dismissFailure()
+	}
+	return false, nil
+}
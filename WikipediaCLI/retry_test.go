@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFailAndRetry(t *testing.T) {
+	m := newTestModel(t)
+	m.start("Searching for Go", "Go", func() tea.Msg { return nil })
+	m.fail("Wikipedia can't be reached")
+	if !m.failed || m.searching || m.textinput.Focused() {
+		t.Fatalf("after fail, failed = %v, searching = %v, focused = %v", m.failed, m.searching, m.textinput.Focused())
+	}
+	failure := m.failures
+
+	if handled, _ := m.retryKey("x"); handled {
+		t.Error("retryKey(x) handled")
+	}
+	handled, retry := m.retryKey("r")
+	if !handled || retry == nil {
+		t.Fatal("retryKey(r) didn't retry")
+	}
+	if m.failed || !m.searching || m.status != "" || m.attempt.input != "Go" {
+		t.Errorf("after retrying, failed = %v, searching = %v, status = %q, input = %q", m.failed, m.searching, m.status, m.attempt.input)
+	}
+
+	// Failing again and going back to edit what was typed
+	m.fail("Wikipedia can't be reached")
+	if m.failures != failure+1 {
+		t.Errorf("failures = %d, want %d", m.failures, failure+1)
+	}
+	m.textinput.SetValue("")
+	if handled, _ := m.retryKey("esc"); !handled {
+		t.Fatal("retryKey(esc) not handled")
+	}
+	if m.failed || m.textinput.Value() != "Go" || !m.textinput.Focused() {
+		t.Errorf("after esc, failed = %v, input = %q, focused = %v, want the search box back with Go", m.failed, m.textinput.Value(), m.textinput.Focused())
+	}
+	if handled, _ := m.retryKey("r"); handled {
+		t.Error("retryKey(r) handled with no failure shown")
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Every article opens in a tab of its own, so a few can be kept open and
+// compared. The model embeds the tab being read and keeps the rest in tabs,
+// where articles carry on loading in the background.
+
+// tabMaxCount is the number of tabs a session can have open. Opening another
+// closes the oldest.
+const tabMaxCount = 9
+
+// tabTitleWidth is how much of an article's title its tab shows, in columns.
+const tabTitleWidth = 20
+
+var (
+	tabStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#888888")).
+			Padding(0, 1)
+
+	activeTabStyle = tabStyle.
+			Foreground(lipgloss.Color("205")).
+			Bold(true)
+)
+
+// tab is an open article and where the user is in it.
+type tab struct {
+	id           int
+	title        string
+	titleLang    string // language edition the article is from
+	viewport     viewport.Model
+	rawSummary   string // the page as it was fetched, before wrapping
+	rawContent   string
+	contentErr   error
+	wrapWidth    int    // width the page is wrapped to
+	summary      string // the page wrapped to fit the viewport
+	content      string
+	loading      bool      // the full article is still being fetched
+	cached       time.Time // when the oldest cached part of the page was fetched
+	sections     []section
+	tocOpen      bool
+	tocCursor    int
+	findTerm     string
+	matches      []match
+	matchIndex   int
+	thumbnail    image.Image // the article's lead image, if it has one
+	thumbnailArt string      // the lead image drawn to fit the viewport
+	contentTries int         // times fetching the full article has been retried
+	related      []string    // titles of articles like this one
+	relatedText  string      // the related articles, listed for the page
+}
+
+// setContent stores the full article once it has been fetched.
+func (t *tab) setContent(msg contentMsg) {
+	t.loading = false
+	t.rawContent = msg.content
+	t.contentErr = msg.err
+	if !msg.cached.IsZero() && (t.cached.IsZero() || msg.cached.Before(t.cached)) {
+		t.cached = msg.cached
+	}
+}
+
+// tabByID returns the open tab with id, or nil if it has been closed.
+func (m *model) tabByID(id int) *tab {
+	if len(m.tabs) > 0 && m.tab.id == id {
+		return &m.tab
+	}
+	for i := range m.tabs {
+		if i != m.current && m.tabs[i].id == id {
+			return &m.tabs[i]
+		}
+	}
+	return nil
+}
+
+// refreshTab shows a change to t. Tabs in the background are wrapped when
+// they're next shown instead.
+func (m *model) refreshTab(t *tab) {
+	if t == &m.tab {
+		m.reflow()
+	} else {
+		t.wrapWidth = 0
+	}
+}
+
+// openTab opens t after the other tabs and switches to it.
+func (m *model) openTab(t tab) {
+	if len(m.tabs) > 0 {
+		m.tabs[m.current] = m.tab
+	}
+	if len(m.tabs) >= tabMaxCount {
+		m.tabs = m.tabs[len(m.tabs)-tabMaxCount+1:]
+	}
+	m.nextTab++
+	t.id = m.nextTab
+	m.tabs = append(m.tabs, t)
+	m.current = len(m.tabs) - 1
+	m.tab = t
+	m.resizeViewport()
+}
+
+// switchTab puts tab i in front, returning a command to start the spinner
+// if it's still loading.
+func (m *model) switchTab(i int) tea.Cmd {
+	m.tabs[m.current] = m.tab
+	m.current = i
+	m.tab = m.tabs[i]
+	// The window may have changed size, or images been hidden, since the
+	// tab was last shown
+	m.wrapWidth = 0
+	m.resizeViewport()
+	if m.loading {
+		return m.spinner.Tick
+	}
+	return nil
+}
+
+// closeTab closes the tab being read, going back to the search box after the
+// last one.
+func (m *model) closeTab() tea.Cmd {
+	m.tabs = append(m.tabs[:m.current], m.tabs[m.current+1:]...)
+	if len(m.tabs) == 0 {
+		m.tab = tab{}
+		m.current = 0
+		m.showViewport = false
+		m.textinput.Focus()
+		return textinput.Blink
+	}
+	m.current = min(m.current, len(m.tabs)-1)
+	m.tab = m.tabs[m.current]
+	m.wrapWidth = 0
+	m.resizeViewport()
+	if m.loading {
+		return m.spinner.Tick
+	}
+	return nil
+}
+
+// tabKey handles the keys for tabs, reporting whether key was one of them.
+// In the search box, tab goes back to the articles.
+func (m *model) tabKey(key string) (bool, tea.Cmd) {
+	if len(m.tabs) == 0 || m.choosing {
+		return false, nil
+	}
+	if !m.showViewport {
+		if key != "tab" {
+			return false, nil
+		}
+		m.showViewport = true
+		m.textinput.Blur()
+		return true, m.switchTab(m.current)
+	}
+	switch key {
+	case "tab":
+		return true, m.switchTab((m.current + 1) % len(m.tabs))
+	case "shift+tab":
+		return true, m.switchTab((m.current + len(m.tabs) - 1) % len(m.tabs))
+	case "ctrl+t":
+		// The next article searched for opens in a new tab
+		m.showViewport = false
+		m.textinput.Focus()
+		return true, textinput.Blink
+	case "ctrl+w":
+		return true, m.closeTab()
+	}
+	return false, nil
+}
+
+// tabsView draws a tab for each open article, numbered and with the one
+// being read highlighted.
+func (m model) tabsView() string {
+	tabs := make([]string, len(m.tabs))
+	for i, t := range m.tabs {
+		style := tabStyle
+		if i == m.current {
+			style = activeTabStyle
+		}
+		tabs[i] = style.Render(ansi.Truncate(fmt.Sprintf("%d %s", i+1, t.title), tabTitleWidth, glyphs.ellipsis))
+	}
+	return ansi.Truncate(strings.Join(tabs, glyphs.sep), m.pageWidth(), glyphs.ellipsis)
+}
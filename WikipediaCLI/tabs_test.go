@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+// newTestModel returns a model with nowhere shared to save its library.
+func newTestModel(t *testing.T) model {
+	t.Helper()
+	useGlyphs(asciiGlyphs)
+	return initialModel(filepath.Join(t.TempDir(), "library.json"))
+}
+
+// tabTitles lists the titles of m's tabs, with the one being read taken
+// from m.tab as the copy in m.tabs may be stale.
+func tabTitles(m model) []string {
+	titles := make([]string, len(m.tabs))
+	for i, t := range m.tabs {
+		titles[i] = t.title
+	}
+	if len(titles) > 0 {
+		titles[m.current] = m.tab.title
+	}
+	return titles
+}
+
+func TestOpenTab(t *testing.T) {
+	m := newTestModel(t)
+	for i := range tabMaxCount + 2 {
+		m.openTab(tab{title: fmt.Sprint("Article ", i)})
+	}
+	titles := tabTitles(m)
+	if len(titles) != tabMaxCount {
+		t.Fatalf("%d tabs open, want %d", len(titles), tabMaxCount)
+	}
+	if titles[0] != "Article 2" {
+		t.Errorf("first tab = %q, want the oldest two closed", titles[0])
+	}
+	if m.current != tabMaxCount-1 || m.tab.title != fmt.Sprint("Article ", tabMaxCount+1) {
+		t.Errorf("reading tab %d, %q, want the last one opened", m.current, m.tab.title)
+	}
+	if m.tab.id != tabMaxCount+2 {
+		t.Errorf("last tab id = %d, want %d", m.tab.id, tabMaxCount+2)
+	}
+}
+
+func TestTabByID(t *testing.T) {
+	m := newTestModel(t)
+	if m.tabByID(0) != nil {
+		t.Error("tabByID(0) with no tabs open is not nil")
+	}
+	m.openTab(tab{title: "Go"})
+	first := m.tab.id
+	m.openTab(tab{title: "Rust"})
+
+	if got := m.tabByID(m.tab.id); got != &m.tab {
+		t.Error("tabByID() of the tab being read doesn't return m.tab")
+	}
+	if got := m.tabByID(first); got == nil || got.title != "Go" {
+		t.Errorf("tabByID(%d) = %v, want the Go tab", first, got)
+	}
+	m.closeTab()
+	if got := m.tabByID(first + 1); got != nil {
+		t.Errorf("tabByID() of a closed tab = %v, want nil", got)
+	}
+}
+
+func TestTabKeys(t *testing.T) {
+	m := newTestModel(t)
+	for _, title := range []string{"Go", "Rust", "Zig"} {
+		m.openTab(tab{title: title})
+	}
+	m.showViewport = true
+
+	steps := []struct {
+		key     string
+		want    string // title of the tab being read after key
+		wantLen int
+	}{
+		{"tab", "Go", 3},
+		{"shift+tab", "Zig", 3},
+		{"shift+tab", "Rust", 3},
+		{"ctrl+w", "Zig", 2},
+		{"ctrl+w", "Go", 1},
+		{"ctrl+w", "", 0},
+	}
+	for _, step := range steps {
+		if handled, _ := m.tabKey(step.key); !handled {
+			t.Fatalf("tabKey(%q) not handled", step.key)
+		}
+		if m.tab.title != step.want || len(m.tabs) != step.wantLen {
+			t.Fatalf("after %q, reading %q of %d tabs, want %q of %d", step.key, m.tab.title, len(m.tabs), step.want, step.wantLen)
+		}
+	}
+	if m.showViewport || !m.textinput.Focused() {
+		t.Error("closing the last tab doesn't go back to the search box")
+	}
+}
+
+func TestTabKeySwitchesBack(t *testing.T) {
+	m := newTestModel(t)
+	m.openTab(tab{title: "Go"})
+	m.openTab(tab{title: "Rust"})
+	m.tab.findTerm = "borrow"
+	m.switchTab(0)
+	m.switchTab(1)
+	if m.tab.findTerm != "borrow" {
+		t.Errorf("findTerm = %q after switching back, want the tab's state kept", m.tab.findTerm)
+	}
+}
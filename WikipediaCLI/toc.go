@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Articles come back as plain text with MediaWiki headings like
+// "== History ==" in them. They're split into sections so the headings can
+// be styled and listed in a table of contents the viewport can jump around.
+
+// tocWidth is how wide the table of contents pane is, in columns.
+const tocWidth = 30
+
+var (
+	headingPattern = regexp.MustCompile(`^(={2,6})\s*(.+?)\s*={2,6}$`)
+
+	sectionStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("12")).
+			Bold(true).
+			Underline(true)
+
+	subsectionStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("14")).
+			Bold(true).
+			PaddingLeft(1)
+
+	tocStyle = lipgloss.NewStyle().
+			Width(tocWidth).
+			BorderStyle(lipgloss.NormalBorder()).
+			BorderRight(true)
+
+	tocSelectedStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("205")).
+				Bold(true)
+)
+
+// section is a heading in the article.
+type section struct {
+	title  string
+	number string // position in the table of contents, e.g. 2 or 2.1
+	depth  int    // 0 for top level sections
+	line   int    // line of the rendered article the heading is on
+}
+
+// renderArticle formats the plain text of an article wrapped to width,
+// returning it with the sections in it.
+func renderArticle(text string, width int) (string, []section) {
+	var out strings.Builder
+	var sections []section
+	var body []string
+	var counters []int
+
+	out.WriteString(contentTitleStyle.Render(glyphs.contentIcon + "FULL CONTENT"))
+	out.WriteString("\n")
+
+	flush := func() {
+		paragraphs := strings.TrimSpace(strings.Join(body, "\n"))
+		body = nil
+		if paragraphs == "" {
+			return
+		}
+		out.WriteString(contentStyle.Render(formatText(paragraphs, width)))
+		out.WriteString("\n")
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		match := headingPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			body = append(body, line)
+			continue
+		}
+		flush()
+
+		// == is the top level, === under it and so on
+		depth := len(match[1]) - 2
+		for len(counters) <= depth {
+			counters = append(counters, 0)
+		}
+		counters[depth]++
+		counters = counters[:depth+1]
+		numbers := make([]string, len(counters))
+		for i, n := range counters {
+			numbers[i] = fmt.Sprint(n)
+		}
+
+		out.WriteString("\n")
+		sections = append(sections, section{
+			title:  match[2],
+			number: strings.Join(numbers, "."),
+			depth:  depth,
+			line:   strings.Count(out.String(), "\n"),
+		})
+		style := sectionStyle
+		if depth > 0 {
+			style = subsectionStyle
+		}
+		out.WriteString(style.Render(match[2]))
+		out.WriteString("\n")
+	}
+	flush()
+
+	if strings.TrimSpace(text) == "" {
+		out.WriteString(contentStyle.Render(formatText(text, width)))
+	}
+	return strings.TrimSuffix(out.String(), "\n"), sections
+}
+
+// tocKey handles the keys for the table of contents while an article is
+// shown, reporting whether key was one of them.
+func (m *model) tocKey(key string) bool {
+	if len(m.sections) == 0 {
+		return false
+	}
+	switch key {
+	case "t":
+		m.tocOpen = !m.tocOpen
+		m.resizeViewport()
+		return true
+	case "esc":
+		if !m.tocOpen {
+			return false
+		}
+		m.tocOpen = false
+		m.resizeViewport()
+		return true
+	case "up", "k":
+		if !m.tocOpen {
+			return false
+		}
+		m.tocCursor = max(0, m.tocCursor-1)
+		return true
+	case "down", "j":
+		if !m.tocOpen {
+			return false
+		}
+		m.tocCursor = min(len(m.sections)-1, m.tocCursor+1)
+		return true
+	case "enter":
+		if !m.tocOpen {
+			return false
+		}
+		m.jumpToSection(m.tocCursor)
+		return true
+	}
+
+	// Numbers jump to the top level sections
+	if len(key) == 1 && key >= "1" && key <= "9" {
+		for i, s := range m.sections {
+			if s.number == key {
+				m.jumpToSection(i)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jumpToSection scrolls the viewport to put section i at the top.
+func (m *model) jumpToSection(i int) {
+	m.tocCursor = i
+	// The article comes after the image and summary, and a blank line
+	offset := strings.Count(m.pageTop(), "\n") + 2
+	m.viewport.SetYOffset(offset + m.sections[i].line)
+}
+
+// tocView draws the table of contents, scrolled to keep the cursor in view.
+func (m model) tocView() string {
+	height := max(1, m.viewport.Height)
+	first := max(0, min(m.tocCursor-height/2, len(m.sections)-height))
+
+	var lines []string
+	for i := first; i < len(m.sections) && i < first+height; i++ {
+		s := m.sections[i]
+		line := strings.Repeat("  ", s.depth) + s.number + " " + s.title
+		if entry := []rune(line); len(entry) > tocWidth-1 {
+			ellipsis := []rune(glyphs.ellipsis)
+			line = string(entry[:tocWidth-1-len(ellipsis)]) + glyphs.ellipsis
+		}
+		if i == m.tocCursor {
+			line = tocSelectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return tocStyle.Height(height).Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sync"
+
+	"github.com/trietmn/go-wiki/models"
+	"github.com/trietmn/go-wiki/utils"
+)
+
+// go-wiki keeps the language it asks Wikipedia in a global, and its own
+// requests aren't safe to make from several goroutines at once. Sessions
+// reading the same edition fetch side by side, through requestWiki, while a
+// session that wants another edition waits for them to finish and then
+// switches it.
+
+// wiki is the edition go-wiki's requests currently go to.
+var wiki struct {
+	sync.Mutex
+	changed *sync.Cond // broadcast when the last fetch in lang finishes
+	lang    string
+	active  int // fetches running in lang
+	waiting int // fetches queued to switch lang, or behind those that are
+}
+
+// wikiCacheMu guards go-wiki's cache of API responses.
+var wikiCacheMu sync.Mutex
+
+func init() {
+	wiki.changed = sync.NewCond(&wiki.Mutex)
+	wiki.lang = defaultLang
+	utils.WikiRequester = requestWiki
+}
+
+// inLanguage runs fetch with go-wiki asking the lang Wikipedia. A request
+// that timed out can still be running after fetch returns, but whatever it
+// gets back is thrown away.
+func inLanguage(lang string, fetch func()) {
+	wiki.Lock()
+	if wiki.waiting > 0 || wiki.active > 0 && wiki.lang != lang {
+		// Queue behind anyone already waiting, so a busy edition can't keep
+		// the others out
+		wiki.waiting++
+		wiki.changed.Wait()
+		for wiki.active > 0 && wiki.lang != lang {
+			wiki.changed.Wait()
+		}
+		wiki.waiting--
+	}
+	wiki.lang = lang
+	wiki.active++
+	wiki.Unlock()
+
+	defer func() {
+		wiki.Lock()
+		wiki.active--
+		if wiki.active == 0 {
+			wiki.changed.Broadcast()
+		}
+		wiki.Unlock()
+	}()
+	fetch()
+}
+
+// requestWiki makes a request to the Wikipedia API in the current edition.
+// It stands in for go-wiki's own, which shares its rate limiting and cache
+// between goroutines without locking them.
+func requestWiki(args map[string]string) (models.RequestResult, error) {
+	wiki.Lock()
+	lang := wiki.lang
+	wiki.Unlock()
+
+	query := url.Values{"format": {"json"}, "action": {"query"}}
+	for k, v := range args {
+		// Some flags, like exintro, are sent empty
+		if v != "" || query.Get(k) == "" {
+			query.Set(k, v)
+		}
+	}
+	// Responses are cached by URL, so editions don't mix
+	u := fmt.Sprintf(utils.WikiURL, lang) + "?" + query.Encode()
+	wikiCacheMu.Lock()
+	result, err := utils.Cache.Get(u)
+	wikiCacheMu.Unlock()
+	if err == nil {
+		return result, nil
+	}
+
+	resp, err := wikimediaGet(u)
+	if err != nil {
+		return models.RequestResult{}, err
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return models.RequestResult{}, err
+	}
+	wikiCacheMu.Lock()
+	utils.Cache.Add(u, result)
+	wikiCacheMu.Unlock()
+	return result, nil
+}